@@ -0,0 +1,40 @@
+-- ============================================================================
+-- MIGRACIÓN: Capacidad y ocupación de albergues, con historial y encargados
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Camas declaradas y último reporte de ocupación. ocupacion_actual NULL = nadie
+-- ha reportado; camas_disponibles se calcula en el servidor.
+ALTER TABLE puntos ADD COLUMN IF NOT EXISTS capacidad_total INTEGER;
+ALTER TABLE puntos ADD COLUMN IF NOT EXISTS ocupacion_actual INTEGER;
+ALTER TABLE puntos ADD COLUMN IF NOT EXISTS ocupacion_actualizada TIMESTAMP;
+
+-- Cada reporte de ocupación (POST /api/admin/puntos/{id}/ocupacion)
+CREATE TABLE IF NOT EXISTS ocupacion_historial (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    ocupacion INTEGER NOT NULL,
+    capacidad_total INTEGER,  -- Capacidad vigente al momento del reporte
+    nota TEXT,
+    reportado_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW()
+);
+
+-- Historial de un albergue: WHERE punto_id = $1 ORDER BY created DESC
+CREATE INDEX IF NOT EXISTS idx_ocupacion_historial_punto_created ON ocupacion_historial(punto_id, created DESC);
+
+-- Rol encargado: reporta la ocupación solo de los puntos que tiene asignados
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_rol_check;
+ALTER TABLE users ADD CONSTRAINT users_rol_check
+    CHECK (rol IN ('superadmin', 'admin', 'verificador', 'encargado', 'usuario'));
+
+CREATE TABLE IF NOT EXISTS punto_encargados (
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    user_id TEXT NOT NULL REFERENCES users(id),
+    created TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (punto_id, user_id)
+);
+
+-- Puntos de un encargado: WHERE user_id = $1
+CREATE INDEX IF NOT EXISTS idx_punto_encargados_user ON punto_encargados(user_id);
@@ -0,0 +1,30 @@
+-- ============================================================================
+-- MIGRACIÓN: Compromisos de donación a los puntos
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Lo que un donante promete llevar a un punto ("20 litros de agua el martes").
+-- categoria e item usan el formato del cruce de necesidades (necesidades_tags);
+-- nombre es lo que escribió el donante.
+CREATE TABLE IF NOT EXISTS compromisos (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    categoria TEXT NOT NULL DEFAULT '',
+    item TEXT NOT NULL DEFAULT '',
+    nombre TEXT NOT NULL,
+    cantidad NUMERIC(12, 2) NOT NULL CHECK(cantidad > 0),
+    unidad TEXT NOT NULL,  -- En minúsculas
+    fecha_entrega DATE NOT NULL,
+    estado TEXT NOT NULL DEFAULT 'comprometido' CHECK(estado IN ('comprometido', 'entregado', 'cancelado')),
+    cantidad_entregada NUMERIC(12, 2),
+    donante_nombre TEXT,
+    donante_contacto TEXT,  -- Privado: solo lo ve el punto
+    nota TEXT,
+    token_hash TEXT,  -- SHA-256 del token con que el donante cancela
+    created TIMESTAMP DEFAULT NOW(),
+    updated TIMESTAMP DEFAULT NOW()
+);
+
+-- Compromisos vigentes de un punto: WHERE punto_id = $1 AND estado = 'comprometido' AND fecha_entrega >= hoy
+CREATE INDEX IF NOT EXISTS idx_compromisos_punto_fecha ON compromisos(punto_id, estado, fecha_entrega);
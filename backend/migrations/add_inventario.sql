@@ -0,0 +1,44 @@
+-- ============================================================================
+-- MIGRACIÓN: Inventario de puntos (stock por ítem y movimientos)
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Un ítem del inventario de un punto. categoria es una clave de necesidades_tags
+-- (alimentos, herramientas, techo_abrigo, animales, medicamentos, olla_comun) u
+-- 'otros'; item es el nombre normalizado ("agua_embotellada"). cantidad es el
+-- stock actual y solo cambia con movimientos.
+CREATE TABLE IF NOT EXISTS inventario_items (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    item TEXT NOT NULL,
+    nombre TEXT NOT NULL,
+    categoria TEXT NOT NULL CHECK(categoria IN ('alimentos', 'herramientas', 'techo_abrigo', 'animales', 'medicamentos', 'olla_comun', 'otros')),
+    unidad TEXT NOT NULL,  -- kg, litros, unidades, cajas...
+    cantidad NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK(cantidad >= 0),
+    objetivo NUMERIC(12, 2),  -- NULLABLE - Stock que el punto quiere mantener
+    created TIMESTAMP DEFAULT NOW(),
+    updated TIMESTAMP DEFAULT NOW(),
+    UNIQUE (punto_id, item, unidad)
+);
+
+-- Consulta entre puntos: WHERE item = $1 / categoria = $1
+CREATE INDEX IF NOT EXISTS idx_inventario_items_item ON inventario_items(item);
+CREATE INDEX IF NOT EXISTS idx_inventario_items_categoria ON inventario_items(categoria);
+
+-- Historial de stock. En 'ajuste' cantidad es el stock contado; saldo es siempre
+-- el stock que quedó después del movimiento.
+CREATE TABLE IF NOT EXISTS inventario_movimientos (
+    id TEXT PRIMARY KEY,
+    item_id TEXT NOT NULL REFERENCES inventario_items(id),
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    tipo TEXT NOT NULL CHECK(tipo IN ('recibido', 'despachado', 'ajuste')),
+    cantidad NUMERIC(12, 2) NOT NULL,
+    saldo NUMERIC(12, 2) NOT NULL,
+    nota TEXT,
+    registrado_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW()
+);
+
+-- Historial de un ítem: WHERE item_id = $1 ORDER BY created DESC
+CREATE INDEX IF NOT EXISTS idx_inventario_movimientos_item_created ON inventario_movimientos(item_id, created DESC);
@@ -0,0 +1,15 @@
+-- ============================================================================
+-- MIGRACIÓN: Rutas de acceso de los puntos (KML/KMZ subido)
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Las rutas del último KML/KMZ subido a un punto, ya validadas y convertidas a
+-- GeoJSON para el mapa. puntos.archivo_kml guarda la URL del archivo original.
+CREATE TABLE IF NOT EXISTS punto_rutas (
+    punto_id TEXT PRIMARY KEY REFERENCES puntos(id),
+    geojson JSONB NOT NULL,  -- FeatureCollection con bbox
+    geometrias INTEGER NOT NULL,
+    subido_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,8 @@
+-- ============================================================================
+-- MIGRACIÓN: Índice para paginación por cursor (created, id) en puntos
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Soporta ORDER BY created DESC, id DESC y la condición (created, id) < (...)
+CREATE INDEX IF NOT EXISTS idx_puntos_created_id ON puntos(created DESC, id DESC);
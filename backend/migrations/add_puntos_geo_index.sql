@@ -0,0 +1,8 @@
+-- ============================================================================
+-- MIGRACIÓN: Índice geográfico para consultas bbox / near en puntos
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Índice compuesto para filtrar por rectángulo (bbox y prefiltro de near)
+CREATE INDEX IF NOT EXISTS idx_puntos_lat_lng ON puntos(latitud, longitud);
@@ -0,0 +1,13 @@
+-- ============================================================================
+-- MIGRACIÓN: Horario estructurado (opening_hours de OSM) para filtrar abiertos
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- horario_osm: "Mo-Fr 09:00-18:00; Sa 10:00-14:00". NULL = aún no derivado del
+-- texto libre (el servidor lo completa al iniciar, ver database.BackfillHorarios).
+ALTER TABLE puntos ADD COLUMN IF NOT EXISTS horario_osm TEXT;
+
+-- Tramos abiertos de la semana [[inicio, fin], ...] en minutos desde el lunes 00:00
+-- (hora de Chile), calculados desde horario_osm. Los usan open_now / open_at.
+ALTER TABLE puntos ADD COLUMN IF NOT EXISTS horario_intervalos JSONB;
@@ -0,0 +1,9 @@
+-- ============================================================================
+-- MIGRACIÓN: Índices GIN para filtros sobre arrays JSONB de puntos
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Soportan los operadores ?| (alguno), ?& (todos) y <@ (solo) de GET /api/puntos
+CREATE INDEX IF NOT EXISTS idx_puntos_categorias_ayuda ON puntos USING GIN(categorias_ayuda);
+CREATE INDEX IF NOT EXISTS idx_puntos_tipos_acceso ON puntos USING GIN(tipos_acceso);
@@ -0,0 +1,20 @@
+-- ============================================================================
+-- MIGRACIÓN: Recordar cuándo se publicó un punto por primera vez
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- publicado_en: primera vez que el punto pasó a publicado (NULL = nunca). El
+-- stream y /api/puntos/changes solo informan como removed los puntos que el
+-- público llegó a ver; los envíos en moderación o rechazados no se anuncian.
+ALTER TABLE puntos ADD COLUMN IF NOT EXISTS publicado_en TIMESTAMP;
+
+-- Puntos existentes: los publicados, y los despublicados que no vienen de un
+-- envío ciudadano sin aprobar
+UPDATE puntos p
+SET publicado_en = COALESCE(p.fecha_verificacion, p.created)
+WHERE p.publicado_en IS NULL
+  AND p.estado <> 'pendiente'
+  AND (p.estado = 'publicado' OR NOT EXISTS (
+      SELECT 1 FROM solicitudes s WHERE s.punto_id = p.id AND s.estado <> 'aprobada'
+  ));
@@ -0,0 +1,28 @@
+-- ============================================================================
+-- MIGRACIÓN: Búsqueda de texto completo en puntos (parámetro q=)
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Extensión para quitar acentos ("Concepción" = "concepcion")
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() no es IMMUTABLE; este wrapper permite usarla en columnas generadas e índices
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- Vector de búsqueda con stemming español ("albergue" = "albergues")
+-- Pesos: A = nombre, B = zona/dirección/ciudad, C = necesidades
+ALTER TABLE puntos
+ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('spanish', f_unaccent(coalesce(nombre, ''))), 'A') ||
+    setweight(to_tsvector('spanish', f_unaccent(coalesce(nombre_zona, ''))), 'B') ||
+    setweight(to_tsvector('spanish', f_unaccent(coalesce(direccion, '') || ' ' || coalesce(ciudad, ''))), 'B') ||
+    setweight(to_tsvector('spanish', f_unaccent(coalesce(necesidades_raw, ''))), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_puntos_search ON puntos USING GIN(search_vector);
+
+COMMENT ON COLUMN puntos.search_vector IS
+  'Vector de búsqueda generado (nombre, zona, dirección, ciudad, necesidades) sin acentos y con stemming español';
@@ -0,0 +1,8 @@
+-- ============================================================================
+-- MIGRACIÓN: Índice para sincronización incremental (GET /api/puntos/changes)
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Soporta WHERE (updated, id) > (...) ORDER BY updated, id
+CREATE INDEX IF NOT EXISTS idx_puntos_updated_id ON puntos(updated, id);
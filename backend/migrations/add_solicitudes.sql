@@ -0,0 +1,25 @@
+-- ============================================================================
+-- MIGRACIÓN: Envíos ciudadanos y cola de moderación
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Un envío público (POST /api/puntos o POST /api/solicitudes). Si trae un
+-- punto, éste se crea con estado 'pendiente' y se publica al aprobarlo.
+-- Los datos del remitente son privados: solo los ve quien modera.
+CREATE TABLE IF NOT EXISTS solicitudes (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT REFERENCES puntos(id),  -- NULLABLE: mensajes sin punto
+    remitente_nombre TEXT,
+    remitente_contacto TEXT,
+    mensaje TEXT,
+    origen TEXT,  -- web-sos, web-collaborate, ...
+    estado TEXT DEFAULT 'pendiente' CHECK(estado IN ('pendiente', 'aprobada', 'rechazada')),
+    motivo_rechazo TEXT,
+    moderado_por TEXT,
+    fecha_moderacion TIMESTAMP,
+    created TIMESTAMP DEFAULT NOW()
+);
+
+-- Cola de moderación: WHERE estado = $1 ORDER BY created
+CREATE INDEX IF NOT EXISTS idx_solicitudes_estado_created ON solicitudes(estado, created);
@@ -0,0 +1,9 @@
+-- ============================================================================
+-- MIGRACIÓN: Marcar posibles duplicados en la cola de moderación
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Punto pendiente o publicado de la misma categoría a menos de 50 m del propuesto.
+-- El envío se acepta igual; quien modera decide si es el mismo lugar.
+ALTER TABLE solicitudes ADD COLUMN IF NOT EXISTS duplicado_de TEXT REFERENCES puntos(id);
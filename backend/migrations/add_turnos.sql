@@ -0,0 +1,40 @@
+-- ============================================================================
+-- MIGRACIÓN: Turnos de voluntarios por punto y token de voluntario
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- SHA-256 del token con que el voluntario toma turnos y lee su calendario.
+-- Los voluntarios inscritos antes de esta migración quedan sin token hasta que
+-- un verificador les genere uno (POST /api/admin/voluntarios/:id/token).
+ALTER TABLE voluntarios ADD COLUMN IF NOT EXISTS token_hash TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_voluntarios_token_hash ON voluntarios(token_hash);
+
+-- Turnos de un punto (p. ej. cocina de 08:00 a 16:00 con 4 cupos)
+CREATE TABLE IF NOT EXISTS turnos (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    inicio TIMESTAMPTZ NOT NULL,
+    fin TIMESTAMPTZ NOT NULL,
+    rol TEXT NOT NULL,
+    cupos INTEGER NOT NULL CHECK(cupos > 0),
+    nota TEXT,
+    creado_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW(),
+    updated TIMESTAMP DEFAULT NOW(),
+    CHECK(fin > inicio)
+);
+
+CREATE INDEX IF NOT EXISTS idx_turnos_punto_inicio ON turnos(punto_id, inicio);
+CREATE INDEX IF NOT EXISTS idx_turnos_fin ON turnos(fin);
+
+-- Voluntarios inscritos en cada turno
+CREATE TABLE IF NOT EXISTS turno_inscripciones (
+    turno_id TEXT NOT NULL REFERENCES turnos(id) ON DELETE CASCADE,
+    voluntario_id TEXT NOT NULL REFERENCES voluntarios(id) ON DELETE CASCADE,
+    created TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (turno_id, voluntario_id)
+);
+
+-- Turnos de un voluntario (cruces y calendario): WHERE voluntario_id = $1
+CREATE INDEX IF NOT EXISTS idx_turno_inscripciones_voluntario ON turno_inscripciones(voluntario_id);
@@ -0,0 +1,44 @@
+-- ============================================================================
+-- MIGRACIÓN: Registro de voluntarios y asignación a puntos
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- Cuántos voluntarios pide el punto (requiere_voluntarios solo dice si pide)
+ALTER TABLE puntos ADD COLUMN IF NOT EXISTS cupo_voluntarios INTEGER;
+
+-- Voluntarios inscritos desde el formulario público. vehiculo usa los mismos
+-- valores que puntos.tipos_acceso; habilidades son nombres normalizados
+-- ("primeros_auxilios"). disponibilidad_intervalos se calcula igual que
+-- puntos.horario_intervalos a partir de disponibilidad_osm.
+CREATE TABLE IF NOT EXISTS voluntarios (
+    id TEXT PRIMARY KEY,
+    nombre TEXT NOT NULL,
+    telefono TEXT,
+    email TEXT,
+    habilidades JSONB NOT NULL DEFAULT '[]',
+    ciudad TEXT NOT NULL,
+    vehiculo TEXT NOT NULL DEFAULT 'pie' CHECK(vehiculo IN ('pie', 'auto', '4x4', 'camion')),
+    disponibilidad TEXT,  -- Texto libre ("fines de semana")
+    disponibilidad_osm TEXT,  -- opening_hours de OSM
+    disponibilidad_intervalos JSONB,
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    notas TEXT,  -- Notas internas de coordinación
+    created TIMESTAMP DEFAULT NOW(),
+    updated TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_voluntarios_ciudad ON voluntarios(ciudad);
+CREATE INDEX IF NOT EXISTS idx_voluntarios_habilidades ON voluntarios USING GIN(habilidades);
+
+-- Voluntarios asignados a cada punto
+CREATE TABLE IF NOT EXISTS voluntario_asignaciones (
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    voluntario_id TEXT NOT NULL REFERENCES voluntarios(id) ON DELETE CASCADE,
+    asignado_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (punto_id, voluntario_id)
+);
+
+-- Puntos de un voluntario: WHERE voluntario_id = $1
+CREATE INDEX IF NOT EXISTS idx_voluntario_asignaciones_voluntario ON voluntario_asignaciones(voluntario_id);
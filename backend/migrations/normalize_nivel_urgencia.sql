@@ -0,0 +1,17 @@
+-- ============================================================================
+-- MIGRACIÓN: Unificar nivel_urgencia en critico, alto, medio y bajo
+-- ============================================================================
+-- Ejecutar en: Supabase Dashboard > SQL Editor
+-- ============================================================================
+
+-- El panel admin guardaba los niveles en femenino (critica, alta, media, baja);
+-- la API, los filtros y las estadísticas usan los valores en masculino.
+UPDATE puntos
+SET nivel_urgencia = CASE nivel_urgencia
+        WHEN 'critica' THEN 'critico'
+        WHEN 'alta' THEN 'alto'
+        WHEN 'media' THEN 'medio'
+        WHEN 'baja' THEN 'bajo'
+    END,
+    updated = NOW()
+WHERE nivel_urgencia IN ('critica', 'alta', 'media', 'baja');
@@ -20,7 +20,7 @@ CREATE TABLE IF NOT EXISTS users (
     
     -- Información personal
     name TEXT NOT NULL,
-    rol TEXT NOT NULL CHECK(rol IN ('superadmin', 'admin', 'verificador', 'usuario')),
+    rol TEXT NOT NULL CHECK(rol IN ('superadmin', 'admin', 'verificador', 'encargado', 'usuario')),
     organizacion TEXT,  -- NULLABLE
     avatar TEXT,  -- NULLABLE
     
@@ -41,6 +41,16 @@ CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 CREATE INDEX IF NOT EXISTS idx_users_rol ON users(rol);
 CREATE INDEX IF NOT EXISTS idx_users_activo ON users(activo);
 
+-- ============================================================================
+-- EXTENSIONES Y FUNCIONES
+-- ============================================================================
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() no es IMMUTABLE; este wrapper permite usarla en columnas generadas
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
 -- ============================================================================
 -- TABLA: puntos
 -- ============================================================================
@@ -67,6 +77,8 @@ CREATE TABLE IF NOT EXISTS puntos (
     contacto_principal TEXT,  -- NULLABLE
     contacto_nombre TEXT,  -- NULLABLE
     horario TEXT,  -- NULLABLE
+    horario_osm TEXT,  -- NULLABLE - opening_hours de OSM ("Mo-Fr 09:00-18:00"), derivado de horario si no se indica
+    horario_intervalos JSONB,  -- NULLABLE - Tramos [[inicio, fin], ...] en minutos desde el lunes 00:00 (hora de Chile)
     
     -- Estado y verificación
     estado TEXT DEFAULT 'activo' CHECK(estado IN ('activo', 'inactivo', 'pendiente', 'cerrado', 'eliminado')),
@@ -77,6 +89,11 @@ CREATE TABLE IF NOT EXISTS puntos (
     notas_internas TEXT,  -- NULLABLE - Solo admins
     capacidad_estado TEXT,  -- NULLABLE
     
+    -- Ocupación de albergues (ver tabla ocupacion_historial)
+    capacidad_total INTEGER,  -- NULLABLE - Camas declaradas
+    ocupacion_actual INTEGER,  -- NULLABLE - Último reporte; NULL = sin reportes
+    ocupacion_actualizada TIMESTAMP,  -- NULLABLE
+    
     -- Necesidades
     necesidades_raw TEXT,  -- NULLABLE
     necesidades_tags JSONB,  -- NULLABLE - Estructura compleja con categorías de necesidades
@@ -96,6 +113,7 @@ CREATE TABLE IF NOT EXISTS puntos (
     logistica_llegada TEXT,  -- NULLABLE
     tipos_acceso JSONB,  -- NULLABLE - Array de tipos ["auto", "4x4", "pie", "camion"]
     requiere_voluntarios BOOLEAN DEFAULT FALSE,
+    cupo_voluntarios INTEGER,  -- NULLABLE - Cuántos voluntarios pide
     
     -- Evidencia
     evidencia_fotos JSONB,  -- NULLABLE - Array de URLs
@@ -114,7 +132,16 @@ CREATE TABLE IF NOT EXISTS puntos (
     -- Timestamps
     created TIMESTAMP DEFAULT NOW(),
     updated TIMESTAMP DEFAULT NOW(),
-    created_by TEXT  -- NULLABLE - Referencia al user.id que lo creó
+    created_by TEXT,  -- NULLABLE - Referencia al user.id que lo creó
+    publicado_en TIMESTAMP,  -- NULLABLE - Primera vez que pasó a publicado (NULL = nunca)
+
+    -- Búsqueda de texto (generada, sin acentos y con stemming español)
+    search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('spanish', f_unaccent(coalesce(nombre, ''))), 'A') ||
+        setweight(to_tsvector('spanish', f_unaccent(coalesce(nombre_zona, ''))), 'B') ||
+        setweight(to_tsvector('spanish', f_unaccent(coalesce(direccion, '') || ' ' || coalesce(ciudad, ''))), 'B') ||
+        setweight(to_tsvector('spanish', f_unaccent(coalesce(necesidades_raw, ''))), 'C')
+    ) STORED
 );
 
 -- Índices para puntos
@@ -124,8 +151,208 @@ CREATE INDEX IF NOT EXISTS idx_puntos_categoria ON puntos(categoria);
 CREATE INDEX IF NOT EXISTS idx_puntos_ciudad ON puntos(ciudad);
 CREATE INDEX IF NOT EXISTS idx_puntos_subtipo ON puntos(subtipo);
 CREATE INDEX IF NOT EXISTS idx_puntos_created ON puntos(created);
+CREATE INDEX IF NOT EXISTS idx_puntos_created_id ON puntos(created DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_puntos_updated_id ON puntos(updated, id);
 CREATE INDEX IF NOT EXISTS idx_puntos_nivel_urgencia ON puntos(nivel_urgencia);
 CREATE INDEX IF NOT EXISTS idx_puntos_habitado ON puntos(habitado_actualmente);
+CREATE INDEX IF NOT EXISTS idx_puntos_lat_lng ON puntos(latitud, longitud);
+CREATE INDEX IF NOT EXISTS idx_puntos_search ON puntos USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_puntos_categorias_ayuda ON puntos USING GIN(categorias_ayuda);
+CREATE INDEX IF NOT EXISTS idx_puntos_tipos_acceso ON puntos USING GIN(tipos_acceso);
+
+-- ============================================================================
+-- TABLA: solicitudes (envíos ciudadanos pendientes de moderación)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS solicitudes (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT REFERENCES puntos(id),  -- NULLABLE: mensajes sin punto
+    duplicado_de TEXT REFERENCES puntos(id),  -- Punto cercano de la misma categoría ya reportado
+    
+    -- Remitente (PRIVADO - solo quien modera)
+    remitente_nombre TEXT,
+    remitente_contacto TEXT,
+    mensaje TEXT,
+    origen TEXT,
+    
+    -- Moderación
+    estado TEXT DEFAULT 'pendiente' CHECK(estado IN ('pendiente', 'aprobada', 'rechazada')),
+    motivo_rechazo TEXT,
+    moderado_por TEXT,
+    fecha_moderacion TIMESTAMP,
+    
+    created TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_solicitudes_estado_created ON solicitudes(estado, created);
+
+-- ============================================================================
+-- TABLA: ocupacion_historial (reportes de ocupación de albergues)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS ocupacion_historial (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    ocupacion INTEGER NOT NULL,
+    capacidad_total INTEGER,  -- Capacidad vigente al momento del reporte
+    nota TEXT,
+    reportado_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ocupacion_historial_punto_created ON ocupacion_historial(punto_id, created DESC);
+
+-- ============================================================================
+-- TABLA: punto_encargados (usuarios con rol encargado a cargo de un punto)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS punto_encargados (
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    user_id TEXT NOT NULL REFERENCES users(id),
+    created TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (punto_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_punto_encargados_user ON punto_encargados(user_id);
+
+-- ============================================================================
+-- TABLA: inventario_items (stock por ítem de cada punto)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS inventario_items (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    item TEXT NOT NULL,  -- Nombre normalizado ("agua_embotellada")
+    nombre TEXT NOT NULL,
+    categoria TEXT NOT NULL CHECK(categoria IN ('alimentos', 'herramientas', 'techo_abrigo', 'animales', 'medicamentos', 'olla_comun', 'otros')),
+    unidad TEXT NOT NULL,  -- kg, litros, unidades, cajas...
+    cantidad NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK(cantidad >= 0),
+    objetivo NUMERIC(12, 2),  -- NULLABLE - Stock que el punto quiere mantener
+    created TIMESTAMP DEFAULT NOW(),
+    updated TIMESTAMP DEFAULT NOW(),
+    UNIQUE (punto_id, item, unidad)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventario_items_item ON inventario_items(item);
+CREATE INDEX IF NOT EXISTS idx_inventario_items_categoria ON inventario_items(categoria);
+
+-- ============================================================================
+-- TABLA: inventario_movimientos (historial de stock)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS inventario_movimientos (
+    id TEXT PRIMARY KEY,
+    item_id TEXT NOT NULL REFERENCES inventario_items(id),
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    tipo TEXT NOT NULL CHECK(tipo IN ('recibido', 'despachado', 'ajuste')),
+    cantidad NUMERIC(12, 2) NOT NULL,  -- En 'ajuste', el stock contado
+    saldo NUMERIC(12, 2) NOT NULL,  -- Stock después del movimiento
+    nota TEXT,
+    registrado_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventario_movimientos_item_created ON inventario_movimientos(item_id, created DESC);
+
+-- ============================================================================
+-- TABLA: compromisos (donaciones prometidas a un punto)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS compromisos (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    categoria TEXT NOT NULL DEFAULT '',  -- Formato del cruce de necesidades
+    item TEXT NOT NULL DEFAULT '',  -- Nombre normalizado
+    nombre TEXT NOT NULL,  -- Como lo escribió el donante
+    cantidad NUMERIC(12, 2) NOT NULL CHECK(cantidad > 0),
+    unidad TEXT NOT NULL,
+    fecha_entrega DATE NOT NULL,
+    estado TEXT NOT NULL DEFAULT 'comprometido' CHECK(estado IN ('comprometido', 'entregado', 'cancelado')),
+    cantidad_entregada NUMERIC(12, 2),
+    donante_nombre TEXT,
+    donante_contacto TEXT,  -- Privado
+    nota TEXT,
+    token_hash TEXT,  -- SHA-256 del token para cancelar
+    created TIMESTAMP DEFAULT NOW(),
+    updated TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_compromisos_punto_fecha ON compromisos(punto_id, estado, fecha_entrega);
+
+-- ============================================================================
+-- TABLA: punto_rutas (rutas de acceso del KML/KMZ de un punto, como GeoJSON)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS punto_rutas (
+    punto_id TEXT PRIMARY KEY REFERENCES puntos(id),
+    geojson JSONB NOT NULL,  -- FeatureCollection con bbox
+    geometrias INTEGER NOT NULL,
+    subido_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW()
+);
+
+-- ============================================================================
+-- TABLA: voluntarios (inscritos desde el formulario público)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS voluntarios (
+    id TEXT PRIMARY KEY,
+    nombre TEXT NOT NULL,
+    telefono TEXT,
+    email TEXT,
+    habilidades JSONB NOT NULL DEFAULT '[]',  -- Nombres normalizados
+    ciudad TEXT NOT NULL,
+    vehiculo TEXT NOT NULL DEFAULT 'pie' CHECK(vehiculo IN ('pie', 'auto', '4x4', 'camion')),  -- Mismos valores que tipos_acceso
+    disponibilidad TEXT,  -- Texto libre
+    disponibilidad_osm TEXT,  -- opening_hours de OSM
+    disponibilidad_intervalos JSONB,  -- Como horario_intervalos
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    notas TEXT,
+    token_hash TEXT,  -- SHA-256 del token para tomar turnos
+    created TIMESTAMP DEFAULT NOW(),
+    updated TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_voluntarios_ciudad ON voluntarios(ciudad);
+CREATE INDEX IF NOT EXISTS idx_voluntarios_habilidades ON voluntarios USING GIN(habilidades);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_voluntarios_token_hash ON voluntarios(token_hash);
+
+-- ============================================================================
+-- TABLA: voluntario_asignaciones (voluntarios asignados a cada punto)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS voluntario_asignaciones (
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    voluntario_id TEXT NOT NULL REFERENCES voluntarios(id) ON DELETE CASCADE,
+    asignado_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (punto_id, voluntario_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_voluntario_asignaciones_voluntario ON voluntario_asignaciones(voluntario_id);
+
+-- ============================================================================
+-- TABLA: turnos (bloques de trabajo de un punto con cupos para voluntarios)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS turnos (
+    id TEXT PRIMARY KEY,
+    punto_id TEXT NOT NULL REFERENCES puntos(id),
+    inicio TIMESTAMPTZ NOT NULL,
+    fin TIMESTAMPTZ NOT NULL,
+    rol TEXT NOT NULL,  -- cocina, bodega, recepción...
+    cupos INTEGER NOT NULL CHECK(cupos > 0),
+    nota TEXT,
+    creado_por TEXT,  -- user.id
+    created TIMESTAMP DEFAULT NOW(),
+    updated TIMESTAMP DEFAULT NOW(),
+    CHECK(fin > inicio)
+);
+
+CREATE INDEX IF NOT EXISTS idx_turnos_punto_inicio ON turnos(punto_id, inicio);
+CREATE INDEX IF NOT EXISTS idx_turnos_fin ON turnos(fin);
+
+-- ============================================================================
+-- TABLA: turno_inscripciones (voluntarios que tomaron cada turno)
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS turno_inscripciones (
+    turno_id TEXT NOT NULL REFERENCES turnos(id) ON DELETE CASCADE,
+    voluntario_id TEXT NOT NULL REFERENCES voluntarios(id) ON DELETE CASCADE,
+    created TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (turno_id, voluntario_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_turno_inscripciones_voluntario ON turno_inscripciones(voluntario_id);
 
 -- ============================================================================
 -- DATOS INICIALES
@@ -3,7 +3,10 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/storage"
 )
 
 type Config struct {
@@ -12,6 +15,17 @@ type Config struct {
 	JWTExpiry   time.Duration
 	DatabaseURL string
 	Environment string
+
+	// Protección de envíos públicos (POST /api/puntos, POST /api/solicitudes)
+	SubmissionIPLimit     int           // Envíos por IP en cada ventana
+	SubmissionDeviceLimit int           // Envíos por X-Device-ID en cada ventana
+	SubmissionWindow      time.Duration // Ventana del rate limit y de la detección de duplicados
+	PoWDifficulty         int           // Bits en cero exigidos por la prueba de trabajo (0 = desactivada)
+	TrustedProxies        string        // IPs/CIDR de los proxies cuyo X-Real-IP se acepta (nginx en el mismo contenedor)
+
+	// Archivos subidos (fotos de evidencia)
+	Storage        storage.Config
+	UploadMaxBytes int64 // Tamaño máximo de cada archivo
 }
 
 func Load() *Config {
@@ -43,5 +57,44 @@ func Load() *Config {
 		JWTExpiry:   24 * time.Hour,
 		DatabaseURL: databaseURL,
 		Environment: env,
+
+		// Varias personas pueden compartir IP (albergues, CGNAT móvil): el límite por IP es más holgado
+		SubmissionIPLimit:     envInt("SUBMISSION_IP_LIMIT", 30),
+		SubmissionDeviceLimit: envInt("SUBMISSION_DEVICE_LIMIT", 5),
+		SubmissionWindow:      10 * time.Minute,
+		PoWDifficulty:         envInt("POW_DIFFICULTY", 0),
+		TrustedProxies:        envString("TRUSTED_PROXIES", "127.0.0.1,::1"),
+
+		Storage: storage.Config{
+			Backend:     os.Getenv("STORAGE_BACKEND"),
+			LocalDir:    envString("UPLOAD_DIR", "./uploads"),
+			PublicURL:   os.Getenv("UPLOAD_PUBLIC_URL"),
+			S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+			S3Region:    os.Getenv("S3_REGION"),
+			S3Bucket:    os.Getenv("S3_BUCKET"),
+			S3AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+			S3SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		},
+		UploadMaxBytes: int64(envInt("UPLOAD_MAX_MB", 10)) << 20,
+	}
+}
+
+func envString(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+func envInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Printf("⚠️  %s inválido (%q), usando %d", name, v, def)
+		return def
 	}
+	return n
 }
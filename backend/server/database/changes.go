@@ -0,0 +1,45 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// ChangesPageSize es el máximo de puntos por página de cambios
+const ChangesPageSize = 500
+
+// GetPuntoChanges devuelve los puntos modificados después de since (por updated, id), en orden.
+// Incluye puntos en cualquier estado: el llamador decide cuáles cuentan como eliminados.
+//
+// Los cambios de los últimos segundos se excluyen para no saltarse transacciones
+// que aún no hacen commit con un updated anterior al último entregado.
+func GetPuntoChanges(since models.PuntoCursor, limit int) ([]models.Punto, bool, error) {
+	query := "SELECT " + puntoColumns + `
+		FROM puntos
+		WHERE (updated, id) > ($1::timestamp, $2)
+		  AND updated <= NOW() - INTERVAL '2 seconds'
+		ORDER BY updated ASC, id ASC
+		LIMIT $3`
+
+	rows, err := DB.Query(query, since.Created, since.ID, limit+1)
+	if err != nil {
+		return nil, false, fmt.Errorf("error listando cambios: %w", err)
+	}
+	defer rows.Close()
+
+	puntos := []models.Punto{}
+	for rows.Next() {
+		punto, err := scanPunto(rows)
+		if err != nil {
+			return nil, false, err
+		}
+		puntos = append(puntos, *punto)
+	}
+
+	hasMore := len(puntos) > limit
+	if hasMore {
+		puntos = puntos[:limit]
+	}
+	return puntos, hasMore, rows.Err()
+}
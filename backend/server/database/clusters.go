@@ -0,0 +1,116 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/geo"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+const (
+	// ClusterThreshold es la cantidad de puntos sobre la cual se responde con clusters
+	ClusterThreshold = 200
+	// clusterCellsPerTile define el tamaño de celda (~64px en un tile de 256px)
+	clusterCellsPerTile = 4
+)
+
+type clusterKey struct{ x, y int }
+
+// clusterAcc acumula los puntos de una celda de la grilla
+type clusterAcc struct {
+	sumLat, sumLng float64
+	cluster        models.PuntoCluster
+	maxRank        int
+}
+
+// GetPuntoClusters agrupa en una grilla por zoom los puntos del filtro (f.BBox es obligatorio).
+// Si hay ClusterThreshold puntos o menos, devuelve los puntos sin agrupar.
+func GetPuntoClusters(f models.PuntoFilter, zoom int) (*models.ClustersResult, error) {
+	if f.Estado == "" {
+		f.Estado = "activo"
+	}
+
+	countQ := newPuntosQuery(f)
+	var total int
+	err := DB.QueryRow("SELECT COUNT(*) FROM puntos"+countQ.whereSQL(), countQ.args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("error contando puntos: %w", err)
+	}
+
+	response := &models.ClustersResult{Zoom: zoom, Total: total}
+
+	if total <= ClusterThreshold {
+		f.UseCursor = false
+		f.Page = 1
+		f.Limit = ClusterThreshold
+		list, err := GetPuntos(f)
+		if err != nil {
+			return nil, err
+		}
+		response.Puntos = list.Data
+		return response, nil
+	}
+
+	q := newPuntosQuery(f)
+	rows, err := DB.Query("SELECT id, latitud, longitud, categoria, nivel_urgencia FROM puntos"+q.whereSQL(), q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("error agrupando puntos: %w", err)
+	}
+	defer rows.Close()
+
+	cells := map[clusterKey]*clusterAcc{}
+	order := []clusterKey{}
+	for rows.Next() {
+		var id string
+		var lat, lng float64
+		var categoria, nivel sql.NullString
+		if err := rows.Scan(&id, &lat, &lng, &categoria, &nivel); err != nil {
+			return nil, fmt.Errorf("error escaneando punto: %w", err)
+		}
+
+		cx, cy := geo.GridCell(lng, lat, zoom, clusterCellsPerTile)
+		key := clusterKey{cx, cy}
+		acc, ok := cells[key]
+		if !ok {
+			acc = &clusterAcc{cluster: models.PuntoCluster{
+				Categorias: map[string]int{},
+				BBox:       [4]float64{lng, lat, lng, lat},
+			}}
+			cells[key] = acc
+			order = append(order, key)
+		}
+
+		acc.sumLat += lat
+		acc.sumLng += lng
+		acc.cluster.Count++
+		acc.cluster.PuntoID = id
+		acc.cluster.Categorias[categoria.String]++
+		acc.cluster.BBox = [4]float64{
+			math.Min(acc.cluster.BBox[0], lng), math.Min(acc.cluster.BBox[1], lat),
+			math.Max(acc.cluster.BBox[2], lng), math.Max(acc.cluster.BBox[3], lat),
+		}
+		if rank := models.NivelUrgenciaRank(nivel.String); rank > acc.maxRank {
+			acc.maxRank = rank
+			acc.cluster.MaxNivelUrgencia = nivel.String
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error agrupando puntos: %w", err)
+	}
+
+	response.Clustered = true
+	response.Clusters = make([]models.PuntoCluster, 0, len(order))
+	for _, key := range order {
+		acc := cells[key]
+		acc.cluster.Latitud = acc.sumLat / float64(acc.cluster.Count)
+		acc.cluster.Longitud = acc.sumLng / float64(acc.cluster.Count)
+		if acc.cluster.Count > 1 {
+			acc.cluster.PuntoID = ""
+		}
+		response.Clusters = append(response.Clusters, acc.cluster)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,226 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/horario"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// ErrCompromisoCerrado se devuelve al cambiar un compromiso ya entregado o cancelado
+var ErrCompromisoCerrado = errors.New("el compromiso ya está cerrado")
+
+const compromisoColumns = `
+		c.id, c.punto_id, c.categoria, c.item, c.nombre, c.cantidad, c.unidad,
+		TO_CHAR(c.fecha_entrega, 'YYYY-MM-DD'), c.estado, c.cantidad_entregada,
+		c.donante_nombre, c.donante_contacto, c.nota, c.created, c.updated`
+
+// CreateCompromiso guarda el compromiso de un donante para la necesidad n del punto.
+// Devuelve también el token con que el donante lo puede cancelar.
+func CreateCompromiso(puntoID string, n models.Necesidad, req models.CompromisoCreateRequest) (*models.Compromiso, string, error) {
+	id := fmt.Sprintf("cmp_%d", time.Now().UnixNano())
+	token, tokenHash := newToken()
+
+	_, err := DB.Exec(`
+		INSERT INTO compromisos (
+			id, punto_id, categoria, item, nombre, cantidad, unidad, fecha_entrega, estado,
+			donante_nombre, donante_contacto, nota, token_hash, created, updated
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
+	`, id, puntoID, n.Categoria, n.Item, strings.TrimSpace(req.Item), req.Cantidad,
+		normalizeUnidad(req.Unidad), req.FechaEntrega, models.CompromisoComprometido,
+		req.DonanteNombre, req.DonanteContacto, req.Nota, tokenHash)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creando compromiso: %w", err)
+	}
+
+	compromiso, err := GetCompromiso(puntoID, id)
+	return compromiso, token, err
+}
+
+func GetCompromiso(puntoID, id string) (*models.Compromiso, error) {
+	query := "SELECT " + compromisoColumns + " FROM compromisos c WHERE c.punto_id = $1 AND c.id = $2"
+
+	compromisos, err := queryCompromisos(query, puntoID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(compromisos) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &compromisos[0], nil
+}
+
+// GetCompromisos lista los compromisos de un punto por fecha de entrega
+func GetCompromisos(f models.CompromisoFilter) ([]models.Compromiso, error) {
+	conds := []string{"c.punto_id = $1"}
+	args := []interface{}{f.PuntoID}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Estado != "" {
+		conds = append(conds, "c.estado = "+arg(f.Estado))
+	}
+	if f.Vigente {
+		conds = append(conds, "c.estado = "+arg(models.CompromisoComprometido))
+		conds = append(conds, "c.fecha_entrega >= "+arg(time.Now().In(horario.Santiago).Format("2006-01-02")))
+	}
+
+	query := "SELECT " + compromisoColumns + `
+		FROM compromisos c
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY c.fecha_entrega, c.created, c.id
+		LIMIT ` + arg(f.Limit)
+
+	return queryCompromisos(query, args...)
+}
+
+// CerrarCompromiso marca un compromiso vigente como entregado o cancelado. Al
+// entregarlo, si el inventario del punto lleva ese ítem con la misma unidad, la
+// cantidad recibida entra como movimiento "recibido".
+func CerrarCompromiso(puntoID, id string, req models.CompromisoUpdateRequest, registradoPor string) (*models.Compromiso, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var estado, item, unidad, nombre string
+	var cantidad float64
+	err = tx.QueryRow(`
+		SELECT estado, item, unidad, nombre, cantidad FROM compromisos
+		WHERE punto_id = $1 AND id = $2 FOR UPDATE
+	`, puntoID, id).Scan(&estado, &item, &unidad, &nombre, &cantidad)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando compromiso: %w", err)
+	}
+	if estado != models.CompromisoComprometido {
+		return nil, ErrCompromisoCerrado
+	}
+
+	var entregada sql.NullFloat64
+	if req.Estado == models.CompromisoEntregado {
+		entregada = sql.NullFloat64{Float64: cantidad, Valid: true}
+		if req.CantidadEntregada != nil {
+			entregada.Float64 = *req.CantidadEntregada
+		}
+	}
+
+	query := `UPDATE compromisos SET estado = $1, cantidad_entregada = $2, updated = NOW()`
+	args := []interface{}{req.Estado, entregada}
+	if req.Nota != nil {
+		query += ", nota = $3"
+		args = append(args, *req.Nota)
+	}
+	args = append(args, id)
+	query += fmt.Sprintf(" WHERE id = $%d", len(args))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return nil, fmt.Errorf("error actualizando compromiso: %w", err)
+	}
+
+	if entregada.Valid && entregada.Float64 > 0 && item != "" {
+		var itemID string
+		var stock float64
+		err := tx.QueryRow(`
+			SELECT id, cantidad FROM inventario_items
+			WHERE punto_id = $1 AND item = $2 AND LOWER(unidad) = $3 FOR UPDATE
+		`, puntoID, item, unidad).Scan(&itemID, &stock)
+		switch {
+		case err == sql.ErrNoRows:
+			// El punto no lleva inventario de este ítem
+		case err != nil:
+			return nil, fmt.Errorf("error buscando ítem de inventario: %w", err)
+		default:
+			saldo := stock + entregada.Float64
+			_, err = tx.Exec(`UPDATE inventario_items SET cantidad = $1, updated = NOW() WHERE id = $2`, saldo, itemID)
+			if err != nil {
+				return nil, fmt.Errorf("error actualizando stock: %w", err)
+			}
+			nota := "Compromiso " + id + ": " + nombre
+			err = insertMovimiento(tx, itemID, puntoID, models.MovimientoRecibido, entregada.Float64, saldo, nota, registradoPor)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error actualizando compromiso: %w", err)
+	}
+	return GetCompromiso(puntoID, id)
+}
+
+// CancelarCompromiso anula un compromiso vigente con el token que recibió el donante.
+// sql.ErrNoRows si el id o el token no coinciden.
+func CancelarCompromiso(id, token string) error {
+	var estado string
+	err := DB.QueryRow(
+		`SELECT estado FROM compromisos WHERE id = $1 AND token_hash = $2`,
+		id, hashToken(token),
+	).Scan(&estado)
+	if errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("error buscando compromiso: %w", err)
+	}
+	if estado == models.CompromisoCancelado {
+		return nil
+	}
+
+	res, err := DB.Exec(`
+		UPDATE compromisos SET estado = $1, updated = NOW() WHERE id = $2 AND estado = $3
+	`, models.CompromisoCancelado, id, models.CompromisoComprometido)
+	if err != nil {
+		return fmt.Errorf("error cancelando compromiso: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCompromisoCerrado
+	}
+	return nil
+}
+
+func queryCompromisos(query string, args ...interface{}) ([]models.Compromiso, error) {
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listando compromisos: %w", err)
+	}
+	defer rows.Close()
+
+	compromisos := []models.Compromiso{}
+	for rows.Next() {
+		var c models.Compromiso
+		var categoria, item, donanteNombre, donanteContacto, nota, created, updated sql.NullString
+		var entregada sql.NullFloat64
+		err := rows.Scan(
+			&c.ID, &c.PuntoID, &categoria, &item, &c.Nombre, &c.Cantidad, &c.Unidad,
+			&c.FechaEntrega, &c.Estado, &entregada,
+			&donanteNombre, &donanteContacto, &nota, &created, &updated,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando compromiso: %w", err)
+		}
+		c.Categoria = categoria.String
+		c.Item = item.String
+		if entregada.Valid {
+			c.CantidadEntregada = &entregada.Float64
+		}
+		c.DonanteNombre = donanteNombre.String
+		c.DonanteContacto = donanteContacto.String
+		c.Nota = nota.String
+		c.Created = created.String
+		c.Updated = updated.String
+		compromisos = append(compromisos, c)
+	}
+	return compromisos, rows.Err()
+}
+
+// normalizeUnidad deja la unidad en minúsculas para sumar compromisos iguales
+func normalizeUnidad(unidad string) string {
+	return strings.ToLower(strings.TrimSpace(unidad))
+}
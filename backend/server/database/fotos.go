@@ -0,0 +1,46 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/events"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// ErrMaxFotos indica que evidencia_fotos ya tiene models.MaxEvidenciaFotos fotos
+var ErrMaxFotos = errors.New("el punto ya tiene el máximo de fotos")
+
+// AddPuntoFoto guarda la URL de una foto subida: una foto de evidencia se agrega
+// al final de evidencia_fotos y una de asbesto reemplaza foto_asbesto.
+func AddPuntoFoto(id, tipo, url string) (*models.Punto, error) {
+	var (
+		res sql.Result
+		err error
+	)
+	switch tipo {
+	case models.FotoAsbesto:
+		res, err = DB.Exec(`UPDATE puntos SET foto_asbesto = $1, updated = NOW() WHERE id = $2`, url, id)
+	default:
+		// El límite se revisa en el mismo UPDATE para que dos subidas simultáneas no lo pasen
+		res, err = DB.Exec(`
+			UPDATE puntos
+			SET evidencia_fotos = COALESCE(evidencia_fotos, '[]'::jsonb) || to_jsonb($1::text),
+			    updated = NOW()
+			WHERE id = $2 AND jsonb_array_length(COALESCE(evidencia_fotos, '[]'::jsonb)) < $3
+		`, url, id, models.MaxEvidenciaFotos)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error guardando foto: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		if _, err := GetPuntoByID(id); err != nil {
+			return nil, err
+		}
+		return nil, ErrMaxFotos
+	}
+
+	return publishPunto(events.PuntoUpdated, id)
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/horario"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// resolveHorario devuelve los valores de horario_osm y horario_intervalos para un punto.
+// Si no viene horario_osm se intenta derivar del texto libre; si tampoco se puede, el
+// punto queda sin horario estructurado (y fuera de los filtros open_now/open_at).
+func resolveHorario(texto, osm string) (string, sql.NullString, error) {
+	var sch horario.Schedule
+	var err error
+	if osm != "" {
+		if sch, err = horario.Parse(osm); err != nil {
+			return "", sql.NullString{}, fmt.Errorf("horario_osm inválido: %w", err)
+		}
+	} else if sch, err = horario.FromText(texto); err != nil {
+		return "", sql.NullString{}, nil
+	}
+
+	intervalos, _ := json.Marshal(sch.Intervals())
+	return sch.String(), sql.NullString{String: string(intervalos), Valid: true}, nil
+}
+
+// openAtSQL es la condición "abierto en el minuto de la semana minute" sobre una columna
+// de intervalos: tramos [inicio, fin) en minutos desde el lunes 00:00
+func openAtSQL(column, minute string) string {
+	return fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM jsonb_array_elements(%[1]s) AS iv
+			WHERE (iv->>0)::int <= %[2]s AND (iv->>1)::int > %[2]s)`, column, minute)
+}
+
+// horarioUpdate calcula el horario estructurado de un PATCH. Enviar horario_osm lo
+// reemplaza (vacío lo vuelve a derivar del texto); cambiar solo el texto lo re-deriva
+// si se entiende y si no deja el anterior. changed es false si no hay que tocarlo.
+func horarioUpdate(id string, req models.PuntoUpdateRequest) (osm string, intervalos sql.NullString, changed bool, err error) {
+	if req.HorarioOSM == nil && req.Horario == nil {
+		return "", sql.NullString{}, false, nil
+	}
+
+	texto := ""
+	if req.Horario != nil {
+		texto = *req.Horario
+	} else {
+		actual, err := GetPuntoByID(id)
+		if err != nil {
+			return "", sql.NullString{}, false, err
+		}
+		texto = actual.Horario
+	}
+
+	explicit := ""
+	if req.HorarioOSM != nil {
+		explicit = *req.HorarioOSM
+	}
+
+	osm, intervalos, err = resolveHorario(texto, explicit)
+	if err != nil {
+		return "", sql.NullString{}, false, err
+	}
+	if req.HorarioOSM == nil && osm == "" {
+		return "", sql.NullString{}, false, nil
+	}
+	return osm, intervalos, true, nil
+}
+
+// BackfillHorarios deriva horario_osm del texto libre en los puntos que aún no lo
+// tienen. Los que no se entienden quedan con horario_osm vacío para no reintentarlos.
+func BackfillHorarios() (int, error) {
+	rows, err := DB.Query("SELECT id, COALESCE(horario, '') FROM puntos WHERE horario_osm IS NULL")
+	if err != nil {
+		return 0, fmt.Errorf("error buscando horarios: %w", err)
+	}
+
+	type pendiente struct{ id, texto string }
+	var pendientes []pendiente
+	for rows.Next() {
+		var p pendiente
+		if err := rows.Scan(&p.id, &p.texto); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("error escaneando horario: %w", err)
+		}
+		pendientes = append(pendientes, p)
+	}
+	rows.Close()
+
+	converted := 0
+	for _, p := range pendientes {
+		osm, intervalos, _ := resolveHorario(p.texto, "")
+		_, err := DB.Exec("UPDATE puntos SET horario_osm = $1, horario_intervalos = $2 WHERE id = $3", osm, intervalos, p.id)
+		if err != nil {
+			return converted, fmt.Errorf("error guardando horario: %w", err)
+		}
+		if osm != "" {
+			converted++
+		}
+	}
+
+	if len(pendientes) > 0 {
+		log.Printf("🕐 Horarios estructurados: %d de %d puntos convertidos", converted, len(pendientes))
+	}
+	return converted, nil
+}
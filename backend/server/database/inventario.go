@@ -0,0 +1,376 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/matching"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/lib/pq"
+)
+
+// ErrStockInsuficiente se devuelve al despachar más de lo que hay
+var ErrStockInsuficiente = errors.New("stock insuficiente")
+
+// ErrInventarioDuplicado se devuelve al renombrar un ítem a un nombre y unidad que ya existen en el punto
+var ErrInventarioDuplicado = errors.New("el punto ya tiene un ítem con ese nombre y unidad")
+
+const inventarioColumns = `
+		i.id, i.punto_id, i.item, i.nombre, i.categoria, i.unidad, i.cantidad, i.objetivo,
+		i.created, i.updated`
+
+// CreateInventarioItem agrega un ítem al inventario del punto. El stock inicial, si
+// lo hay, queda como un movimiento de ajuste.
+func CreateInventarioItem(puntoID string, req models.InventarioItemCreateRequest, registradoPor string) (*models.InventarioItem, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	id := fmt.Sprintf("inv_%d", time.Now().UnixNano())
+	_, err = tx.Exec(`
+		INSERT INTO inventario_items (id, punto_id, item, nombre, categoria, unidad, cantidad, objetivo, created, updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+	`, id, puntoID, matching.Normalize(req.Nombre), req.Nombre, req.Categoria, req.Unidad, req.Cantidad, objetivoArg(req.Objetivo))
+	if err != nil {
+		return nil, fmt.Errorf("error creando ítem de inventario: %w", err)
+	}
+
+	if req.Cantidad > 0 {
+		err = insertMovimiento(tx, id, puntoID, models.MovimientoAjuste, req.Cantidad, req.Cantidad, "Stock inicial", registradoPor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error guardando ítem de inventario: %w", err)
+	}
+	return GetInventarioItem(puntoID, id)
+}
+
+// FindInventarioItem busca el ítem del punto con ese nombre (normalizado) y unidad ("" si no hay)
+func FindInventarioItem(puntoID, nombre, unidad string) (string, error) {
+	var id string
+	err := DB.QueryRow(
+		`SELECT id FROM inventario_items WHERE punto_id = $1 AND item = $2 AND unidad = $3`,
+		puntoID, matching.Normalize(nombre), unidad,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error buscando ítem de inventario: %w", err)
+	}
+	return id, nil
+}
+
+func GetInventarioItem(puntoID, id string) (*models.InventarioItem, error) {
+	query := "SELECT " + inventarioColumns + " FROM inventario_items i WHERE i.punto_id = $1 AND i.id = $2"
+
+	rows, err := DB.Query(query, puntoID, id)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando ítem de inventario: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanInventarioItem(rows)
+	}
+	return nil, sql.ErrNoRows
+}
+
+// GetInventario devuelve el inventario del punto, por categoría y nombre
+func GetInventario(puntoID string) ([]models.InventarioItem, error) {
+	query := "SELECT " + inventarioColumns + `
+		FROM inventario_items i
+		WHERE i.punto_id = $1
+		ORDER BY i.categoria, i.nombre, i.unidad`
+
+	rows, err := DB.Query(query, puntoID)
+	if err != nil {
+		return nil, fmt.Errorf("error listando inventario: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.InventarioItem{}
+	for rows.Next() {
+		item, err := scanInventarioItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+// GetStockAcopios devuelve, por punto, los ítems con stock (cantidad > 0) de los
+// acopios publicados; es el lado de la oferta del cruce de necesidades
+func GetStockAcopios() (map[string][]models.InventarioItem, error) {
+	query := "SELECT " + inventarioColumns + `
+		FROM inventario_items i
+		JOIN puntos p ON p.id = i.punto_id
+		WHERE p.categoria = 'acopio' AND p.estado = 'publicado' AND i.cantidad > 0`
+
+	rows, err := DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error listando stock de acopios: %w", err)
+	}
+	defer rows.Close()
+
+	stock := map[string][]models.InventarioItem{}
+	for rows.Next() {
+		item, err := scanInventarioItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		stock[item.PuntoID] = append(stock[item.PuntoID], *item)
+	}
+	return stock, rows.Err()
+}
+
+// SearchInventario busca stock entre los puntos publicados: primero lo que más falta,
+// luego lo que más hay
+func SearchInventario(f models.InventarioFilter) ([]models.InventarioItem, error) {
+	conds := []string{"p.estado = 'publicado'"}
+	args := []interface{}{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Categoria != "" {
+		conds = append(conds, "i.categoria = "+arg(f.Categoria))
+	}
+	if f.Item != "" {
+		conds = append(conds, "i.item = "+arg(f.Item))
+	}
+	if f.Ciudad != "" {
+		conds = append(conds, "p.ciudad = "+arg(f.Ciudad))
+	}
+	if f.SoloFaltantes {
+		conds = append(conds, "i.objetivo IS NOT NULL AND i.cantidad < i.objetivo")
+	}
+
+	query := "SELECT " + inventarioColumns + `, p.nombre, p.ciudad
+		FROM inventario_items i
+		JOIN puntos p ON p.id = i.punto_id
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY COALESCE(i.objetivo - i.cantidad, 0) DESC, i.cantidad DESC, i.id
+		LIMIT ` + arg(f.Limit)
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando inventario: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.InventarioItem{}
+	for rows.Next() {
+		var nombre, ciudad sql.NullString
+		item, err := scanInventarioItem(rows, &nombre, &ciudad)
+		if err != nil {
+			return nil, err
+		}
+		item.PuntoNombre = nombre.String
+		item.Ciudad = ciudad.String
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+func UpdateInventarioItem(puntoID, id string, req models.InventarioItemUpdateRequest) (*models.InventarioItem, error) {
+	updates := []string{}
+	args := []interface{}{}
+	placeholder := 1
+
+	if req.Nombre != nil {
+		updates = append(updates, fmt.Sprintf("nombre = $%d, item = $%d", placeholder, placeholder+1))
+		args = append(args, *req.Nombre, matching.Normalize(*req.Nombre))
+		placeholder += 2
+	}
+	if req.Categoria != nil {
+		updates = append(updates, fmt.Sprintf("categoria = $%d", placeholder))
+		args = append(args, *req.Categoria)
+		placeholder++
+	}
+	if req.Unidad != nil {
+		updates = append(updates, fmt.Sprintf("unidad = $%d", placeholder))
+		args = append(args, *req.Unidad)
+		placeholder++
+	}
+	if req.Objetivo != nil {
+		updates = append(updates, fmt.Sprintf("objetivo = $%d", placeholder))
+		args = append(args, objetivoArg(req.Objetivo))
+		placeholder++
+	}
+
+	if len(updates) == 0 {
+		return GetInventarioItem(puntoID, id)
+	}
+
+	updates = append(updates, "updated = NOW()")
+	query := fmt.Sprintf("UPDATE inventario_items SET %s WHERE punto_id = $%d AND id = $%d",
+		strings.Join(updates, ", "), placeholder, placeholder+1)
+	args = append(args, puntoID, id)
+
+	res, err := DB.Exec(query, args...)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, ErrInventarioDuplicado
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando ítem de inventario: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return GetInventarioItem(puntoID, id)
+}
+
+// DeleteInventarioItem quita el ítem y su historial de movimientos
+func DeleteInventarioItem(puntoID, id string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM inventario_movimientos WHERE item_id = $1 AND punto_id = $2`, id, puntoID); err != nil {
+		return fmt.Errorf("error borrando movimientos: %w", err)
+	}
+	res, err := tx.Exec(`DELETE FROM inventario_items WHERE id = $1 AND punto_id = $2`, id, puntoID)
+	if err != nil {
+		return fmt.Errorf("error borrando ítem de inventario: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// RegisterMovimiento aplica un movimiento al stock del ítem y lo guarda en el
+// historial. Despachar más de lo que hay devuelve ErrStockInsuficiente.
+func RegisterMovimiento(puntoID, itemID string, req models.MovimientoRequest, registradoPor string) (*models.InventarioItem, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE: dos despachos simultáneos no pueden dejar el stock negativo
+	var cantidad float64
+	err = tx.QueryRow(
+		`SELECT cantidad FROM inventario_items WHERE id = $1 AND punto_id = $2 FOR UPDATE`,
+		itemID, puntoID,
+	).Scan(&cantidad)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando ítem de inventario: %w", err)
+	}
+
+	saldo := cantidad
+	switch req.Tipo {
+	case models.MovimientoRecibido:
+		saldo += req.Cantidad
+	case models.MovimientoDespachado:
+		if req.Cantidad > cantidad {
+			return nil, ErrStockInsuficiente
+		}
+		saldo -= req.Cantidad
+	case models.MovimientoAjuste:
+		saldo = req.Cantidad
+	default:
+		return nil, fmt.Errorf("tipo de movimiento desconocido: %q", req.Tipo)
+	}
+
+	_, err = tx.Exec(`UPDATE inventario_items SET cantidad = $1, updated = NOW() WHERE id = $2`, saldo, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando stock: %w", err)
+	}
+	if err := insertMovimiento(tx, itemID, puntoID, req.Tipo, req.Cantidad, saldo, req.Nota, registradoPor); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error guardando movimiento: %w", err)
+	}
+	return GetInventarioItem(puntoID, itemID)
+}
+
+func insertMovimiento(tx *sql.Tx, itemID, puntoID, tipo string, cantidad, saldo float64, nota, registradoPor string) error {
+	id := fmt.Sprintf("mov_%d", time.Now().UnixNano())
+	_, err := tx.Exec(`
+		INSERT INTO inventario_movimientos (id, item_id, punto_id, tipo, cantidad, saldo, nota, registrado_por, created)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+	`, id, itemID, puntoID, tipo, cantidad, saldo, nota, registradoPor)
+	if err != nil {
+		return fmt.Errorf("error guardando movimiento: %w", err)
+	}
+	return nil
+}
+
+// GetMovimientos devuelve los últimos limit movimientos del ítem, del más reciente al más antiguo
+func GetMovimientos(puntoID, itemID string, limit int) ([]models.Movimiento, error) {
+	query := `
+		SELECT id, item_id, punto_id, tipo, cantidad, saldo, nota, registrado_por, created
+		FROM inventario_movimientos
+		WHERE punto_id = $1 AND item_id = $2
+		ORDER BY created DESC, id DESC
+		LIMIT $3
+	`
+
+	rows, err := DB.Query(query, puntoID, itemID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listando movimientos: %w", err)
+	}
+	defer rows.Close()
+
+	movimientos := []models.Movimiento{}
+	for rows.Next() {
+		var m models.Movimiento
+		var nota, registradoPor sql.NullString
+		err := rows.Scan(&m.ID, &m.ItemID, &m.PuntoID, &m.Tipo, &m.Cantidad, &m.Saldo, &nota, &registradoPor, &m.Created)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando movimiento: %w", err)
+		}
+		m.Nota = nota.String
+		m.RegistradoPor = registradoPor.String
+		movimientos = append(movimientos, m)
+	}
+	return movimientos, rows.Err()
+}
+
+// scanInventarioItem lee una fila con inventarioColumns; extra recibe columnas adicionales
+func scanInventarioItem(rows *sql.Rows, extra ...interface{}) (*models.InventarioItem, error) {
+	item := &models.InventarioItem{}
+	var objetivo sql.NullFloat64
+	var created, updated sql.NullString
+
+	dest := []interface{}{
+		&item.ID, &item.PuntoID, &item.Item, &item.Nombre, &item.Categoria, &item.Unidad,
+		&item.Cantidad, &objetivo, &created, &updated,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return nil, fmt.Errorf("error escaneando ítem de inventario: %w", err)
+	}
+
+	if objetivo.Valid {
+		item.Objetivo = &objetivo.Float64
+	}
+	item.Faltante = models.Faltante(item.Cantidad, item.Objetivo)
+	item.Created = created.String
+	item.Updated = updated.String
+	return item, nil
+}
+
+// objetivoArg guarda un objetivo 0 o ausente como NULL (sin objetivo)
+func objetivoArg(objetivo *float64) sql.NullFloat64 {
+	if objetivo == nil || *objetivo <= 0 {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *objetivo, Valid: true}
+}
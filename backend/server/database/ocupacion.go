@@ -0,0 +1,192 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/events"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// ReportOcupacion actualiza la ocupación actual del albergue y guarda el reporte en
+// el historial, en una sola transacción
+func ReportOcupacion(puntoID string, req models.OcupacionRequest, reportadoPor string) (*models.Punto, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var capacidad sql.NullInt64
+	if req.CapacidadTotal != nil {
+		capacidad = sql.NullInt64{Int64: int64(*req.CapacidadTotal), Valid: true}
+	}
+
+	var capacidadTotal sql.NullInt64
+	err = tx.QueryRow(`
+		UPDATE puntos
+		SET ocupacion_actual = $1,
+		    capacidad_total = COALESCE($2, capacidad_total),
+		    ocupacion_actualizada = NOW(),
+		    updated = NOW()
+		WHERE id = $3
+		RETURNING capacidad_total
+	`, req.Ocupacion, capacidad, puntoID).Scan(&capacidadTotal)
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando ocupación: %w", err)
+	}
+
+	id := fmt.Sprintf("ocu_%d", time.Now().UnixNano())
+	_, err = tx.Exec(`
+		INSERT INTO ocupacion_historial (id, punto_id, ocupacion, capacidad_total, nota, reportado_por, created)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+	`, id, puntoID, req.Ocupacion, capacidadTotal.Int64, req.Nota, reportadoPor)
+	if err != nil {
+		return nil, fmt.Errorf("error guardando historial de ocupación: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error guardando ocupación: %w", err)
+	}
+
+	return publishPunto(events.PuntoUpdated, puntoID)
+}
+
+// GetOcupacionHistorial devuelve los últimos limit reportes de ocupación del albergue
+func GetOcupacionHistorial(puntoID string, limit int) ([]models.OcupacionRegistro, error) {
+	query := `
+		SELECT id, punto_id, ocupacion, capacidad_total, nota, reportado_por, created
+		FROM ocupacion_historial
+		WHERE punto_id = $1
+		ORDER BY created DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := DB.Query(query, puntoID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listando historial de ocupación: %w", err)
+	}
+	defer rows.Close()
+
+	registros := []models.OcupacionRegistro{}
+	for rows.Next() {
+		var r models.OcupacionRegistro
+		var capacidad sql.NullInt64
+		var nota, reportadoPor sql.NullString
+		err := rows.Scan(&r.ID, &r.PuntoID, &r.Ocupacion, &capacidad, &nota, &reportadoPor, &r.Created)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando historial de ocupación: %w", err)
+		}
+		r.CapacidadTotal = int(capacidad.Int64)
+		r.Nota = nota.String
+		r.ReportadoPor = reportadoPor.String
+		registros = append(registros, r)
+	}
+	return registros, rows.Err()
+}
+
+// ErrNoEsEncargado se devuelve al asignar un punto a un usuario sin rol encargado
+var ErrNoEsEncargado = errors.New("el usuario no tiene rol encargado")
+
+// AssignEncargado deja al usuario a cargo del punto (puede reportar su ocupación).
+// Solo acepta usuarios con rol encargado.
+func AssignEncargado(puntoID, userID string) error {
+	var rol string
+	err := DB.QueryRow(`SELECT rol FROM users WHERE id = $1`, userID).Scan(&rol)
+	if err == sql.ErrNoRows {
+		return sql.ErrNoRows
+	}
+	if err != nil {
+		return fmt.Errorf("error buscando usuario: %w", err)
+	}
+	if rol != "encargado" {
+		return ErrNoEsEncargado
+	}
+
+	query := `
+		INSERT INTO punto_encargados (punto_id, user_id, created)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (punto_id, user_id) DO NOTHING
+	`
+	_, err = DB.Exec(query, puntoID, userID)
+	if err != nil {
+		return fmt.Errorf("error asignando encargado: %w", err)
+	}
+	return nil
+}
+
+func RemoveEncargado(puntoID, userID string) error {
+	_, err := DB.Exec(`DELETE FROM punto_encargados WHERE punto_id = $1 AND user_id = $2`, puntoID, userID)
+	if err != nil {
+		return fmt.Errorf("error quitando encargado: %w", err)
+	}
+	return nil
+}
+
+// IsEncargado indica si el usuario está a cargo del punto
+func IsEncargado(puntoID, userID string) (bool, error) {
+	var exists bool
+	err := DB.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM punto_encargados WHERE punto_id = $1 AND user_id = $2)`,
+		puntoID, userID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("error buscando encargado: %w", err)
+	}
+	return exists, nil
+}
+
+// GetEncargados lista los usuarios a cargo del punto
+func GetEncargados(puntoID string) ([]models.User, error) {
+	query := `
+		SELECT u.id, u.email, u.name, u.rol, u.organizacion, u.activo, u.created
+		FROM punto_encargados e
+		JOIN users u ON u.id = e.user_id
+		WHERE e.punto_id = $1
+		ORDER BY e.created
+	`
+
+	rows, err := DB.Query(query, puntoID)
+	if err != nil {
+		return nil, fmt.Errorf("error listando encargados: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var u models.User
+		var organizacion sql.NullString
+		if err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.Rol, &organizacion, &u.Activo, &u.Created); err != nil {
+			return nil, fmt.Errorf("error escaneando encargado: %w", err)
+		}
+		u.Organizacion = organizacion.String
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// GetPuntosByEncargado devuelve los puntos a cargo del usuario, en cualquier estado salvo oculto
+func GetPuntosByEncargado(userID string) ([]models.Punto, error) {
+	query := "SELECT " + puntoColumns + ` FROM puntos
+		WHERE estado <> 'oculto'
+		  AND id IN (SELECT punto_id FROM punto_encargados WHERE user_id = $1)
+		ORDER BY nombre`
+
+	rows, err := DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listando puntos del encargado: %w", err)
+	}
+	defer rows.Close()
+
+	puntos := []models.Punto{}
+	for rows.Next() {
+		punto, err := scanPunto(rows)
+		if err != nil {
+			return nil, err
+		}
+		puntos = append(puntos, *punto)
+	}
+	return puntos, rows.Err()
+}
@@ -4,92 +4,224 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/events"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/horario"
 	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/lib/pq"
 )
 
-func GetPuntos(categoria, subtipo, ciudad, estado string, page, limit int) (*models.PuntosListResponse, error) {
-	if estado == "" {
-		estado = "activo"
+// puntoColumns es la lista de columnas que espera scanPunto
+const puntoColumns = `
+		id, nombre, latitud, longitud, direccion, ciudad, categoria, subtipo,
+		categorias_ayuda, nivel_urgencia,
+		contacto_principal, contacto_nombre, horario, horario_osm, estado, entidad_verificadora,
+		fecha_verificacion, notas_internas, capacidad_estado,
+		capacidad_total, ocupacion_actual, ocupacion_actualizada,
+		necesidades_raw, necesidades_tags, nombre_zona, habitado_actualmente,
+		cantidad_ninos, cantidad_adolescentes, cantidad_adultos, cantidad_ancianos,
+		animales_detalle, riesgo_asbesto, foto_asbesto, logistica_llegada,
+		tipos_acceso, requiere_voluntarios, cupo_voluntarios, ` + voluntariosAsignadosSQL + `,
+		tiene_banos, tiene_electricidad, tiene_senal, fallecidos_reportados,
+		evidencia_fotos, archivo_kml,
+		created, updated, created_by, (publicado_en IS NOT NULL)`
+
+// haversineSQL calcula la distancia en metros entre (latitud, longitud) y ($lat, $lng)
+const haversineSQL = `(6371000 * 2 * ASIN(SQRT(
+		POWER(SIN(RADIANS(latitud - %[1]s) / 2), 2) +
+		COS(RADIANS(%[1]s)) * COS(RADIANS(latitud)) *
+		POWER(SIN(RADIANS(longitud - %[2]s) / 2), 2))))`
+
+// searchTSQuery convierte la búsqueda del usuario en tsquery con stemming español y sin acentos.
+// La columna search_vector se genera con la misma configuración (ver migrations/add_puntos_search.sql)
+const searchTSQuery = "websearch_to_tsquery('spanish', f_unaccent(%s))"
+
+// puntosQuery acumula las condiciones y argumentos de una consulta sobre puntos
+type puntosQuery struct {
+	conds     []string
+	args      []interface{}
+	distExpr  string
+	searchArg string
+}
+
+// arg agrega un argumento y devuelve su placeholder ($n)
+func (q *puntosQuery) arg(v interface{}) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *puntosQuery) where(cond string) {
+	q.conds = append(q.conds, cond)
+}
+
+// distanceTo devuelve la expresión SQL de distancia a p, agregando sus argumentos una sola vez
+func (q *puntosQuery) distanceTo(p *models.GeoPoint) string {
+	if q.distExpr == "" {
+		q.distExpr = fmt.Sprintf(haversineSQL, q.arg(p.Lat), q.arg(p.Lng))
 	}
+	return q.distExpr
+}
 
-	query := `
-		SELECT id, nombre, latitud, longitud, direccion, ciudad, categoria, subtipo,
-		       categorias_ayuda, nivel_urgencia,
-		       contacto_principal, contacto_nombre, horario, estado, entidad_verificadora,
-		       fecha_verificacion, notas_internas, capacidad_estado,
-		       necesidades_raw, necesidades_tags, nombre_zona, habitado_actualmente,
-		       cantidad_ninos, cantidad_adolescentes, cantidad_adultos, cantidad_ancianos,
-		       animales_detalle, riesgo_asbesto, foto_asbesto, logistica_llegada,
-		       tipos_acceso, requiere_voluntarios, tiene_banos, tiene_electricidad,
-		       tiene_senal, fallecidos_reportados, evidencia_fotos, archivo_kml,
-		       created, updated, created_by
-		FROM puntos
-		WHERE 1=1
-	`
-	args := []interface{}{}
-	placeholder := 1
+// whereJSONBArray filtra una columna JSONB con array de strings:
+// any = contiene alguno, all = contiene todos, only = no contiene otros que los pedidos
+func (q *puntosQuery) whereJSONBArray(column string, values []string, match string) {
+	if len(values) == 0 {
+		return
+	}
 
-	if estado != "" {
-		query += fmt.Sprintf(" AND estado = $%d", placeholder)
-		args = append(args, estado)
-		placeholder++
+	switch match {
+	case "all":
+		q.where(column + " ?& " + q.arg(pq.Array(values)))
+	case "only":
+		raw, _ := json.Marshal(values)
+		q.where(fmt.Sprintf("(%s <@ %s::jsonb AND jsonb_array_length(%s) > 0)", column, q.arg(string(raw)), column))
+	default:
+		q.where(column + " ?| " + q.arg(pq.Array(values)))
 	}
-	if categoria != "" {
-		query += fmt.Sprintf(" AND categoria = $%d", placeholder)
-		args = append(args, categoria)
-		placeholder++
+}
+
+// search devuelve la tsquery de la búsqueda q, agregando su argumento una sola vez
+func (q *puntosQuery) search(text string) string {
+	if q.searchArg == "" {
+		q.searchArg = q.arg(text)
 	}
-	if subtipo != "" {
-		query += fmt.Sprintf(" AND subtipo = $%d", placeholder)
-		args = append(args, subtipo)
-		placeholder++
+	return fmt.Sprintf(searchTSQuery, q.searchArg)
+}
+
+func (q *puntosQuery) whereSQL() string {
+	clause := " WHERE 1=1"
+	for _, cond := range q.conds {
+		clause += " AND " + cond
 	}
-	if ciudad != "" {
-		query += fmt.Sprintf(" AND ciudad = $%d", placeholder)
-		args = append(args, ciudad)
-		placeholder++
+	return clause
+}
+
+// newPuntosQuery construye las condiciones WHERE comunes a listado y conteo
+func newPuntosQuery(f models.PuntoFilter) *puntosQuery {
+	q := &puntosQuery{}
+
+	if f.Estado != "" {
+		q.where("estado = " + q.arg(f.Estado))
+	}
+	if f.Categoria != "" {
+		q.where("categoria = " + q.arg(f.Categoria))
+	}
+	if f.Subtipo != "" {
+		q.where("subtipo = " + q.arg(f.Subtipo))
+	}
+	if f.Ciudad != "" {
+		q.where("ciudad = " + q.arg(f.Ciudad))
 	}
 
-	// Contar total con los mismos filtros
-	countQuery := "SELECT COUNT(*) FROM puntos WHERE 1=1"
-	countArgs := []interface{}{}
-	countPlaceholder := 1
+	if f.Q != "" {
+		q.where("search_vector @@ " + q.search(f.Q))
+	}
 
-	if estado != "" {
-		countQuery += fmt.Sprintf(" AND estado = $%d", countPlaceholder)
-		countArgs = append(countArgs, estado)
-		countPlaceholder++
+	q.whereJSONBArray("categorias_ayuda", f.CategoriasAyuda, f.CategoriasAyudaMatch)
+	q.whereJSONBArray("tipos_acceso", f.TiposAcceso, f.TiposAccesoMatch)
+	if len(f.NivelesUrgencia) > 0 {
+		q.where("nivel_urgencia = ANY(" + q.arg(pq.Array(f.NivelesUrgencia)) + ")")
 	}
-	if categoria != "" {
-		countQuery += fmt.Sprintf(" AND categoria = $%d", countPlaceholder)
-		countArgs = append(countArgs, categoria)
-		countPlaceholder++
+	if len(f.RiesgoAsbesto) > 0 {
+		q.where("riesgo_asbesto = ANY(" + q.arg(pq.Array(f.RiesgoAsbesto)) + ")")
 	}
-	if subtipo != "" {
-		countQuery += fmt.Sprintf(" AND subtipo = $%d", countPlaceholder)
-		countArgs = append(countArgs, subtipo)
-		countPlaceholder++
+
+	if f.MinCamas > 0 {
+		q.where("capacidad_total - ocupacion_actual >= " + q.arg(f.MinCamas))
 	}
-	if ciudad != "" {
-		countQuery += fmt.Sprintf(" AND ciudad = $%d", countPlaceholder)
-		countArgs = append(countArgs, ciudad)
-		countPlaceholder++
+
+	if f.OpenAt != nil {
+		q.where(openAtSQL("horario_intervalos", q.arg(horario.MinuteOfWeek(*f.OpenAt))))
 	}
 
-	var total int
-	err := DB.QueryRow(countQuery, countArgs...).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("error contando puntos: %w", err)
+	flags := []struct {
+		column string
+		value  *bool
+	}{
+		{"requiere_voluntarios", f.RequiereVoluntarios},
+		{"habitado_actualmente", f.HabitadoActualmente},
+		{"tiene_banos", f.TieneBanos},
+		{"tiene_electricidad", f.TieneElectricidad},
+		{"tiene_senal", f.TieneSenal},
+	}
+	for _, flag := range flags {
+		if flag.value != nil {
+			q.where(flag.column + " = " + q.arg(*flag.value))
+		}
+	}
+
+	if f.BBox != nil {
+		q.where(fmt.Sprintf("latitud BETWEEN %s AND %s", q.arg(f.BBox.MinLat), q.arg(f.BBox.MaxLat)))
+		q.where(fmt.Sprintf("longitud BETWEEN %s AND %s", q.arg(f.BBox.MinLng), q.arg(f.BBox.MaxLng)))
+	}
+
+	if f.Near != nil && f.RadiusM > 0 {
+		// Prefiltro por rectángulo para aprovechar el índice antes de calcular la distancia
+		dLat := f.RadiusM / metersPerDegree
+		dLng := f.RadiusM / (metersPerDegree * math.Max(math.Cos(f.Near.Lat*math.Pi/180), 0.01))
+		q.where(fmt.Sprintf("latitud BETWEEN %s AND %s", q.arg(f.Near.Lat-dLat), q.arg(f.Near.Lat+dLat)))
+		q.where(fmt.Sprintf("longitud BETWEEN %s AND %s", q.arg(f.Near.Lng-dLng), q.arg(f.Near.Lng+dLng)))
+		q.where(fmt.Sprintf("%s <= %s", q.distanceTo(f.Near), q.arg(f.RadiusM)))
 	}
 
-	offset := (page - 1) * limit
-	query += fmt.Sprintf(" ORDER BY created DESC LIMIT $%d OFFSET $%d", placeholder, placeholder+1)
-	args = append(args, limit, offset)
+	return q
+}
+
+// metersPerDegree es la longitud aproximada de un grado de latitud
+const metersPerDegree = 111320.0
 
-	rows, err := DB.Query(query, args...)
+func GetPuntos(f models.PuntoFilter) (*models.PuntosListResponse, error) {
+	if f.Estado == "" {
+		f.Estado = "activo"
+	}
+
+	response := &models.PuntosListResponse{Limit: f.Limit}
+
+	// El conteo total solo se calcula en modo page/limit
+	if !f.UseCursor {
+		countQ := newPuntosQuery(f)
+		var total int
+		err := DB.QueryRow("SELECT COUNT(*) FROM puntos"+countQ.whereSQL(), countQ.args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("error contando puntos: %w", err)
+		}
+		response.Total = &total
+		response.Page = f.Page
+	}
+
+	q := newPuntosQuery(f)
+	selectCols := puntoColumns
+	if f.Near != nil {
+		selectCols += ", " + q.distanceTo(f.Near) + " AS distancia"
+	}
+
+	orderBy := "created DESC, id DESC"
+	switch {
+	case f.Sort == "distance" && f.Near != nil:
+		orderBy = "distancia ASC, created DESC, id DESC"
+	case f.Sort == "relevance" && f.Q != "":
+		orderBy = fmt.Sprintf("ts_rank(search_vector, %s) DESC, created DESC, id DESC", q.search(f.Q))
+	}
+
+	var pagination string
+	if f.UseCursor {
+		// Keyset: (created, id) estrictamente menor que el último punto entregado
+		if f.Cursor != nil {
+			q.where(fmt.Sprintf("(created, id) < (%s::timestamp, %s)", q.arg(f.Cursor.Created), q.arg(f.Cursor.ID)))
+		}
+		// Se pide uno extra para saber si hay página siguiente
+		pagination = " LIMIT " + q.arg(f.Limit+1)
+	} else {
+		offset := (f.Page - 1) * f.Limit
+		pagination = fmt.Sprintf(" LIMIT %s OFFSET %s", q.arg(f.Limit), q.arg(offset))
+	}
+
+	query := "SELECT " + selectCols + " FROM puntos" + q.whereSQL() + " ORDER BY " + orderBy + pagination
+
+	rows, err := DB.Query(query, q.args...)
 	if err != nil {
 		return nil, fmt.Errorf("error listando puntos: %w", err)
 	}
@@ -97,37 +229,63 @@ func GetPuntos(categoria, subtipo, ciudad, estado string, page, limit int) (*mod
 
 	puntos := []models.Punto{}
 	for rows.Next() {
-		punto, err := scanPunto(rows)
+		var distancia sql.NullFloat64
+		var extra []interface{}
+		if f.Near != nil {
+			extra = append(extra, &distancia)
+		}
+
+		punto, err := scanPunto(rows, extra...)
 		if err != nil {
 			return nil, err
 		}
+		if distancia.Valid {
+			d := math.Round(distancia.Float64)
+			punto.DistanciaM = &d
+		}
 		puntos = append(puntos, *punto)
 	}
 
-	return &models.PuntosListResponse{
-		Data:  puntos,
-		Total: total,
-		Page:  page,
-		Limit: limit,
-	}, nil
+	if f.UseCursor && len(puntos) > f.Limit {
+		puntos = puntos[:f.Limit]
+		last := puntos[len(puntos)-1]
+		response.NextCursor = models.PuntoCursor{Created: last.Created, ID: last.ID}.Encode()
+	}
+
+	response.Data = puntos
+	return response, nil
+}
+
+// EachPunto recorre todos los puntos que cumplen el filtro (sin paginar), del más
+// reciente al más antiguo, sin cargarlos todos en memoria. Se usa en las exportaciones.
+func EachPunto(f models.PuntoFilter, fn func(p *models.Punto) error) error {
+	if f.Estado == "" {
+		f.Estado = "activo"
+	}
+
+	q := newPuntosQuery(f)
+	query := "SELECT " + puntoColumns + " FROM puntos" + q.whereSQL() + " ORDER BY created DESC, id DESC"
+
+	rows, err := DB.Query(query, q.args...)
+	if err != nil {
+		return fmt.Errorf("error listando puntos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		punto, err := scanPunto(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(punto); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
 func GetPuntoByID(id string) (*models.Punto, error) {
-	query := `
-		SELECT id, nombre, latitud, longitud, direccion, ciudad, categoria, subtipo,
-		       categorias_ayuda, nivel_urgencia,
-		       contacto_principal, contacto_nombre, horario, estado, entidad_verificadora,
-		       fecha_verificacion, notas_internas, capacidad_estado,
-		       necesidades_raw, necesidades_tags, nombre_zona, habitado_actualmente,
-		       cantidad_ninos, cantidad_adolescentes, cantidad_adultos, cantidad_ancianos,
-		       animales_detalle, riesgo_asbesto, foto_asbesto, logistica_llegada,
-		       tipos_acceso, requiere_voluntarios, tiene_banos, tiene_electricidad,
-		       tiene_senal, fallecidos_reportados, evidencia_fotos, archivo_kml,
-		       created, updated, created_by
-		FROM puntos
-		WHERE id = $1
-		LIMIT 1
-	`
+	query := "SELECT " + puntoColumns + " FROM puntos WHERE id = $1 LIMIT 1"
 
 	rows, err := DB.Query(query, id)
 	if err != nil {
@@ -143,6 +301,25 @@ func GetPuntoByID(id string) (*models.Punto, error) {
 }
 
 func CreatePunto(req models.PuntoCreateRequest, createdBy string) (*models.Punto, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := insertPunto(tx, req, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error creando punto: %w", err)
+	}
+
+	return publishPunto(events.PuntoCreated, id)
+}
+
+// insertPunto inserta el punto dentro de tx y devuelve su id; el llamador lo publica tras el commit
+func insertPunto(tx *sql.Tx, req models.PuntoCreateRequest, createdBy string) (string, error) {
 	id := fmt.Sprintf("pnt_%d", time.Now().UnixNano())
 
 	necesidadesJSON, _ := json.Marshal(req.NecesidadesTags)
@@ -150,6 +327,11 @@ func CreatePunto(req models.PuntoCreateRequest, createdBy string) (*models.Punto
 	tiposAccesoJSON, _ := json.Marshal(req.TiposAcceso)
 	evidenciaJSON, _ := json.Marshal(req.EvidenciaFotos)
 
+	horarioOSM, horarioIntervalos, err := resolveHorario(req.Horario, req.HorarioOSM)
+	if err != nil {
+		return "", err
+	}
+
 	query := `
 		INSERT INTO puntos (
 			id, nombre, latitud, longitud, direccion, ciudad, categoria, subtipo,
@@ -160,15 +342,17 @@ func CreatePunto(req models.PuntoCreateRequest, createdBy string) (*models.Punto
 			cantidad_adultos, cantidad_ancianos, animales_detalle, riesgo_asbesto,
 			foto_asbesto, logistica_llegada, tipos_acceso, requiere_voluntarios,
 			tiene_banos, tiene_electricidad, tiene_senal, fallecidos_reportados,
-			evidencia_fotos, archivo_kml, created, updated, created_by
+			evidencia_fotos, archivo_kml, created, updated, created_by,
+			horario_osm, horario_intervalos, capacidad_total, cupo_voluntarios, publicado_en
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), $16,
 			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31,
-			$32, $33, $34, $35, $36, NOW(), NOW(), $37
+			$32, $33, $34, $35, $36, NOW(), NOW(), $37, $38, $39, $40, $41,
+			CASE WHEN $14 = 'publicado' THEN NOW() END
 		)
 	`
 
-	_, err := DB.Exec(query,
+	_, err = tx.Exec(query,
 		id, req.Nombre, req.Latitud, req.Longitud, req.Direccion, req.Ciudad,
 		req.Categoria, req.Subtipo, string(categoriasJSON), req.NivelUrgencia,
 		req.ContactoPrincipal, req.ContactoNombre, req.Horario, req.Estado,
@@ -179,14 +363,14 @@ func CreatePunto(req models.PuntoCreateRequest, createdBy string) (*models.Punto
 		req.FotoAsbesto, req.LogisticaLlegada, string(tiposAccesoJSON),
 		req.RequiereVoluntarios, req.TieneBanos, req.TieneElectricidad,
 		req.TieneSenal, req.FallecidosReportados, string(evidenciaJSON),
-		req.ArchivoKML, createdBy,
+		req.ArchivoKML, createdBy, horarioOSM, horarioIntervalos, req.CapacidadTotal,
+		req.CupoVoluntarios,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("error creando punto: %w", err)
+		return "", fmt.Errorf("error creando punto: %w", err)
 	}
-
-	return GetPuntoByID(id)
+	return id, nil
 }
 
 func UpdatePunto(id string, req models.PuntoUpdateRequest) (*models.Punto, error) {
@@ -244,8 +428,18 @@ func UpdatePunto(id string, req models.PuntoUpdateRequest) (*models.Punto, error
 		args = append(args, *req.Horario)
 		placeholder++
 	}
+	horarioOSM, horarioIntervalos, horarioChanged, err := horarioUpdate(id, req)
+	if err != nil {
+		return nil, err
+	}
+	if horarioChanged {
+		updates = append(updates, fmt.Sprintf("horario_osm = $%d, horario_intervalos = $%d", placeholder, placeholder+1))
+		args = append(args, horarioOSM, horarioIntervalos)
+		placeholder += 2
+	}
 	if req.Estado != nil {
-		updates = append(updates, fmt.Sprintf("estado = $%d", placeholder))
+		updates = append(updates, fmt.Sprintf("estado = $%d", placeholder),
+			fmt.Sprintf("publicado_en = %s", publicadoEnSQL(placeholder)))
 		args = append(args, *req.Estado)
 		placeholder++
 	}
@@ -264,13 +458,23 @@ func UpdatePunto(id string, req models.PuntoUpdateRequest) (*models.Punto, error
 		args = append(args, *req.CapacidadEstado)
 		placeholder++
 	}
+	if req.CapacidadTotal != nil {
+		updates = append(updates, fmt.Sprintf("capacidad_total = $%d", placeholder))
+		args = append(args, *req.CapacidadTotal)
+		placeholder++
+	}
+	if req.CupoVoluntarios != nil {
+		updates = append(updates, fmt.Sprintf("cupo_voluntarios = $%d", placeholder))
+		args = append(args, *req.CupoVoluntarios)
+		placeholder++
+	}
 	if req.NecesidadesRaw != nil {
 		updates = append(updates, fmt.Sprintf("necesidades_raw = $%d", placeholder))
 		args = append(args, *req.NecesidadesRaw)
 		placeholder++
 	}
 	if req.NecesidadesTags != nil {
-		necesidadesJSON, _ := json.Marshal(*req.NecesidadesTags)
+		necesidadesJSON, _ := json.Marshal(req.NecesidadesTags)
 		updates = append(updates, fmt.Sprintf("necesidades_tags = $%d", placeholder))
 		args = append(args, string(necesidadesJSON))
 		placeholder++
@@ -284,12 +488,12 @@ func UpdatePunto(id string, req models.PuntoUpdateRequest) (*models.Punto, error
 	query := fmt.Sprintf("UPDATE puntos SET %s WHERE id = $%d", strings.Join(updates, ", "), placeholder)
 	args = append(args, id)
 
-	_, err := DB.Exec(query, args...)
+	_, err = DB.Exec(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error actualizando punto: %w", err)
 	}
 
-	return GetPuntoByID(id)
+	return publishPunto(events.PuntoUpdated, id)
 }
 
 func UpdatePuntoEstado(id, estado, verificadoPor string) (*models.Punto, error) {
@@ -297,6 +501,7 @@ func UpdatePuntoEstado(id, estado, verificadoPor string) (*models.Punto, error)
 		UPDATE puntos 
 		SET estado = $1, 
 		    fecha_verificacion = NOW(),
+		    publicado_en = ` + publicadoEnSQL(1) + `,
 		    updated = NOW()
 		WHERE id = $2
 	`
@@ -306,16 +511,37 @@ func UpdatePuntoEstado(id, estado, verificadoPor string) (*models.Punto, error)
 		return nil, fmt.Errorf("error actualizando estado: %w", err)
 	}
 
-	return GetPuntoByID(id)
+	return publishPunto(events.PuntoUpdated, id)
+}
+
+// publicadoEnSQL deja en publicado_en la primera vez que el punto pasa a publicado;
+// $n es el nuevo estado. Así el público solo se entera de que se quitó un punto
+// que alguna vez vio, nunca de envíos que no pasaron la moderación.
+func publicadoEnSQL(n int) string {
+	return fmt.Sprintf("COALESCE(publicado_en, CASE WHEN $%d = 'publicado' THEN NOW() END)", n)
 }
 
 func DeletePunto(id string) error {
 	query := `UPDATE puntos SET estado = 'oculto', updated = NOW() WHERE id = $1`
 	_, err := DB.Exec(query, id)
+	if err == nil {
+		publishPunto(events.PuntoRemoved, id)
+	}
 	return err
 }
 
-func scanPunto(rows *sql.Rows) (*models.Punto, error) {
+// publishPunto lee el punto recién modificado y lo publica a los clientes en vivo
+func publishPunto(eventType, id string) (*models.Punto, error) {
+	punto, err := GetPuntoByID(id)
+	if err != nil {
+		return nil, err
+	}
+	events.Publish(eventType, *punto)
+	return punto, nil
+}
+
+// scanPunto lee una fila con puntoColumns; extra recibe columnas adicionales al final del SELECT
+func scanPunto(rows *sql.Rows, extra ...interface{}) (*models.Punto, error) {
 	punto := &models.Punto{}
 	var necesidadesJSON sql.NullString
 	var evidenciaJSON sql.NullString
@@ -325,23 +551,31 @@ func scanPunto(rows *sql.Rows) (*models.Punto, error) {
 	var created sql.NullString
 	var updated sql.NullString
 	var createdBy sql.NullString
+	var horarioOSM sql.NullString
+	var capacidadTotal, ocupacionActual sql.NullInt64
+	var cupoVoluntarios sql.NullInt64
+	var ocupacionActualizada sql.NullString
 
-	err := rows.Scan(
+	dest := []interface{}{
 		&punto.ID, &punto.Nombre, &punto.Latitud, &punto.Longitud,
 		&punto.Direccion, &punto.Ciudad, &punto.Categoria, &punto.Subtipo,
 		&categoriasJSON, &punto.NivelUrgencia,
-		&punto.ContactoPrincipal, &punto.ContactoNombre, &punto.Horario,
+		&punto.ContactoPrincipal, &punto.ContactoNombre, &punto.Horario, &horarioOSM,
 		&punto.Estado, &punto.EntidadVerificadora, &fechaVerif,
 		&punto.NotasInternas, &punto.CapacidadEstado,
+		&capacidadTotal, &ocupacionActual, &ocupacionActualizada,
 		&punto.NecesidadesRaw, &necesidadesJSON, &punto.NombreZona,
 		&punto.HabitadoActualmente, &punto.CantidadNinos, &punto.CantidadAdolescentes,
 		&punto.CantidadAdultos, &punto.CantidadAncianos, &punto.AnimalesDetalle,
 		&punto.RiesgoAsbesto, &punto.FotoAsbesto, &punto.LogisticaLlegada,
-		&tiposAccesoJSON, &punto.RequiereVoluntarios, &punto.TieneBanos,
+		&tiposAccesoJSON, &punto.RequiereVoluntarios, &cupoVoluntarios,
+		&punto.VoluntariosAsignados, &punto.TieneBanos,
 		&punto.TieneElectricidad, &punto.TieneSenal, &punto.FallecidosReportados,
 		&evidenciaJSON, &punto.ArchivoKML, &created, &updated, &createdBy,
-	)
+		&punto.FuePublicado,
+	}
 
+	err := rows.Scan(append(dest, extra...)...)
 	if err != nil {
 		return nil, fmt.Errorf("error escaneando punto: %w", err)
 	}
@@ -359,6 +593,16 @@ func scanPunto(rows *sql.Rows) (*models.Punto, error) {
 	if createdBy.Valid {
 		punto.CreatedBy = createdBy.String
 	}
+	punto.HorarioOSM = horarioOSM.String
+
+	punto.CapacidadTotal = int(capacidadTotal.Int64)
+	if ocupacionActual.Valid {
+		ocupacion := int(ocupacionActual.Int64)
+		punto.OcupacionActual = &ocupacion
+	}
+	punto.OcupacionActualizada = ocupacionActualizada.String
+	punto.CamasDisponibles = models.CamasDisponibles(punto.CapacidadTotal, punto.OcupacionActual)
+	punto.CupoVoluntarios = int(cupoVoluntarios.Int64)
 
 	// Parsear JSONB de categorias_ayuda
 	if categoriasJSON.Valid && categoriasJSON.String != "" && categoriasJSON.String != "null" {
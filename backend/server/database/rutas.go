@@ -0,0 +1,88 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/events"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// SetPuntoRutas guarda las rutas de un KML/KMZ ya validado (reemplazando las
+// anteriores) y deja url en archivo_kml
+func SetPuntoRutas(puntoID, url string, rutas models.RutasGeoJSON, subidoPor string) (*models.Punto, error) {
+	geojson, err := json.Marshal(rutas)
+	if err != nil {
+		return nil, fmt.Errorf("error serializando rutas: %w", err)
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE puntos SET archivo_kml = $1, updated = NOW() WHERE id = $2`, url, puntoID)
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando archivo_kml: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO punto_rutas (punto_id, geojson, geometrias, subido_por, created)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (punto_id) DO UPDATE
+		SET geojson = EXCLUDED.geojson,
+		    geometrias = EXCLUDED.geometrias,
+		    subido_por = EXCLUDED.subido_por,
+		    created = EXCLUDED.created
+	`, puntoID, string(geojson), len(rutas.Features), subidoPor)
+	if err != nil {
+		return nil, fmt.Errorf("error guardando rutas: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error guardando rutas: %w", err)
+	}
+
+	return publishPunto(events.PuntoUpdated, puntoID)
+}
+
+// GetPuntoRutas devuelve las rutas del punto; sql.ErrNoRows si no tiene
+func GetPuntoRutas(puntoID string) (*models.RutasGeoJSON, error) {
+	var raw string
+	err := DB.QueryRow(`SELECT geojson FROM punto_rutas WHERE punto_id = $1`, puntoID).Scan(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var rutas models.RutasGeoJSON
+	if err := json.Unmarshal([]byte(raw), &rutas); err != nil {
+		return nil, fmt.Errorf("error leyendo rutas: %w", err)
+	}
+	return &rutas, nil
+}
+
+// DeletePuntoRutas quita las rutas del punto y vacía archivo_kml
+func DeletePuntoRutas(puntoID string) (*models.Punto, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM punto_rutas WHERE punto_id = $1`, puntoID); err != nil {
+		return nil, fmt.Errorf("error borrando rutas: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE puntos SET archivo_kml = '', updated = NOW() WHERE id = $1`, puntoID); err != nil {
+		return nil, fmt.Errorf("error actualizando archivo_kml: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error borrando rutas: %w", err)
+	}
+
+	return publishPunto(events.PuntoUpdated, puntoID)
+}
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+const solicitudColumns = `
+		id, punto_id, duplicado_de, remitente_nombre, remitente_contacto, mensaje, origen,
+		estado, motivo_rechazo, moderado_por, fecha_moderacion, created`
+
+// CreateSolicitud guarda un envío público. Si trae un punto, lo crea en estado pendiente
+// (invisible en la API pública) y lo enlaza a la solicitud, ambos en la misma transacción.
+// duplicadoDe es el punto cercano que ya reportaron (ver FindDuplicatePunto), o "".
+func CreateSolicitud(req models.SolicitudCreateRequest, duplicadoDe string) (*models.Solicitud, error) {
+	id := fmt.Sprintf("sol_%d", time.Now().UnixNano())
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var puntoID sql.NullString
+	if req.Punto != nil {
+		pid, err := insertPunto(tx, req.Punto.ToCreateRequest(), "")
+		if err != nil {
+			return nil, err
+		}
+		puntoID = sql.NullString{String: pid, Valid: true}
+	}
+
+	query := `
+		INSERT INTO solicitudes (
+			id, punto_id, duplicado_de, remitente_nombre, remitente_contacto, mensaje, origen, estado, created
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, 'pendiente', NOW())
+	`
+	_, err = tx.Exec(query, id, puntoID, sql.NullString{String: duplicadoDe, Valid: duplicadoDe != ""},
+		req.RemitenteNombre, req.RemitenteContacto, req.Mensaje, req.Origen)
+	if err != nil {
+		return nil, fmt.Errorf("error creando solicitud: %w", err)
+	}
+
+	// El punto pendiente no se publica en el stream: se anuncia cuando la moderación lo aprueba
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error creando solicitud: %w", err)
+	}
+	return GetSolicitudByID(id)
+}
+
+// DuplicateRadiusM es la distancia bajo la cual un punto propuesto puede ser el
+// mismo que uno ya reportado de la misma categoría
+const DuplicateRadiusM = 50.0
+
+// FindDuplicatePunto busca un punto pendiente o publicado de la misma categoría a menos
+// de DuplicateRadiusM del propuesto. Devuelve "" si no hay.
+func FindDuplicatePunto(p models.PuntoPublicoRequest) (string, error) {
+	near := &models.GeoPoint{Lat: p.Latitud, Lng: p.Longitud}
+	q := newPuntosQuery(models.PuntoFilter{
+		Categoria: p.Categoria,
+		Near:      near,
+		RadiusM:   DuplicateRadiusM,
+	})
+	q.where("estado IN ('pendiente', 'publicado')")
+
+	query := "SELECT id FROM puntos" + q.whereSQL() + " ORDER BY " + q.distanceTo(near) + " LIMIT 1"
+
+	var id string
+	err := DB.QueryRow(query, q.args...).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error buscando duplicados: %w", err)
+	}
+	return id, nil
+}
+
+func GetSolicitudByID(id string) (*models.Solicitud, error) {
+	query := "SELECT " + solicitudColumns + " FROM solicitudes WHERE id = $1 LIMIT 1"
+
+	rows, err := DB.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando solicitud: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanSolicitud(rows)
+	}
+	return nil, sql.ErrNoRows
+}
+
+// GetSolicitudes lista la cola de moderación, las más antiguas primero
+func GetSolicitudes(estado string, page, limit int) (*models.SolicitudesListResponse, error) {
+	if estado == "" {
+		estado = models.SolicitudPendiente
+	}
+
+	var total int
+	err := DB.QueryRow("SELECT COUNT(*) FROM solicitudes WHERE estado = $1", estado).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("error contando solicitudes: %w", err)
+	}
+
+	query := "SELECT " + solicitudColumns + `
+		FROM solicitudes
+		WHERE estado = $1
+		ORDER BY created ASC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := DB.Query(query, estado, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listando solicitudes: %w", err)
+	}
+	defer rows.Close()
+
+	solicitudes := []models.Solicitud{}
+	for rows.Next() {
+		solicitud, err := scanSolicitud(rows)
+		if err != nil {
+			return nil, err
+		}
+		solicitudes = append(solicitudes, *solicitud)
+	}
+
+	return &models.SolicitudesListResponse{
+		Data:  solicitudes,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+// ModerarSolicitud registra la decisión de un verificador sobre una solicitud pendiente
+func ModerarSolicitud(id, estado, motivo, moderadoPor, puntoID string) (*models.Solicitud, error) {
+	query := `
+		UPDATE solicitudes
+		SET estado = $1,
+		    motivo_rechazo = NULLIF($2, ''),
+		    moderado_por = $3,
+		    punto_id = COALESCE(NULLIF($4, ''), punto_id),
+		    fecha_moderacion = NOW()
+		WHERE id = $5 AND estado = 'pendiente'
+	`
+
+	result, err := DB.Exec(query, estado, motivo, moderadoPor, puntoID, id)
+	if err != nil {
+		return nil, fmt.Errorf("error moderando solicitud: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return GetSolicitudByID(id)
+}
+
+func scanSolicitud(rows *sql.Rows) (*models.Solicitud, error) {
+	s := &models.Solicitud{}
+	var puntoID, duplicadoDe, remitenteNombre, remitenteContacto, mensaje, origen sql.NullString
+	var motivo, moderadoPor, fechaModeracion, created sql.NullString
+
+	err := rows.Scan(
+		&s.ID, &puntoID, &duplicadoDe, &remitenteNombre, &remitenteContacto, &mensaje, &origen,
+		&s.Estado, &motivo, &moderadoPor, &fechaModeracion, &created,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error escaneando solicitud: %w", err)
+	}
+
+	s.PuntoID = puntoID.String
+	s.DuplicadoDe = duplicadoDe.String
+	s.RemitenteNombre = remitenteNombre.String
+	s.RemitenteContacto = remitenteContacto.String
+	s.Mensaje = mensaje.String
+	s.Origen = origen.String
+	s.MotivoRechazo = motivo.String
+	s.ModeradoPor = moderadoPor.String
+	s.FechaModeracion = fechaModeracion.String
+	s.Created = created.String
+
+	return s, nil
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// GetPuntoStats cuenta los puntos del filtro por comuna, categoría, estado y nivel de
+// urgencia, y suma la población afectada. Sin estado cuenta todos menos los ocultos.
+func GetPuntoStats(f models.PuntoFilter) (*models.PuntoStats, error) {
+	q := newPuntosQuery(f)
+	if f.Estado == "" {
+		q.where("estado <> 'oculto'")
+	}
+
+	query := `
+		SELECT ciudad, nombre_zona, categoria, estado, nivel_urgencia, COUNT(*),
+		       COALESCE(SUM(cantidad_ninos), 0), COALESCE(SUM(cantidad_adolescentes), 0),
+		       COALESCE(SUM(cantidad_adultos), 0), COALESCE(SUM(cantidad_ancianos), 0)
+		FROM puntos` + q.whereSQL() + `
+		GROUP BY ciudad, nombre_zona, categoria, estado, nivel_urgencia`
+
+	rows, err := DB.Query(query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("error calculando estadísticas: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.PuntoStats{
+		PorCategoria:     map[string]int{},
+		PorEstado:        map[string]int{},
+		PorNivelUrgencia: map[string]int{},
+		PorCiudad:        []models.CiudadStats{},
+	}
+	ciudades := map[string]*models.CiudadStats{}
+	zonas := map[string]map[string]*models.ZonaStats{}
+
+	for rows.Next() {
+		var ciudad, zona, categoria, estado, nivel sql.NullString
+		var count int
+		var a models.Afectados
+		err := rows.Scan(&ciudad, &zona, &categoria, &estado, &nivel, &count,
+			&a.Ninos, &a.Adolescentes, &a.Adultos, &a.Ancianos)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando estadísticas: %w", err)
+		}
+		a.Total = a.Ninos + a.Adolescentes + a.Adultos + a.Ancianos
+
+		ciudadKey := orSinDato(ciudad)
+		categoriaKey := orSinDato(categoria)
+		nivelKey := orSinDato(nivel)
+
+		stats.Total += count
+		stats.PorCategoria[categoriaKey] += count
+		stats.PorEstado[orSinDato(estado)] += count
+		stats.PorNivelUrgencia[nivelKey] += count
+		stats.Afectados.Add(a)
+
+		c, ok := ciudades[ciudadKey]
+		if !ok {
+			c = &models.CiudadStats{
+				Ciudad:           ciudadKey,
+				PorCategoria:     map[string]int{},
+				PorNivelUrgencia: map[string]int{},
+			}
+			ciudades[ciudadKey] = c
+			zonas[ciudadKey] = map[string]*models.ZonaStats{}
+		}
+		c.Total += count
+		c.PorCategoria[categoriaKey] += count
+		c.PorNivelUrgencia[nivelKey] += count
+		c.Afectados.Add(a)
+
+		if nombre := strings.TrimSpace(zona.String); nombre != "" {
+			z, ok := zonas[ciudadKey][nombre]
+			if !ok {
+				z = &models.ZonaStats{NombreZona: nombre}
+				zonas[ciudadKey][nombre] = z
+			}
+			z.Puntos += count
+			z.Afectados.Add(a)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo estadísticas: %w", err)
+	}
+
+	for key, c := range ciudades {
+		for _, z := range zonas[key] {
+			c.Zonas = append(c.Zonas, *z)
+		}
+		sort.Slice(c.Zonas, func(i, j int) bool {
+			if c.Zonas[i].Afectados.Total != c.Zonas[j].Afectados.Total {
+				return c.Zonas[i].Afectados.Total > c.Zonas[j].Afectados.Total
+			}
+			return c.Zonas[i].NombreZona < c.Zonas[j].NombreZona
+		})
+		stats.PorCiudad = append(stats.PorCiudad, *c)
+	}
+
+	// Las comunas con más puntos primero
+	sort.Slice(stats.PorCiudad, func(i, j int) bool {
+		a, b := stats.PorCiudad[i], stats.PorCiudad[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Ciudad < b.Ciudad
+	})
+
+	return stats, nil
+}
+
+func orSinDato(s sql.NullString) string {
+	if v := strings.TrimSpace(s.String); v != "" {
+		return v
+	}
+	return models.StatsSinDato
+}
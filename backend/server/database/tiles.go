@@ -0,0 +1,41 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// MaxTilePuntos limita los puntos por tile para acotar el tamaño de la respuesta
+const MaxTilePuntos = 20000
+
+// GetPuntoResumenes devuelve los campos mínimos de los puntos del filtro, para tiles vectoriales
+func GetPuntoResumenes(f models.PuntoFilter) ([]models.PuntoResumen, error) {
+	q := newPuntosQuery(f)
+	query := `SELECT id, nombre, latitud, longitud, categoria, subtipo, nivel_urgencia, estado
+		FROM puntos` + q.whereSQL() + " ORDER BY created DESC LIMIT " + q.arg(MaxTilePuntos)
+
+	rows, err := DB.Query(query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listando puntos para tile: %w", err)
+	}
+	defer rows.Close()
+
+	puntos := []models.PuntoResumen{}
+	for rows.Next() {
+		var p models.PuntoResumen
+		var categoria, subtipo, nivel, estado sql.NullString
+		err := rows.Scan(&p.ID, &p.Nombre, &p.Latitud, &p.Longitud, &categoria, &subtipo, &nivel, &estado)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando punto: %w", err)
+		}
+		p.Categoria = categoria.String
+		p.Subtipo = subtipo.String
+		p.NivelUrgencia = nivel.String
+		p.Estado = estado.String
+		puntos = append(puntos, p)
+	}
+
+	return puntos, rows.Err()
+}
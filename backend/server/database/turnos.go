@@ -0,0 +1,358 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrTurnoLleno se devuelve al tomar un turno sin cupos libres
+	ErrTurnoLleno = errors.New("el turno no tiene cupos libres")
+	// ErrTurnoTerminado se devuelve al tomar un turno que ya terminó
+	ErrTurnoTerminado = errors.New("el turno ya terminó")
+	// ErrTurnoConInscritos se devuelve al mover el horario de un turno que alguien ya tomó
+	ErrTurnoConInscritos = errors.New("el turno ya tiene voluntarios inscritos")
+	// ErrCuposOcupados se devuelve al bajar los cupos de un turno bajo los ya ocupados
+	ErrCuposOcupados = errors.New("hay más voluntarios inscritos que cupos")
+)
+
+// TurnoConflictoError indica que el voluntario ya tiene otro turno que se cruza
+type TurnoConflictoError struct {
+	TurnoID string
+}
+
+func (e *TurnoConflictoError) Error() string {
+	return "el voluntario ya tiene el turno " + e.TurnoID + " a esa hora"
+}
+
+// turnoColumns es la lista de columnas que espera scanTurno (FROM turnos t JOIN puntos p)
+const turnoColumns = `
+		t.id, t.punto_id, t.inicio, t.fin, t.rol, t.cupos,
+		(SELECT COUNT(*) FROM turno_inscripciones ti WHERE ti.turno_id = t.id),
+		t.nota, t.created, t.updated, p.nombre, p.ciudad, p.direccion, p.latitud, p.longitud`
+
+// CreateTurnos crea repeticiones turnos seguidos de largo fin - inicio, en una transacción
+func CreateTurnos(puntoID string, inicio, fin time.Time, req models.TurnoCreateRequest, creadoPor string) ([]models.Turno, error) {
+	repeticiones := req.Repeticiones
+	if repeticiones < 1 {
+		repeticiones = 1
+	}
+	duracion := fin.Sub(inicio)
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]string, repeticiones)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("tur_%d_%d", time.Now().UnixNano(), i)
+		desde := inicio.Add(time.Duration(i) * duracion)
+		_, err := tx.Exec(`
+			INSERT INTO turnos (id, punto_id, inicio, fin, rol, cupos, nota, creado_por, created, updated)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		`, ids[i], puntoID, desde, desde.Add(duracion), strings.TrimSpace(req.Rol), req.Cupos, req.Nota, creadoPor)
+		if err != nil {
+			return nil, fmt.Errorf("error creando turno: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error creando turnos: %w", err)
+	}
+
+	query := "SELECT " + turnoColumns + `
+		FROM turnos t JOIN puntos p ON p.id = t.punto_id
+		WHERE t.id = ANY($1)
+		ORDER BY t.inicio`
+	return queryTurnos(query, pq.Array(ids))
+}
+
+func GetTurnoByID(id string) (*models.Turno, error) {
+	query := "SELECT " + turnoColumns + `
+		FROM turnos t JOIN puntos p ON p.id = t.punto_id
+		WHERE t.id = $1`
+
+	turnos, err := queryTurnos(query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(turnos) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &turnos[0], nil
+}
+
+// GetTurnos lista turnos por hora de inicio
+func GetTurnos(f models.TurnoFilter) ([]models.Turno, error) {
+	conds := []string{"t.fin > $1"}
+	args := []interface{}{f.Desde}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.PuntoID != "" {
+		conds = append(conds, "t.punto_id = "+arg(f.PuntoID))
+	}
+	if f.VoluntarioID != "" {
+		conds = append(conds, "t.id IN (SELECT turno_id FROM turno_inscripciones WHERE voluntario_id = "+arg(f.VoluntarioID)+")")
+	}
+	if f.Ciudad != "" {
+		conds = append(conds, "p.ciudad = "+arg(f.Ciudad))
+	}
+	if f.Rol != "" {
+		conds = append(conds, "f_unaccent(LOWER(t.rol)) = f_unaccent(LOWER("+arg(f.Rol)+"))")
+	}
+	if !f.Hasta.IsZero() {
+		conds = append(conds, "t.inicio < "+arg(f.Hasta))
+	}
+	if f.SoloLibres {
+		conds = append(conds, "t.cupos > (SELECT COUNT(*) FROM turno_inscripciones ti WHERE ti.turno_id = t.id)")
+	}
+	if f.Publicados {
+		conds = append(conds, "p.estado = 'publicado'")
+	} else {
+		conds = append(conds, "p.estado <> 'oculto'")
+	}
+
+	query := "SELECT " + turnoColumns + `
+		FROM turnos t JOIN puntos p ON p.id = t.punto_id
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY t.inicio, t.id
+		LIMIT ` + arg(f.Limit)
+
+	return queryTurnos(query, args...)
+}
+
+// AttachInscritos completa los voluntarios inscritos en cada turno (planilla del punto)
+func AttachInscritos(turnos []models.Turno) error {
+	if len(turnos) == 0 {
+		return nil
+	}
+	index := map[string]int{}
+	ids := make([]string, len(turnos))
+	for i, t := range turnos {
+		index[t.ID] = i
+		ids[i] = t.ID
+		turnos[i].Inscritos = []models.TurnoInscrito{}
+	}
+
+	rows, err := DB.Query(`
+		SELECT ti.turno_id, v.id, v.nombre, v.telefono, v.email, ti.created
+		FROM turno_inscripciones ti
+		JOIN voluntarios v ON v.id = ti.voluntario_id
+		WHERE ti.turno_id = ANY($1)
+		ORDER BY ti.created
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error listando inscritos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var turnoID string
+		var inscrito models.TurnoInscrito
+		var telefono, email sql.NullString
+		if err := rows.Scan(&turnoID, &inscrito.VoluntarioID, &inscrito.Nombre, &telefono, &email, &inscrito.Created); err != nil {
+			return fmt.Errorf("error escaneando inscrito: %w", err)
+		}
+		inscrito.Telefono = telefono.String
+		inscrito.Email = email.String
+		i := index[turnoID]
+		turnos[i].Inscritos = append(turnos[i].Inscritos, inscrito)
+	}
+	return rows.Err()
+}
+
+// UpdateTurno aplica los cambios del turno del punto. inicio y fin (nil = sin cambio)
+// ya vienen validados; moverlos requiere que nadie lo haya tomado.
+func UpdateTurno(puntoID, id string, inicio, fin *time.Time, req models.TurnoUpdateRequest) (*models.Turno, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE: nadie puede tomar el turno mientras se revisan los cupos
+	var ocupados int
+	err = tx.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM turno_inscripciones ti WHERE ti.turno_id = t.id)
+		FROM turnos t WHERE t.id = $1 AND t.punto_id = $2 FOR UPDATE
+	`, id, puntoID).Scan(&ocupados)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando turno: %w", err)
+	}
+	if (inicio != nil || fin != nil) && ocupados > 0 {
+		return nil, ErrTurnoConInscritos
+	}
+	if req.Cupos != nil && *req.Cupos < ocupados {
+		return nil, ErrCuposOcupados
+	}
+
+	updates := []string{}
+	args := []interface{}{}
+	set := func(column string, v interface{}) {
+		args = append(args, v)
+		updates = append(updates, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if inicio != nil {
+		set("inicio", *inicio)
+	}
+	if fin != nil {
+		set("fin", *fin)
+	}
+	if req.Rol != nil {
+		set("rol", strings.TrimSpace(*req.Rol))
+	}
+	if req.Cupos != nil {
+		set("cupos", *req.Cupos)
+	}
+	if req.Nota != nil {
+		set("nota", *req.Nota)
+	}
+
+	if len(updates) > 0 {
+		updates = append(updates, "updated = NOW()")
+		args = append(args, id)
+		query := fmt.Sprintf("UPDATE turnos SET %s WHERE id = $%d", strings.Join(updates, ", "), len(args))
+		if _, err := tx.Exec(query, args...); err != nil {
+			return nil, fmt.Errorf("error actualizando turno: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error actualizando turno: %w", err)
+	}
+	return GetTurnoByID(id)
+}
+
+// DeleteTurno borra el turno del punto con sus inscripciones
+func DeleteTurno(puntoID, id string) error {
+	res, err := DB.Exec(`DELETE FROM turnos WHERE id = $1 AND punto_id = $2`, id, puntoID)
+	if err != nil {
+		return fmt.Errorf("error borrando turno: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// TomarTurno inscribe al voluntario en el turno si quedan cupos y no tiene otro turno
+// que se cruce. Tomar un turno que ya tenía no hace nada.
+func TomarTurno(turnoID, voluntarioID string) (*models.Turno, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Se bloquean el voluntario (dos turnos suyos tomados a la vez no pueden cruzarse)
+	// y el turno (dos voluntarios no pueden tomar el último cupo)
+	if _, err := tx.Exec(`SELECT id FROM voluntarios WHERE id = $1 FOR UPDATE`, voluntarioID); err != nil {
+		return nil, fmt.Errorf("error bloqueando voluntario: %w", err)
+	}
+	var inicio, fin time.Time
+	var cupos int
+	err = tx.QueryRow(`SELECT inicio, fin, cupos FROM turnos WHERE id = $1 FOR UPDATE`, turnoID).Scan(&inicio, &fin, &cupos)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando turno: %w", err)
+	}
+	if !fin.After(time.Now()) {
+		return nil, ErrTurnoTerminado
+	}
+
+	var inscrito bool
+	var ocupados int
+	err = tx.QueryRow(`
+		SELECT COALESCE(BOOL_OR(voluntario_id = $2), FALSE), COUNT(*)
+		FROM turno_inscripciones WHERE turno_id = $1
+	`, turnoID, voluntarioID).Scan(&inscrito, &ocupados)
+	if err != nil {
+		return nil, fmt.Errorf("error contando inscritos: %w", err)
+	}
+	if inscrito {
+		return GetTurnoByID(turnoID)
+	}
+	if ocupados >= cupos {
+		return nil, ErrTurnoLleno
+	}
+
+	var conflicto string
+	err = tx.QueryRow(`
+		SELECT t.id FROM turno_inscripciones ti
+		JOIN turnos t ON t.id = ti.turno_id
+		WHERE ti.voluntario_id = $1 AND t.inicio < $3 AND t.fin > $2
+		LIMIT 1
+	`, voluntarioID, inicio, fin).Scan(&conflicto)
+	if err == nil {
+		return nil, &TurnoConflictoError{TurnoID: conflicto}
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error buscando turnos cruzados: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO turno_inscripciones (turno_id, voluntario_id, created) VALUES ($1, $2, NOW())
+	`, turnoID, voluntarioID)
+	if err != nil {
+		return nil, fmt.Errorf("error tomando turno: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error tomando turno: %w", err)
+	}
+	return GetTurnoByID(turnoID)
+}
+
+// SoltarTurno libera el cupo del voluntario; sql.ErrNoRows si no lo tenía
+func SoltarTurno(turnoID, voluntarioID string) (*models.Turno, error) {
+	res, err := DB.Exec(`DELETE FROM turno_inscripciones WHERE turno_id = $1 AND voluntario_id = $2`, turnoID, voluntarioID)
+	if err != nil {
+		return nil, fmt.Errorf("error soltando turno: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return GetTurnoByID(turnoID)
+}
+
+func queryTurnos(query string, args ...interface{}) ([]models.Turno, error) {
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listando turnos: %w", err)
+	}
+	defer rows.Close()
+
+	turnos := []models.Turno{}
+	for rows.Next() {
+		var t models.Turno
+		var nota, created, updated, ciudad, direccion sql.NullString
+		err := rows.Scan(
+			&t.ID, &t.PuntoID, &t.Inicio, &t.Fin, &t.Rol, &t.Cupos, &t.Ocupados,
+			&nota, &created, &updated, &t.PuntoNombre, &ciudad, &direccion, &t.Latitud, &t.Longitud,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando turno: %w", err)
+		}
+		t.Nota = nota.String
+		t.Created = created.String
+		t.Updated = updated.String
+		t.Ciudad = ciudad.String
+		t.Direccion = direccion.String
+		t.Libres = t.Cupos - t.Ocupados
+		if t.Libres < 0 {
+			t.Libres = 0
+		}
+		turnos = append(turnos, t)
+	}
+	return turnos, rows.Err()
+}
@@ -0,0 +1,398 @@
+package database
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/events"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/matching"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/lib/pq"
+)
+
+// voluntariosAsignadosSQL cuenta los voluntarios asignados a la fila actual de puntos
+const voluntariosAsignadosSQL = "(SELECT COUNT(*) FROM voluntario_asignaciones va WHERE va.punto_id = puntos.id)"
+
+// voluntarioColumns es la lista de columnas que espera scanVoluntario
+const voluntarioColumns = `
+		v.id, v.nombre, v.telefono, v.email, v.habilidades, v.ciudad, v.vehiculo,
+		v.disponibilidad, v.disponibilidad_osm, v.activo, v.notas, v.created, v.updated,
+		COALESCE((SELECT jsonb_agg(a.punto_id ORDER BY a.created)
+		          FROM voluntario_asignaciones a WHERE a.voluntario_id = v.id), '[]')`
+
+// CreateVoluntario inscribe a un voluntario. Las habilidades se guardan normalizadas
+// y la disponibilidad estructurada se deriva del texto si no viene en formato OSM.
+// Devuelve también el token con que el voluntario toma turnos; solo se guarda su hash.
+func CreateVoluntario(req models.VoluntarioCreateRequest) (*models.Voluntario, string, error) {
+	id := fmt.Sprintf("vol_%d", time.Now().UnixNano())
+	token, tokenHash := newToken()
+
+	vehiculo := req.Vehiculo
+	if vehiculo == "" {
+		vehiculo = "pie"
+	}
+	habilidadesJSON, _ := json.Marshal(normalizeHabilidades(req.Habilidades))
+
+	disponibilidadOSM, intervalos, err := resolveHorario(req.Disponibilidad, req.DisponibilidadOSM)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `
+		INSERT INTO voluntarios (
+			id, nombre, telefono, email, habilidades, ciudad, vehiculo,
+			disponibilidad, disponibilidad_osm, disponibilidad_intervalos, token_hash, activo, created, updated
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, TRUE, NOW(), NOW())
+	`
+	_, err = DB.Exec(query,
+		id, strings.TrimSpace(req.Nombre), req.Telefono, req.Email, string(habilidadesJSON),
+		strings.TrimSpace(req.Ciudad), vehiculo, req.Disponibilidad, disponibilidadOSM, intervalos, tokenHash,
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creando voluntario: %w", err)
+	}
+
+	voluntario, err := GetVoluntarioByID(id)
+	return voluntario, token, err
+}
+
+// GetVoluntarioByToken busca al voluntario dueño del token; sql.ErrNoRows si no existe
+func GetVoluntarioByToken(token string) (*models.Voluntario, error) {
+	query := "SELECT " + voluntarioColumns + " FROM voluntarios v WHERE v.token_hash = $1 LIMIT 1"
+
+	voluntarios, err := queryVoluntarios(query, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if len(voluntarios) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &voluntarios[0], nil
+}
+
+// ResetVoluntarioToken genera un token nuevo e invalida el anterior (p. ej. si el
+// voluntario lo perdió o se filtró su enlace de calendario)
+func ResetVoluntarioToken(id string) (string, error) {
+	token, tokenHash := newToken()
+	res, err := DB.Exec(`UPDATE voluntarios SET token_hash = $1, updated = NOW() WHERE id = $2`, tokenHash, id)
+	if err != nil {
+		return "", fmt.Errorf("error generando token: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return "", sql.ErrNoRows
+	}
+	return token, nil
+}
+
+// newToken genera un token aleatorio y el hash que se guarda (voluntarios, compromisos)
+func newToken() (token, hash string) {
+	random := make([]byte, 24)
+	rand.Read(random)
+	token = hex.EncodeToString(random)
+	return token, hashToken(token)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func GetVoluntarioByID(id string) (*models.Voluntario, error) {
+	query := "SELECT " + voluntarioColumns + " FROM voluntarios v WHERE v.id = $1 LIMIT 1"
+
+	rows, err := DB.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando voluntario: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanVoluntario(rows)
+	}
+	return nil, sql.ErrNoRows
+}
+
+// GetVoluntarios lista voluntarios, los inscritos más recientes primero
+func GetVoluntarios(f models.VoluntarioFilter) ([]models.Voluntario, error) {
+	conds := []string{"TRUE"}
+	args := []interface{}{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Ciudad != "" {
+		conds = append(conds, "f_unaccent(LOWER(v.ciudad)) = f_unaccent(LOWER("+arg(f.Ciudad)+"))")
+	}
+	if f.Habilidad != "" {
+		conds = append(conds, "v.habilidades ? "+arg(f.Habilidad))
+	}
+	if len(f.Vehiculos) > 0 {
+		conds = append(conds, "v.vehiculo = ANY("+arg(pq.Array(f.Vehiculos))+")")
+	}
+	if f.Activo != nil {
+		conds = append(conds, "v.activo = "+arg(*f.Activo))
+	}
+	if f.Libres {
+		conds = append(conds, "NOT EXISTS (SELECT 1 FROM voluntario_asignaciones a WHERE a.voluntario_id = v.id)")
+	}
+	if f.Excluir != "" {
+		conds = append(conds, "NOT EXISTS (SELECT 1 FROM voluntario_asignaciones a WHERE a.voluntario_id = v.id AND a.punto_id = "+arg(f.Excluir)+")")
+	}
+	if f.DisponibleMinuto >= 0 {
+		conds = append(conds, openAtSQL("v.disponibilidad_intervalos", arg(f.DisponibleMinuto)))
+	}
+
+	query := "SELECT " + voluntarioColumns + `
+		FROM voluntarios v
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY v.created DESC, v.id DESC
+		LIMIT ` + arg(f.Limit)
+
+	return queryVoluntarios(query, args...)
+}
+
+// UpdateVoluntario aplica los campos enviados. Cambiar la disponibilidad (texto u OSM)
+// recalcula los intervalos igual que el horario de un punto.
+func UpdateVoluntario(id string, req models.VoluntarioUpdateRequest) (*models.Voluntario, error) {
+	actual, err := GetVoluntarioByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := []string{}
+	args := []interface{}{}
+	set := func(column string, v interface{}) {
+		args = append(args, v)
+		updates = append(updates, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if req.Nombre != nil {
+		set("nombre", strings.TrimSpace(*req.Nombre))
+	}
+	if req.Telefono != nil {
+		set("telefono", *req.Telefono)
+	}
+	if req.Email != nil {
+		set("email", *req.Email)
+	}
+	if req.Habilidades != nil {
+		habilidadesJSON, _ := json.Marshal(normalizeHabilidades(*req.Habilidades))
+		set("habilidades", string(habilidadesJSON))
+	}
+	if req.Ciudad != nil {
+		set("ciudad", strings.TrimSpace(*req.Ciudad))
+	}
+	if req.Vehiculo != nil {
+		set("vehiculo", *req.Vehiculo)
+	}
+	if req.Activo != nil {
+		set("activo", *req.Activo)
+	}
+	if req.Notas != nil {
+		set("notas", *req.Notas)
+	}
+	if req.Disponibilidad != nil || req.DisponibilidadOSM != nil {
+		texto := actual.Disponibilidad
+		if req.Disponibilidad != nil {
+			texto = *req.Disponibilidad
+			set("disponibilidad", texto)
+		}
+		explicit := ""
+		if req.DisponibilidadOSM != nil {
+			explicit = *req.DisponibilidadOSM
+		}
+		osm, intervalos, err := resolveHorario(texto, explicit)
+		if err != nil {
+			return nil, err
+		}
+		set("disponibilidad_osm", osm)
+		set("disponibilidad_intervalos", intervalos)
+	}
+
+	if len(updates) == 0 {
+		return actual, nil
+	}
+
+	updates = append(updates, "updated = NOW()")
+	args = append(args, id)
+	query := fmt.Sprintf("UPDATE voluntarios SET %s WHERE id = $%d", strings.Join(updates, ", "), len(args))
+	if _, err := DB.Exec(query, args...); err != nil {
+		return nil, fmt.Errorf("error actualizando voluntario: %w", err)
+	}
+
+	return GetVoluntarioByID(id)
+}
+
+// DeleteVoluntario borra la ficha y sus asignaciones (p. ej. si la persona lo pide)
+func DeleteVoluntario(id string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`DELETE FROM voluntario_asignaciones WHERE voluntario_id = $1 RETURNING punto_id`, id)
+	if err != nil {
+		return fmt.Errorf("error quitando asignaciones: %w", err)
+	}
+	var puntos []string
+	for rows.Next() {
+		var puntoID string
+		if err := rows.Scan(&puntoID); err != nil {
+			rows.Close()
+			return fmt.Errorf("error escaneando asignación: %w", err)
+		}
+		puntos = append(puntos, puntoID)
+	}
+	rows.Close()
+
+	res, err := tx.Exec(`DELETE FROM voluntarios WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error borrando voluntario: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error borrando voluntario: %w", err)
+	}
+
+	// Los puntos en que estaba asignado cambian su conteo
+	for _, puntoID := range puntos {
+		publishPunto(events.PuntoUpdated, puntoID)
+	}
+	return nil
+}
+
+// GetPuntoVoluntarios lista los voluntarios asignados al punto, por orden de asignación
+func GetPuntoVoluntarios(puntoID string) ([]models.Voluntario, error) {
+	query := "SELECT " + voluntarioColumns + `
+		FROM voluntarios v
+		JOIN voluntario_asignaciones va ON va.voluntario_id = v.id
+		WHERE va.punto_id = $1
+		ORDER BY va.created`
+
+	return queryVoluntarios(query, puntoID)
+}
+
+// AssignVoluntario asigna el voluntario al punto (idempotente) y publica el nuevo conteo
+func AssignVoluntario(puntoID, voluntarioID, asignadoPor string) (*models.Punto, error) {
+	query := `
+		INSERT INTO voluntario_asignaciones (punto_id, voluntario_id, asignado_por, created)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (punto_id, voluntario_id) DO NOTHING
+	`
+	if _, err := DB.Exec(query, puntoID, voluntarioID, asignadoPor); err != nil {
+		return nil, fmt.Errorf("error asignando voluntario: %w", err)
+	}
+	return publishPunto(events.PuntoUpdated, puntoID)
+}
+
+// RemoveVoluntario quita la asignación; sql.ErrNoRows si no estaba asignado
+func RemoveVoluntario(puntoID, voluntarioID string) (*models.Punto, error) {
+	res, err := DB.Exec(`DELETE FROM voluntario_asignaciones WHERE punto_id = $1 AND voluntario_id = $2`, puntoID, voluntarioID)
+	if err != nil {
+		return nil, fmt.Errorf("error quitando voluntario: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return publishPunto(events.PuntoUpdated, puntoID)
+}
+
+// GetPuntosNecesitanVoluntarios devuelve los puntos (salvo ocultos) que piden voluntarios,
+// los que más faltan primero
+func GetPuntosNecesitanVoluntarios(ciudad string) ([]models.Punto, error) {
+	query := "SELECT " + puntoColumns + ` FROM puntos
+		WHERE estado <> 'oculto'
+		  AND (requiere_voluntarios OR cupo_voluntarios > 0)
+		  AND ($1 = '' OR ciudad = $1)
+		ORDER BY GREATEST(COALESCE(cupo_voluntarios, 0) - ` + voluntariosAsignadosSQL + `, 0) DESC, nombre`
+
+	rows, err := DB.Query(query, ciudad)
+	if err != nil {
+		return nil, fmt.Errorf("error listando puntos que piden voluntarios: %w", err)
+	}
+	defer rows.Close()
+
+	puntos := []models.Punto{}
+	for rows.Next() {
+		punto, err := scanPunto(rows)
+		if err != nil {
+			return nil, err
+		}
+		puntos = append(puntos, *punto)
+	}
+	return puntos, rows.Err()
+}
+
+func queryVoluntarios(query string, args ...interface{}) ([]models.Voluntario, error) {
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listando voluntarios: %w", err)
+	}
+	defer rows.Close()
+
+	voluntarios := []models.Voluntario{}
+	for rows.Next() {
+		v, err := scanVoluntario(rows)
+		if err != nil {
+			return nil, err
+		}
+		voluntarios = append(voluntarios, *v)
+	}
+	return voluntarios, rows.Err()
+}
+
+// scanVoluntario lee una fila con voluntarioColumns
+func scanVoluntario(rows *sql.Rows) (*models.Voluntario, error) {
+	v := &models.Voluntario{}
+	var telefono, email, disponibilidad, disponibilidadOSM, notas, updated sql.NullString
+	var habilidadesJSON, puntosJSON []byte
+
+	err := rows.Scan(
+		&v.ID, &v.Nombre, &telefono, &email, &habilidadesJSON, &v.Ciudad, &v.Vehiculo,
+		&disponibilidad, &disponibilidadOSM, &v.Activo, &notas, &v.Created, &updated,
+		&puntosJSON,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error escaneando voluntario: %w", err)
+	}
+
+	v.Telefono = telefono.String
+	v.Email = email.String
+	v.Disponibilidad = disponibilidad.String
+	v.DisponibilidadOSM = disponibilidadOSM.String
+	v.Notas = notas.String
+	v.Updated = updated.String
+
+	v.Habilidades = []string{}
+	if len(habilidadesJSON) > 0 {
+		json.Unmarshal(habilidadesJSON, &v.Habilidades)
+	}
+	v.Puntos = []string{}
+	json.Unmarshal(puntosJSON, &v.Puntos)
+	return v, nil
+}
+
+// normalizeHabilidades normaliza y quita duplicados, conservando el orden
+func normalizeHabilidades(habilidades []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, h := range habilidades {
+		h = matching.Normalize(h)
+		if h == "" || seen[h] {
+			continue
+		}
+		seen[h] = true
+		out = append(out, h)
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+// Package events distribuye en memoria los cambios de puntos a los clientes
+// conectados por Server-Sent Events (GET /api/puntos/stream).
+package events
+
+import (
+	"log"
+	"sync"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// Tipos de evento sobre un punto
+const (
+	PuntoCreated = "created"
+	PuntoUpdated = "updated"
+	PuntoRemoved = "removed"
+)
+
+// Event es un cambio sobre un punto. En PuntoRemoved solo se garantiza Punto.ID.
+type Event struct {
+	Type  string
+	Punto models.Punto
+}
+
+// subscriberBuffer es cuántos eventos puede acumular un cliente lento antes de perderlos
+const subscriberBuffer = 64
+
+type Subscription struct {
+	C chan Event
+}
+
+type Broker struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: map[*Subscription]struct{}{}}
+}
+
+func (b *Broker) Subscribe() *Subscription {
+	sub := &Subscription{C: make(chan Event, subscriberBuffer)}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	delete(b.subs, sub)
+	b.mu.Unlock()
+}
+
+// Publish entrega el evento a todos los suscriptores sin bloquear: si el buffer
+// de un cliente está lleno, ese cliente pierde el evento.
+func (b *Broker) Publish(e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		select {
+		case sub.C <- e:
+		default:
+			log.Printf("⚠️ Suscriptor lento, evento %s de %s descartado", e.Type, e.Punto.ID)
+		}
+	}
+}
+
+// Default es el broker del proceso
+var Default = NewBroker()
+
+func Subscribe() *Subscription {
+	return Default.Subscribe()
+}
+
+func Unsubscribe(sub *Subscription) {
+	Default.Unsubscribe(sub)
+}
+
+func Publish(eventType string, p models.Punto) {
+	Default.Publish(Event{Type: eventType, Punto: p})
+}
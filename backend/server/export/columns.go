@@ -0,0 +1,130 @@
+package export
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// Visibilidad de una columna, igual que las vistas de models.Punto.ForRole
+const (
+	visiblePublic      = ""
+	visibleVerificador = "verificador"
+	visibleAdmin       = "admin"
+)
+
+// Column es un campo de Punto exportado como texto
+type Column struct {
+	Name    string
+	Visible string
+	Value   func(p *models.Punto) string
+}
+
+// Columns son todas las columnas exportables, en el orden de salida. Quien consume
+// el CSV lee por posición: una columna nueva se agrega al final de la tabla.
+var Columns = []Column{
+	{"id", visiblePublic, func(p *models.Punto) string { return p.ID }},
+	{"nombre", visiblePublic, func(p *models.Punto) string { return p.Nombre }},
+	{"latitud", visiblePublic, func(p *models.Punto) string { return formatCoord(p.Latitud) }},
+	{"longitud", visiblePublic, func(p *models.Punto) string { return formatCoord(p.Longitud) }},
+	{"direccion", visiblePublic, func(p *models.Punto) string { return p.Direccion }},
+	{"ciudad", visiblePublic, func(p *models.Punto) string { return p.Ciudad }},
+	{"nombre_zona", visiblePublic, func(p *models.Punto) string { return p.NombreZona }},
+	{"categoria", visiblePublic, func(p *models.Punto) string { return p.Categoria }},
+	{"subtipo", visiblePublic, func(p *models.Punto) string { return p.Subtipo }},
+	{"categorias_ayuda", visiblePublic, func(p *models.Punto) string { return joinList(p.CategoriasAyuda) }},
+	{"nivel_urgencia", visiblePublic, func(p *models.Punto) string { return p.NivelUrgencia }},
+	{"estado", visiblePublic, func(p *models.Punto) string { return p.Estado }},
+	{"contacto_nombre", visiblePublic, func(p *models.Punto) string { return p.ContactoNombre }},
+	{"contacto_principal", visiblePublic, func(p *models.Punto) string { return p.ContactoPrincipal }},
+	{"horario", visiblePublic, func(p *models.Punto) string { return p.Horario }},
+	{"entidad_verificadora", visiblePublic, func(p *models.Punto) string { return p.EntidadVerificadora }},
+	{"fecha_verificacion", visiblePublic, func(p *models.Punto) string { return p.FechaVerificacion }},
+	{"capacidad_estado", visiblePublic, func(p *models.Punto) string { return p.CapacidadEstado }},
+	{"necesidades_raw", visiblePublic, func(p *models.Punto) string { return p.NecesidadesRaw }},
+	{"necesidades_tags", visiblePublic, func(p *models.Punto) string { return formatJSON(p.NecesidadesTags) }},
+	{"habitado_actualmente", visiblePublic, func(p *models.Punto) string { return strconv.FormatBool(p.HabitadoActualmente) }},
+	{"cantidad_ninos", visiblePublic, func(p *models.Punto) string { return strconv.Itoa(p.CantidadNinos) }},
+	{"cantidad_adolescentes", visiblePublic, func(p *models.Punto) string { return strconv.Itoa(p.CantidadAdolescentes) }},
+	{"cantidad_adultos", visiblePublic, func(p *models.Punto) string { return strconv.Itoa(p.CantidadAdultos) }},
+	{"cantidad_ancianos", visiblePublic, func(p *models.Punto) string { return strconv.Itoa(p.CantidadAncianos) }},
+	{"animales_detalle", visiblePublic, func(p *models.Punto) string { return p.AnimalesDetalle }},
+	{"riesgo_asbesto", visiblePublic, func(p *models.Punto) string { return p.RiesgoAsbesto }},
+	{"logistica_llegada", visiblePublic, func(p *models.Punto) string { return p.LogisticaLlegada }},
+	{"tipos_acceso", visiblePublic, func(p *models.Punto) string { return joinList(p.TiposAcceso) }},
+	{"requiere_voluntarios", visiblePublic, func(p *models.Punto) string { return strconv.FormatBool(p.RequiereVoluntarios) }},
+	{"tiene_banos", visiblePublic, func(p *models.Punto) string { return strconv.FormatBool(p.TieneBanos) }},
+	{"tiene_electricidad", visiblePublic, func(p *models.Punto) string { return strconv.FormatBool(p.TieneElectricidad) }},
+	{"tiene_senal", visiblePublic, func(p *models.Punto) string { return strconv.FormatBool(p.TieneSenal) }},
+	{"created", visiblePublic, func(p *models.Punto) string { return p.Created }},
+	{"updated", visiblePublic, func(p *models.Punto) string { return p.Updated }},
+	{"notas_internas", visibleVerificador, func(p *models.Punto) string { return p.NotasInternas }},
+	{"created_by", visibleVerificador, func(p *models.Punto) string { return p.CreatedBy }},
+	{"fallecidos_reportados", visibleAdmin, func(p *models.Punto) string { return strconv.FormatBool(p.FallecidosReportados) }},
+	{"horario_osm", visiblePublic, func(p *models.Punto) string { return p.HorarioOSM }},
+	{"capacidad_total", visiblePublic, func(p *models.Punto) string { return formatCapacidad(p.CapacidadTotal) }},
+	{"ocupacion_actual", visiblePublic, func(p *models.Punto) string { return formatOptionalInt(p.OcupacionActual) }},
+	{"camas_disponibles", visiblePublic, func(p *models.Punto) string { return formatOptionalInt(p.CamasDisponibles) }},
+	{"ocupacion_actualizada", visiblePublic, func(p *models.Punto) string { return p.OcupacionActualizada }},
+	{"cupo_voluntarios", visiblePublic, func(p *models.Punto) string { return formatCapacidad(p.CupoVoluntarios) }},
+	{"voluntarios_asignados", visiblePublic, func(p *models.Punto) string { return strconv.Itoa(p.VoluntariosAsignados) }},
+}
+
+// ColumnsFor devuelve las columnas que puede ver el rol (mismas reglas que Punto.ForRole)
+func ColumnsFor(rol string) []Column {
+	cols := make([]Column, 0, len(Columns))
+	for _, c := range Columns {
+		if canSee(rol, c.Visible) {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+func canSee(rol, visible string) bool {
+	switch visible {
+	case visiblePublic:
+		return true
+	case visibleVerificador:
+		return rol == "verificador" || rol == "admin" || rol == "superadmin"
+	default:
+		return rol == "admin" || rol == "superadmin"
+	}
+}
+
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(v, 'f', 6, 64)
+}
+
+func joinList(values []string) string {
+	return strings.Join(values, "; ")
+}
+
+func formatJSON(v any) string {
+	if v == nil {
+		return ""
+	}
+	raw, err := json.Marshal(v)
+	if err != nil || string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
+
+// formatOptionalInt deja vacío lo que no se ha reportado (nil)
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+// formatCapacidad deja vacía una capacidad o cupo no declarado (0)
+func formatCapacidad(v int) string {
+	if v <= 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
@@ -0,0 +1,77 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// utf8BOM hace que Excel abra el archivo como UTF-8 (acentos y ñ)
+const utf8BOM = "\ufeff"
+
+type CSVWriter struct {
+	w    *csv.Writer
+	cols []Column
+	row  []string
+}
+
+// NewCSVWriter escribe una fila de encabezado con las columnas visibles para el rol
+func NewCSVWriter(w io.Writer, rol string) (Writer, error) {
+	cols := ColumnsFor(rol)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name
+	}
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return nil, err
+	}
+	return newCSVWriter(w, cols, header)
+}
+
+func newCSVWriter(w io.Writer, cols []Column, headers ...[]string) (*CSVWriter, error) {
+	cw := &CSVWriter{w: csv.NewWriter(w), cols: cols, row: make([]string, len(cols))}
+	for _, h := range headers {
+		if err := cw.w.Write(h); err != nil {
+			return nil, err
+		}
+	}
+	return cw, nil
+}
+
+func (cw *CSVWriter) Write(p *models.Punto) error {
+	for i, c := range cw.cols {
+		cw.row[i] = c.Value(p)
+	}
+	return writeRow(cw.w, cw.row)
+}
+
+// formulaPrefixes son los caracteres con que Excel y LibreOffice interpretan una celda como fórmula
+const formulaPrefixes = "=+-@\t\r"
+
+// safeCell antepone ' a un texto que la planilla ejecutaría como fórmula. Los
+// números (latitudes negativas) quedan como están.
+func safeCell(v string) string {
+	if v == "" || !strings.ContainsRune(formulaPrefixes, rune(v[0])) {
+		return v
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	return "'" + v
+}
+
+// writeRow escribe una fila de datos (no encabezados) con cada celda pasada por safeCell
+func writeRow(w *csv.Writer, row []string) error {
+	for i := range row {
+		row[i] = safeCell(row[i])
+	}
+	return w.Write(row)
+}
+
+func (cw *CSVWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+func TestSafeCell(t *testing.T) {
+	tests := map[string]string{
+		`=HYPERLINK("http://x","click")`: `'=HYPERLINK("http://x","click")`,
+		"+56 9 1111 1111":                "'+56 9 1111 1111",
+		"-2+3+cmd|' /C calc'!A0":         "'-2+3+cmd|' /C calc'!A0",
+		"@SUM(A1:A2)":                    "'@SUM(A1:A2)",
+		"\t=1+1":                         "'\t=1+1",
+		"\r=1+1":                         "'\r=1+1",
+		"-36.8201":                       "-36.8201",
+		"+5":                             "+5",
+		"Albergue = Escuela":             "Albergue = Escuela",
+		"":                               "",
+	}
+	for in, want := range tests {
+		if got := safeCell(in); got != want {
+			t.Errorf("safeCell(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// Los textos de los envíos públicos no llegan como fórmula a la planilla del verificador
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCSVWriter(&buf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &models.Punto{ID: "pnt_1", Nombre: "=cmd|' /C calc'!A0", Latitud: -36.82, Longitud: -73.05, Categoria: "sos"}
+	if err := w.Write(p); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), utf8BOM))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range rows[0] {
+		switch name {
+		case "nombre":
+			if rows[1][i] != "'=cmd|' /C calc'!A0" {
+				t.Errorf("nombre = %q", rows[1][i])
+			}
+		case "latitud":
+			if rows[1][i] != "-36.820000" {
+				t.Errorf("latitud = %q", rows[1][i])
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package export escribe listados de puntos en formatos que leen las
+// herramientas de terreno: KML (Google Earth), GPX (GPS de mano), CSV
+// (planillas) y CSV con hashtags HXL (coordinación humanitaria). Los escritores
+// trabajan punto a punto, así que la respuesta se transmite a medida que se
+// leen las filas de la base de datos.
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// Writer recibe los puntos de a uno. Close escribe el cierre del documento.
+type Writer interface {
+	Write(p *models.Punto) error
+	Close() error
+}
+
+// Format describe un formato de exportación
+type Format struct {
+	Extension   string
+	ContentType string
+	New         func(w io.Writer, rol string) (Writer, error)
+}
+
+var Formats = map[string]Format{
+	"csv": {"csv", "text/csv; charset=utf-8", NewCSVWriter},
+	"kml": {"kml", "application/vnd.google-earth.kml+xml", NewKMLWriter},
+	"gpx": {"gpx", "application/gpx+xml", NewGPXWriter},
+	"hxl": {"hxl.csv", "text/csv; charset=utf-8", NewHXLWriter},
+}
+
+func Lookup(name string) (Format, error) {
+	f, ok := Formats[name]
+	if !ok {
+		return Format{}, fmt.Errorf("unknown export format %q", name)
+	}
+	return f, nil
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// gpxWaypoint sigue GPX 1.1; los GPS de mano solo leen nombre, descripción y tipo
+type gpxWaypoint struct {
+	XMLName xml.Name `xml:"wpt"`
+	Lat     string   `xml:"lat,attr"`
+	Lon     string   `xml:"lon,attr"`
+	Name    string   `xml:"name"`
+	Cmt     string   `xml:"cmt,omitempty"`
+	Desc    string   `xml:"desc,omitempty"`
+	Type    string   `xml:"type,omitempty"`
+}
+
+type GPXWriter struct {
+	enc   *xml.Encoder
+	notas bool
+}
+
+// NewGPXWriter escribe un waypoint por punto. Las notas internas van en <cmt>
+// solo si el rol puede verlas.
+func NewGPXWriter(w io.Writer, rol string) (Writer, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+
+	gw := &GPXWriter{enc: xml.NewEncoder(w), notas: canSee(rol, visibleVerificador)}
+	gw.enc.Indent("", "  ")
+
+	start := xml.StartElement{
+		Name: xml.Name{Local: "gpx"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "version"}, Value: "1.1"},
+			{Name: xml.Name{Local: "creator"}, Value: "Donde Ayudo CL"},
+			{Name: xml.Name{Local: "xmlns"}, Value: "http://www.topografix.com/GPX/1/1"},
+		},
+	}
+	if err := gw.enc.EncodeToken(start); err != nil {
+		return nil, err
+	}
+	return gw, nil
+}
+
+func (gw *GPXWriter) Write(p *models.Punto) error {
+	wpt := gpxWaypoint{
+		Lat:  formatCoord(p.Latitud),
+		Lon:  formatCoord(p.Longitud),
+		Name: p.Nombre,
+		Desc: describe(p),
+		Type: p.Categoria,
+	}
+	if gw.notas {
+		wpt.Cmt = p.NotasInternas
+	}
+	return gw.enc.Encode(wpt)
+}
+
+func (gw *GPXWriter) Close() error {
+	if err := gw.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "gpx"}}); err != nil {
+		return err
+	}
+	return gw.enc.Flush()
+}
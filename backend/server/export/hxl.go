@@ -0,0 +1,99 @@
+package export
+
+import (
+	"io"
+)
+
+// DictionaryEntry describe una columna de la exportación HXL. El diccionario es
+// estable: una columna nueva se agrega al final y un hashtag no cambia de significado.
+type DictionaryEntry struct {
+	Column      string `json:"column"`
+	HXL         string `json:"hxl"`
+	Type        string `json:"type"` // text, number, boolean, date, coordinate, list, json
+	Visibility  string `json:"visibility"`
+	Description string `json:"description"`
+}
+
+// hxlTags asigna el hashtag HXL (https://hxlstandard.org) de cada columna de Columns
+var hxlTags = map[string]struct {
+	tag, typ, description string
+}{
+	"id":                    {"#meta+id", "text", "Identificador estable del punto"},
+	"nombre":                {"#loc+name", "text", "Nombre del punto"},
+	"latitud":               {"#geo+lat", "coordinate", "Latitud WGS84"},
+	"longitud":              {"#geo+lon", "coordinate", "Longitud WGS84"},
+	"direccion":             {"#loc+address", "text", "Dirección"},
+	"ciudad":                {"#adm2+name", "text", "Ciudad o comuna"},
+	"nombre_zona":           {"#loc+zone+name", "text", "Sector, villa o zona (SOS)"},
+	"categoria":             {"#loc+type", "text", "acopio, albergue, hidratacion o sos"},
+	"subtipo":               {"#loc+type+sub", "text", "Subtipo dentro de la categoría"},
+	"categorias_ayuda":      {"#sector+list", "list", "Tipos de ayuda, separados por '; '"},
+	"nivel_urgencia":        {"#severity", "text", "critico, alto, medio o bajo"},
+	"estado":                {"#status", "text", "Estado de publicación del punto"},
+	"contacto_nombre":       {"#contact+name", "text", "Persona de contacto"},
+	"contacto_principal":    {"#contact+phone", "text", "Teléfono o correo de contacto"},
+	"horario":               {"#access+hours", "text", "Horario de atención"},
+	"entidad_verificadora":  {"#org+verifier", "text", "Organización que verificó el punto"},
+	"fecha_verificacion":    {"#date+verified", "date", "Fecha de verificación"},
+	"capacidad_estado":      {"#capacity+status", "text", "Estado de capacidad declarado"},
+	"necesidades_raw":       {"#description+needs", "text", "Necesidades en texto libre"},
+	"necesidades_tags":      {"#item+needs+json", "json", "Necesidades estructuradas (JSON)"},
+	"habitado_actualmente":  {"#indicator+inhabited+bool", "boolean", "Si la zona está habitada (SOS)"},
+	"cantidad_ninos":        {"#affected+children", "number", "Niños y niñas afectados"},
+	"cantidad_adolescentes": {"#affected+adolescents", "number", "Adolescentes afectados"},
+	"cantidad_adultos":      {"#affected+adults", "number", "Adultos afectados"},
+	"cantidad_ancianos":     {"#affected+elderly", "number", "Personas mayores afectadas"},
+	"animales_detalle":      {"#description+animals", "text", "Animales en la zona"},
+	"riesgo_asbesto":        {"#severity+asbestos", "text", "Riesgo de asbesto"},
+	"logistica_llegada":     {"#access+description", "text", "Cómo llegar"},
+	"tipos_acceso":          {"#access+type+list", "list", "Tipos de acceso, separados por '; '"},
+	"requiere_voluntarios":  {"#indicator+volunteers_needed+bool", "boolean", "Si se necesitan voluntarios"},
+	"tiene_banos":           {"#indicator+toilets+bool", "boolean", "Si hay baños"},
+	"tiene_electricidad":    {"#indicator+electricity+bool", "boolean", "Si hay electricidad"},
+	"tiene_senal":           {"#indicator+signal+bool", "boolean", "Si hay señal telefónica"},
+	"created":               {"#date+created", "date", "Fecha de creación"},
+	"updated":               {"#date+updated", "date", "Fecha de última actualización"},
+	"notas_internas":        {"#description+internal", "text", "Notas internas (verificadores)"},
+	"created_by":            {"#meta+created_by", "text", "Usuario que creó el punto"},
+	"fallecidos_reportados": {"#indicator+deaths_reported+bool", "boolean", "Si se reportaron fallecidos (solo admin)"},
+	"horario_osm":           {"#access+hours+osm", "text", "Horario en formato opening_hours de OpenStreetMap"},
+	"capacidad_total":       {"#capacity+beds+total", "number", "Camas declaradas del albergue"},
+	"ocupacion_actual":      {"#capacity+beds+occupied", "number", "Personas alojadas según el último reporte"},
+	"camas_disponibles":     {"#capacity+beds+available", "number", "Camas disponibles (capacidad menos ocupación)"},
+	"ocupacion_actualizada": {"#date+occupancy", "date", "Fecha del último reporte de ocupación"},
+	"cupo_voluntarios":      {"#capacity+volunteers+needed", "number", "Voluntarios que pide el punto"},
+	"voluntarios_asignados": {"#capacity+volunteers+assigned", "number", "Voluntarios asignados al punto"},
+}
+
+// Dictionary devuelve el diccionario de datos HXL en el orden de las columnas
+func Dictionary() []DictionaryEntry {
+	entries := make([]DictionaryEntry, 0, len(Columns))
+	for _, c := range Columns {
+		tag := hxlTags[c.Name]
+		visibility := c.Visible
+		if visibility == visiblePublic {
+			visibility = "public"
+		}
+		entries = append(entries, DictionaryEntry{
+			Column:      c.Name,
+			HXL:         tag.tag,
+			Type:        tag.typ,
+			Visibility:  visibility,
+			Description: tag.description,
+		})
+	}
+	return entries
+}
+
+// NewHXLWriter escribe un CSV con dos filas de encabezado: los nombres de columna
+// y, debajo, sus hashtags HXL. Va sin BOM, como esperan las herramientas HXL.
+func NewHXLWriter(w io.Writer, rol string) (Writer, error) {
+	cols := ColumnsFor(rol)
+	header := make([]string, len(cols))
+	tags := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name
+		tags[i] = hxlTags[c.Name].tag
+	}
+	return newCSVWriter(w, cols, header, tags)
+}
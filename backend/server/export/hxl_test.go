@@ -0,0 +1,48 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+// Toda columna exportable necesita hashtag HXL, tipo y descripción en el diccionario
+func TestDictionaryCoversColumns(t *testing.T) {
+	seen := map[string]string{}
+	for _, e := range Dictionary() {
+		if !strings.HasPrefix(e.HXL, "#") || e.Type == "" || e.Description == "" {
+			t.Errorf("columna %q sin entrada completa en hxlTags", e.Column)
+		}
+		if other, ok := seen[e.HXL]; ok {
+			t.Errorf("hashtag %q repetido en %q y %q", e.HXL, other, e.Column)
+		}
+		seen[e.HXL] = e.Column
+	}
+	if len(hxlTags) != len(Columns) {
+		t.Errorf("hxlTags tiene %d entradas y Columns %d", len(hxlTags), len(Columns))
+	}
+}
+
+// exportedColumns es el orden publicado de las columnas: los consumidores leen
+// por posición, así que solo se agregan columnas al final
+var exportedColumns = []string{
+	"id", "nombre", "latitud", "longitud", "direccion", "ciudad", "nombre_zona",
+	"categoria", "subtipo", "categorias_ayuda", "nivel_urgencia", "estado",
+	"contacto_nombre", "contacto_principal", "horario", "entidad_verificadora",
+	"fecha_verificacion", "capacidad_estado", "necesidades_raw", "necesidades_tags",
+	"habitado_actualmente", "cantidad_ninos", "cantidad_adolescentes", "cantidad_adultos",
+	"cantidad_ancianos", "animales_detalle", "riesgo_asbesto", "logistica_llegada",
+	"tipos_acceso", "requiere_voluntarios", "tiene_banos", "tiene_electricidad",
+	"tiene_senal", "created", "updated", "notas_internas", "created_by",
+	"fallecidos_reportados", "horario_osm", "capacidad_total", "ocupacion_actual",
+	"camas_disponibles", "ocupacion_actualizada", "cupo_voluntarios", "voluntarios_asignados",
+}
+
+func TestColumnsAppendOnly(t *testing.T) {
+	names := make([]string, len(Columns))
+	for i, c := range Columns {
+		names[i] = c.Name
+	}
+	if got, want := strings.Join(names, ","), strings.Join(exportedColumns, ","); got != want {
+		t.Errorf("orden de Columns cambió:\n got %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// kmlStyles asigna un color de ícono por categoría (formato KML: aabbggrr)
+var kmlStyles = map[string]string{
+	"sos":         "ff0000ff",
+	"acopio":      "ff00a5ff",
+	"albergue":    "ff00c800",
+	"hidratacion": "ffffb400",
+}
+
+const kmlIcon = "https://maps.google.com/mapfiles/kml/paddle/wht-blank.png"
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlPlacemark struct {
+	XMLName     xml.Name  `xml:"Placemark"`
+	ID          string    `xml:"id,attr"`
+	Name        string    `xml:"name"`
+	Description string    `xml:"description,omitempty"`
+	StyleURL    string    `xml:"styleUrl,omitempty"`
+	Data        []kmlData `xml:"ExtendedData>Data"`
+	Coordinates string    `xml:"Point>coordinates"`
+}
+
+type kmlStyle struct {
+	XMLName xml.Name `xml:"Style"`
+	ID      string   `xml:"id,attr"`
+	Color   string   `xml:"IconStyle>color"`
+	Icon    string   `xml:"IconStyle>Icon>href"`
+}
+
+type KMLWriter struct {
+	enc  *xml.Encoder
+	cols []Column
+}
+
+// NewKMLWriter abre el Document con un estilo por categoría; cada punto es un
+// Placemark con las columnas visibles para el rol en ExtendedData
+func NewKMLWriter(w io.Writer, rol string) (Writer, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+
+	kw := &KMLWriter{enc: xml.NewEncoder(w), cols: ColumnsFor(rol)}
+	kw.enc.Indent("", "  ")
+
+	tokens := []xml.Token{
+		xml.StartElement{Name: xml.Name{Local: "kml"}, Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "http://www.opengis.net/kml/2.2"}}},
+		xml.StartElement{Name: xml.Name{Local: "Document"}},
+	}
+	for _, t := range tokens {
+		if err := kw.enc.EncodeToken(t); err != nil {
+			return nil, err
+		}
+	}
+	if err := kw.enc.EncodeElement("Donde Ayudo CL - Puntos", xml.StartElement{Name: xml.Name{Local: "name"}}); err != nil {
+		return nil, err
+	}
+	categorias := make([]string, 0, len(kmlStyles))
+	for categoria := range kmlStyles {
+		categorias = append(categorias, categoria)
+	}
+	sort.Strings(categorias)
+	for _, categoria := range categorias {
+		if err := kw.enc.Encode(kmlStyle{ID: categoria, Color: kmlStyles[categoria], Icon: kmlIcon}); err != nil {
+			return nil, err
+		}
+	}
+	return kw, nil
+}
+
+func (kw *KMLWriter) Write(p *models.Punto) error {
+	pm := kmlPlacemark{
+		ID:          p.ID,
+		Name:        p.Nombre,
+		Description: describe(p),
+		Coordinates: fmt.Sprintf("%s,%s,0", formatCoord(p.Longitud), formatCoord(p.Latitud)),
+	}
+	if _, ok := kmlStyles[p.Categoria]; ok {
+		pm.StyleURL = "#" + p.Categoria
+	}
+	for _, c := range kw.cols {
+		if v := c.Value(p); v != "" {
+			pm.Data = append(pm.Data, kmlData{Name: c.Name, Value: v})
+		}
+	}
+	return kw.enc.Encode(pm)
+}
+
+func (kw *KMLWriter) Close() error {
+	for _, name := range []string{"Document", "kml"} {
+		if err := kw.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return kw.enc.Flush()
+}
+
+// describe arma el texto corto que muestran el globo de Google Earth y el GPS
+func describe(p *models.Punto) string {
+	var parts []string
+	if p.Subtipo != "" {
+		parts = append(parts, p.Categoria+" / "+p.Subtipo)
+	} else {
+		parts = append(parts, p.Categoria)
+	}
+	if p.NivelUrgencia != "" {
+		parts = append(parts, "Urgencia: "+p.NivelUrgencia)
+	}
+	if p.Direccion != "" {
+		parts = append(parts, p.Direccion)
+	}
+	if p.ContactoPrincipal != "" {
+		parts = append(parts, "Contacto: "+p.ContactoPrincipal)
+	}
+	if p.Horario != "" {
+		parts = append(parts, "Horario: "+p.Horario)
+	}
+	if p.NecesidadesRaw != "" {
+		parts = append(parts, "Necesidades: "+p.NecesidadesRaw)
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// WriteStatsCSV escribe una fila por comuna con los conteos por categoría y nivel de
+// urgencia y la población afectada, lista para abrir en Excel sin pivotear
+func WriteStatsCSV(w io.Writer, stats *models.PuntoStats) error {
+	categorias := sortedKeys(stats.PorCategoria, func(a, b string) bool { return a < b })
+	niveles := sortedKeys(stats.PorNivelUrgencia, func(a, b string) bool {
+		// Del más grave al menos grave; sin_dato (rank 0) al final
+		ra, rb := models.NivelUrgenciaRank(a), models.NivelUrgenciaRank(b)
+		if ra != rb {
+			return ra > rb
+		}
+		return a < b
+	})
+
+	header := []string{"ciudad", "total"}
+	for _, c := range categorias {
+		header = append(header, "categoria_"+c)
+	}
+	for _, n := range niveles {
+		header = append(header, "urgencia_"+n)
+	}
+	header = append(header, "ninos", "adolescentes", "adultos", "ancianos", "afectados_total")
+
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, c := range stats.PorCiudad {
+		row := []string{c.Ciudad, strconv.Itoa(c.Total)}
+		for _, cat := range categorias {
+			row = append(row, strconv.Itoa(c.PorCategoria[cat]))
+		}
+		for _, n := range niveles {
+			row = append(row, strconv.Itoa(c.PorNivelUrgencia[n]))
+		}
+		a := c.Afectados
+		row = append(row,
+			strconv.Itoa(a.Ninos), strconv.Itoa(a.Adolescentes),
+			strconv.Itoa(a.Adultos), strconv.Itoa(a.Ancianos), strconv.Itoa(a.Total))
+		if err := writeRow(cw, row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func sortedKeys(m map[string]int, less func(a, b string) bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+	return keys
+}
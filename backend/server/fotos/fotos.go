@@ -0,0 +1,152 @@
+// Package fotos procesa las fotos que se suben desde terreno: reconoce el formato por
+// su contenido, endereza según la orientación EXIF, reduce el tamaño, genera una
+// miniatura y vuelve a codificar la imagen, lo que descarta EXIF (incluida la
+// ubicación GPS del teléfono) y cualquier otro metadato.
+package fotos
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxPixels evita decodificar imágenes enormes (bombas de descompresión)
+	MaxPixels = 40_000_000
+	// MaxLado es el lado mayor de la imagen guardada
+	MaxLado = 2560
+	// MiniaturaLado es el lado mayor de la miniatura
+	MiniaturaLado = 400
+
+	jpegQuality = 85
+)
+
+var (
+	ErrFormato     = errors.New("formato no soportado, se aceptan JPEG y PNG")
+	ErrDimensiones = errors.New("la imagen es demasiado grande")
+)
+
+// Foto es una imagen ya procesada, lista para guardar
+type Foto struct {
+	ContentType string
+	Extension   string // Con punto: ".jpg"
+	Ancho       int
+	Alto        int
+	Imagen      []byte
+	Miniatura   []byte
+}
+
+// Process reconoce, endereza, reduce y vuelve a codificar data. El formato se deduce
+// del contenido, no del nombre ni del Content-Type que declara el cliente.
+func Process(data []byte) (*Foto, error) {
+	contentType := http.DetectContentType(data)
+	var encode func(*bytes.Buffer, image.Image) error
+	foto := &Foto{ContentType: contentType}
+	switch contentType {
+	case "image/jpeg":
+		foto.Extension = ".jpg"
+		encode = func(b *bytes.Buffer, img image.Image) error {
+			return jpeg.Encode(b, img, &jpeg.Options{Quality: jpegQuality})
+		}
+	case "image/png":
+		foto.Extension = ".png"
+		encode = func(b *bytes.Buffer, img image.Image) error {
+			return png.Encode(b, img)
+		}
+	default:
+		return nil, ErrFormato
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrFormato
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrDimensiones
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrFormato
+	}
+	img := image.NewRGBA(image.Rect(0, 0, cfg.Width, cfg.Height))
+	draw.Draw(img, img.Bounds(), decoded, decoded.Bounds().Min, draw.Src)
+
+	if contentType == "image/jpeg" {
+		img = orient(img, exifOrientation(data))
+	}
+	img = fit(img, MaxLado)
+	foto.Ancho, foto.Alto = img.Bounds().Dx(), img.Bounds().Dy()
+
+	var full, thumb bytes.Buffer
+	if err := encode(&full, img); err != nil {
+		return nil, err
+	}
+	if err := encode(&thumb, fit(img, MiniaturaLado)); err != nil {
+		return nil, err
+	}
+	foto.Imagen = full.Bytes()
+	foto.Miniatura = thumb.Bytes()
+	return foto, nil
+}
+
+// fit reduce img para que su lado mayor no pase de max (nunca la agranda)
+func fit(img *image.RGBA, max int) *image.RGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w <= max && h <= max {
+		return img
+	}
+	if w >= h {
+		h = maxInt(1, h*max/w)
+		w = max
+	} else {
+		w = maxInt(1, w*max/h)
+		h = max
+	}
+	return resize(img, w, h)
+}
+
+// resize reduce img a w×h promediando los píxeles de origen que cubre cada píxel
+// de destino (box filter); es suficiente para achicar fotos
+func resize(img *image.RGBA, w, h int) *image.RGBA {
+	src := img.Bounds()
+	sw, sh := src.Dx(), src.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0, y1 := y*sh/h, maxInt((y+1)*sh/h, y*sh/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := x*sw/w, maxInt((x+1)*sw/w, x*sw/w+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				i := img.PixOffset(src.Min.X+x0, src.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(img.Pix[i])
+					g += uint64(img.Pix[i+1])
+					b += uint64(img.Pix[i+2])
+					a += uint64(img.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(b / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,104 @@
+package fotos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// withExif inserta tras el SOI un APP1 con Orientation y un tag GPS de relleno
+func withExif(jpg []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(42))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(2)) // Entradas del IFD0
+	for _, e := range [][3]uint32{{0x0112, 3, uint32(orientation)}, {0x8825, 4, 0}} {
+		binary.Write(&tiff, binary.LittleEndian, uint16(e[0]))
+		binary.Write(&tiff, binary.LittleEndian, uint16(e[1]))
+		binary.Write(&tiff, binary.LittleEndian, uint32(1))
+		binary.Write(&tiff, binary.LittleEndian, e[2])
+	}
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+	tiff.WriteString("GPSLatitude -36.8201")
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	var out bytes.Buffer
+	out.Write(jpg[:2])
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+	out.Write(jpg[2:])
+	return out.Bytes()
+}
+
+func testJPEG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// Una foto de teléfono en vertical (orientación 6) queda derecha y sin EXIF ni GPS
+func TestProcessOrientsAndStripsExif(t *testing.T) {
+	data := withExif(testJPEG(t, 40, 20), 6)
+	if got := exifOrientation(data); got != 6 {
+		t.Fatalf("exifOrientation = %d, want 6", got)
+	}
+
+	foto, err := Process(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foto.ContentType != "image/jpeg" || foto.Ancho != 20 || foto.Alto != 40 {
+		t.Errorf("got %s %dx%d, want image/jpeg 20x40", foto.ContentType, foto.Ancho, foto.Alto)
+	}
+	for _, out := range [][]byte{foto.Imagen, foto.Miniatura} {
+		if bytes.Contains(out, []byte("Exif")) || bytes.Contains(out, []byte("GPS")) {
+			t.Error("quedaron metadatos EXIF en la salida")
+		}
+		if exifOrientation(out) != 1 {
+			t.Error("la salida no debe traer orientación")
+		}
+	}
+}
+
+func TestProcessResizes(t *testing.T) {
+	foto, err := Process(testJPEG(t, 3000, 1500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foto.Ancho != MaxLado || foto.Alto != MaxLado/2 {
+		t.Errorf("imagen %dx%d, want %dx%d", foto.Ancho, foto.Alto, MaxLado, MaxLado/2)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(foto.Miniatura))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != MiniaturaLado || cfg.Height != MiniaturaLado/2 {
+		t.Errorf("miniatura %dx%d, want %dx%d", cfg.Width, cfg.Height, MiniaturaLado, MiniaturaLado/2)
+	}
+}
+
+// El formato se reconoce por el contenido, no por lo que declare el cliente
+func TestProcessRejectsNonImages(t *testing.T) {
+	for _, data := range [][]byte{
+		[]byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"),
+		[]byte("%PDF-1.4"),
+		{0xFF, 0xD8, 0xFF, 0xE0, 0, 0}, // JPEG truncado
+	} {
+		if _, err := Process(data); err != ErrFormato {
+			t.Errorf("Process(%q) = %v, want ErrFormato", data, err)
+		}
+	}
+}
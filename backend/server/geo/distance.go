@@ -0,0 +1,16 @@
+package geo
+
+import "math"
+
+// earthRadiusM es el radio medio de la Tierra (el mismo que usa haversineSQL en database)
+const earthRadiusM = 6371000
+
+// DistanceM calcula la distancia en metros entre dos puntos con la fórmula de haversine
+func DistanceM(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Pow(math.Sin(dLng/2), 2)
+	return earthRadiusM * 2 * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,51 @@
+package geo
+
+import "math"
+
+// MaxLatitude es el límite de latitud de la proyección Web Mercator
+const MaxLatitude = 85.05112878
+
+// ToWorld proyecta lng/lat a coordenadas Web Mercator normalizadas en [0,1]
+// (x crece hacia el este, y crece hacia el sur, como en los tiles XYZ)
+func ToWorld(lng, lat float64) (x, y float64) {
+	lat = math.Max(-MaxLatitude, math.Min(MaxLatitude, lat))
+	x = (lng + 180) / 360
+	sin := math.Sin(lat * math.Pi / 180)
+	y = 0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)
+	return x, y
+}
+
+// FromWorld es la inversa de ToWorld
+func FromWorld(x, y float64) (lng, lat float64) {
+	lng = x*360 - 180
+	n := math.Pi - 2*math.Pi*y
+	lat = 180 / math.Pi * math.Atan(math.Sinh(n))
+	return lng, lat
+}
+
+// GridCell devuelve la celda que contiene lng/lat en una grilla de
+// 2^zoom * cellsPerTile celdas por lado
+func GridCell(lng, lat float64, zoom, cellsPerTile int) (cx, cy int) {
+	n := float64(int(1)<<zoom) * float64(cellsPerTile)
+	x, y := ToWorld(lng, lat)
+	cx = int(math.Min(math.Floor(x*n), n-1))
+	cy = int(math.Min(math.Floor(y*n), n-1))
+	return cx, cy
+}
+
+// TileBounds devuelve minLng,minLat,maxLng,maxLat del tile XYZ z/x/y
+func TileBounds(z, x, y int) (minLng, minLat, maxLng, maxLat float64) {
+	n := float64(int(1) << z)
+	minLng, maxLat = FromWorld(float64(x)/n, float64(y)/n)
+	maxLng, minLat = FromWorld(float64(x+1)/n, float64(y+1)/n)
+	return minLng, minLat, maxLng, maxLat
+}
+
+// ToTile proyecta lng/lat a coordenadas locales del tile z/x/y con la resolución extent
+func ToTile(lng, lat float64, z, x, y int, extent uint32) (px, py int) {
+	n := float64(int(1) << z)
+	wx, wy := ToWorld(lng, lat)
+	px = int(math.Round((wx*n - float64(x)) * float64(extent)))
+	py = int(math.Round((wy*n - float64(y)) * float64(extent)))
+	return px, py
+}
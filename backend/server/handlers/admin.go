@@ -13,40 +13,33 @@ import (
 )
 
 func GetAdminPuntos(w http.ResponseWriter, r *http.Request) {
-	categoria := r.URL.Query().Get("categoria")
-	subtipo := r.URL.Query().Get("subtipo")
-	ciudad := r.URL.Query().Get("ciudad")
-	estado := r.URL.Query().Get("estado")
-
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
-	}
-
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limit < 1 || limit > 100 {
-		limit = 50
+	filter, err := parsePuntoFilter(r, defaultMaxLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	filter.Estado = r.URL.Query().Get("estado")
 
-	response, err := database.GetPuntos(categoria, subtipo, ciudad, estado, page, limit)
+	response, err := database.GetPuntos(filter)
 	if err != nil {
 		http.Error(w, `{"error":"Error fetching puntos"}`, http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSONWithETag(w, r, "application/json", privateCacheControl, response.ForRole(middleware.GetUserRole(r)))
 }
 
 func CreatePunto(w http.ResponseWriter, r *http.Request) {
 	var req models.PuntoCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+	if !decodeValid(w, r, &req) {
 		return
 	}
 
-	if req.Nombre == "" || req.Latitud == 0 || req.Longitud == 0 || req.Categoria == "" {
-		http.Error(w, `{"error":"Missing required fields"}`, http.StatusBadRequest)
+	if req.Latitud == 0 || req.Longitud == 0 {
+		writeJSONError(w, "latitud and longitud are required", http.StatusBadRequest)
+		return
+	}
+	if !validHorarioOSM(w, &req.HorarioOSM) {
 		return
 	}
 
@@ -60,15 +53,14 @@ func CreatePunto(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(punto)
+	json.NewEncoder(w).Encode(punto.ForRole(middleware.GetUserRole(r)))
 }
 
 func UpdatePunto(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req models.PuntoUpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+	if !decodeValid(w, r, &req) || !validHorarioOSM(w, req.HorarioOSM) {
 		return
 	}
 
@@ -79,15 +71,14 @@ func UpdatePunto(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(punto)
+	json.NewEncoder(w).Encode(punto.ForRole(middleware.GetUserRole(r)))
 }
 
 func UpdatePuntoEstado(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req models.EstadoUpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+	if !decodeValid(w, r, &req) {
 		return
 	}
 
@@ -111,7 +102,7 @@ func UpdatePuntoEstado(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(punto)
+	json.NewEncoder(w).Encode(punto.ForRole(middleware.GetUserRole(r)))
 }
 
 func DeletePunto(w http.ResponseWriter, r *http.Request) {
@@ -160,6 +151,7 @@ func CreateUser(w http.ResponseWriter, r *http.Request) {
 		"superadmin":  true,
 		"admin":       true,
 		"verificador": true,
+		"encargado":   true,
 	}
 	if !validRoles[req.Rol] {
 		http.Error(w, `{"error":"Invalid rol value"}`, http.StatusBadRequest)
@@ -205,6 +197,7 @@ func UpdateUserRol(w http.ResponseWriter, r *http.Request) {
 		"superadmin":  true,
 		"admin":       true,
 		"verificador": true,
+		"encargado":   true,
 	}
 	if !validRoles[req.Rol] {
 		http.Error(w, `{"error":"Invalid rol value"}`, http.StatusBadRequest)
@@ -272,6 +265,14 @@ func ImportCSV(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
+		nivel, ok := models.NormalizeNivelUrgencia(punto.NivelUrgencia)
+		if !ok {
+			skipped++
+			errors = append(errors, strconv.Itoa(i)+": Invalid nivel_urgencia, expected critico, alto, medio or bajo")
+			continue
+		}
+		punto.NivelUrgencia = nivel
+
 		// Establecer estado por defecto si no viene
 		if punto.Estado == "" {
 			punto.Estado = "activo"
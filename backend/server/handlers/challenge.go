@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/pow"
+)
+
+// GetChallenge entrega un desafío de prueba de trabajo para los envíos públicos.
+// Con la prueba desactivada responde {"difficulty": 0} y el cliente no necesita resolver nada.
+func GetChallenge(issuer *pow.Issuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(issuer.Issue())
+	}
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/horario"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/matching"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultCompromisosLimit = 100
+	maxCompromisosLimit     = 500
+)
+
+// GetPuntoCompromisos muestra, por necesidad, lo comprometido frente a lo que falta
+// (GET /api/puntos/{id}/compromisos). No incluye datos de los donantes.
+func GetPuntoCompromisos(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	punto, err := database.GetPuntoByID(id)
+	if err != nil || punto.Estado != "publicado" {
+		http.Error(w, `{"error":"Punto not found"}`, http.StatusNotFound)
+		return
+	}
+
+	inventario, err := database.GetInventario(id)
+	if err != nil {
+		log.Printf("❌ Error listando inventario de %s: %v", id, err)
+		http.Error(w, `{"error":"Error fetching compromisos"}`, http.StatusInternalServerError)
+		return
+	}
+	compromisos, err := database.GetCompromisos(models.CompromisoFilter{
+		PuntoID: id, Vigente: true, Limit: maxCompromisosLimit,
+	})
+	if err != nil {
+		log.Printf("❌ Error listando compromisos de %s: %v", id, err)
+		http.Error(w, `{"error":"Error fetching compromisos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, models.PuntoCompromisosResponse{
+		PuntoID: punto.ID,
+		Nombre:  punto.Nombre,
+		Data:    matching.Pledged(matching.Needs(punto), inventario, compromisos),
+	})
+}
+
+// CreateCompromiso registra lo que un donante promete llevar a un punto publicado
+// (POST /api/puntos/{id}/compromisos). Solo se acepta para algo que el punto necesita.
+func CreateCompromiso(w http.ResponseWriter, r *http.Request) {
+	var req models.CompromisoCreateRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxSubmissionBytes)
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	if req.Cantidad <= 0 {
+		writeJSONError(w, "cantidad must be greater than 0", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Unidad) == "" || strings.TrimSpace(req.DonanteContacto) == "" {
+		writeJSONError(w, "unidad and donante_contacto are required", http.StatusBadRequest)
+		return
+	}
+	if err := validFechaEntrega(req.FechaEntrega); err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	punto, err := database.GetPuntoByID(id)
+	if err != nil || punto.Estado != "publicado" {
+		http.Error(w, `{"error":"Punto not found"}`, http.StatusNotFound)
+		return
+	}
+
+	inventario, err := database.GetInventario(id)
+	if err != nil {
+		log.Printf("❌ Error listando inventario de %s: %v", id, err)
+		http.Error(w, `{"error":"Error saving compromiso"}`, http.StatusInternalServerError)
+		return
+	}
+	necesidad, ok := matching.NeedFor(matching.Needs(punto), inventario, req.Item, req.Categoria)
+	if !ok {
+		writeJSONError(w, "item is not among the needs of this punto", http.StatusBadRequest)
+		return
+	}
+
+	compromiso, token, err := database.CreateCompromiso(id, necesidad, req)
+	if err != nil {
+		log.Printf("❌ Error guardando compromiso: %v", err)
+		http.Error(w, `{"error":"Error saving compromiso"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.CompromisoAcceptedResponse{
+		ID:      compromiso.ID,
+		Token:   token,
+		Message: "Thanks, the punto will see what you are bringing",
+	})
+}
+
+// validFechaEntrega exige YYYY-MM-DD entre hoy y MaxDiasCompromiso días más (hora de Chile)
+func validFechaEntrega(raw string) error {
+	fecha, err := time.ParseInLocation("2006-01-02", raw, horario.Santiago)
+	if err != nil {
+		return errors.New("Invalid fecha_entrega, expected YYYY-MM-DD")
+	}
+	hoy, _ := time.ParseInLocation("2006-01-02", time.Now().In(horario.Santiago).Format("2006-01-02"), horario.Santiago)
+	if fecha.Before(hoy) || fecha.After(hoy.AddDate(0, 0, models.MaxDiasCompromiso)) {
+		return fmt.Errorf("fecha_entrega must be between today and %d days from now", models.MaxDiasCompromiso)
+	}
+	return nil
+}
+
+// CancelarCompromiso anula un compromiso con el token que recibió el donante
+// (POST /api/compromisos/{id}/cancelar)
+func CancelarCompromiso(w http.ResponseWriter, r *http.Request) {
+	var req models.CompromisoCancelarRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	err := database.CancelarCompromiso(chi.URLParam(r, "id"), req.Token)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, `{"error":"Compromiso not found"}`, http.StatusNotFound)
+		return
+	case errors.Is(err, database.ErrCompromisoCerrado):
+		writeJSONError(w, "Compromiso was already delivered", http.StatusConflict)
+		return
+	case err != nil:
+		log.Printf("❌ Error cancelando compromiso: %v", err)
+		http.Error(w, `{"error":"Error cancelling compromiso"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"message":"Compromiso cancelled"}`))
+}
+
+// GetAdminCompromisos lista los compromisos de un punto con los datos del donante
+func GetAdminCompromisos(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := models.CompromisoFilter{Estado: q.Get("estado")}
+
+	var err error
+	if f.Limit, err = parseLimit(r, defaultCompromisosLimit, maxCompromisosLimit); err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch f.Estado {
+	case "", models.CompromisoComprometido, models.CompromisoEntregado, models.CompromisoCancelado:
+	default:
+		writeJSONError(w, "Invalid estado, expected comprometido, entregado or cancelado", http.StatusBadRequest)
+		return
+	}
+	if raw := q.Get("vigentes"); raw != "" {
+		if f.Vigente, err = strconv.ParseBool(raw); err != nil {
+			writeJSONError(w, "Invalid vigentes, expected true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	f.PuntoID = punto.ID
+	compromisos, err := database.GetCompromisos(f)
+	if err != nil {
+		log.Printf("❌ Error listando compromisos de %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error fetching compromisos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl,
+		models.CompromisosResponse{Data: compromisos, Limit: f.Limit})
+}
+
+// UpdateCompromiso marca un compromiso como entregado (suma al inventario si el punto
+// lleva ese ítem) o cancelado
+func UpdateCompromiso(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	var req models.CompromisoUpdateRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	compromiso, err := database.CerrarCompromiso(punto.ID, chi.URLParam(r, "compromisoId"), req, middleware.GetUserID(r))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, `{"error":"Compromiso not found"}`, http.StatusNotFound)
+		return
+	case errors.Is(err, database.ErrCompromisoCerrado):
+		writeJSONError(w, "Compromiso is already closed", http.StatusConflict)
+		return
+	case err != nil:
+		log.Printf("❌ Error actualizando compromiso: %v", err)
+		http.Error(w, `{"error":"Error updating compromiso"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(compromiso)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// writeJSONWithETag serializa v con un ETag del contenido y responde 304 si el
+// cliente ya tiene esa versión (If-None-Match). cacheControl define quién puede
+// guardar la respuesta; siempre se revalida contra el servidor.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, contentType, cacheControl string, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, `{"error":"Error encoding response"}`, http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Add("Vary", "Accept")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+	w.Write([]byte("\n"))
+}
+
+// etagMatches compara If-None-Match (lista separada por comas o "*") con el ETag actual
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	publicCacheControl  = "public, no-cache"
+	privateCacheControl = "private, no-cache"
+)
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/export"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// ExportPuntos transmite los puntos filtrados en el formato pedido (csv, kml, gpx o hxl).
+// Acepta los mismos filtros que GetAdminPuntos, sin paginación; las columnas
+// privadas salen según el rol, igual que en la API.
+func ExportPuntos(format string) http.HandlerFunc {
+	f, err := export.Lookup(format)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parsePuntoFilter(r, defaultMaxLimit)
+		if err != nil {
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.Estado = r.URL.Query().Get("estado")
+
+		w.Header().Set("Cache-Control", "no-store")
+		streamExport(w, f, filter, middleware.GetUserRole(r))
+	}
+}
+
+// hxlCacheControl deja que la CDN sirva la exportación pública unos minutos
+// en vez de recorrer todos los puntos en cada descarga
+const hxlCacheControl = "public, max-age=300"
+
+// GetPuntosHXL exporta los puntos publicados como CSV con hashtags HXL (vista pública).
+// Acepta los mismos filtros que GetPuntos, sin paginación.
+func GetPuntosHXL(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePuntoFilter(r, defaultMaxLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Estado = "publicado"
+
+	f, _ := export.Lookup("hxl")
+	w.Header().Set("Cache-Control", hxlCacheControl)
+	streamExport(w, f, filter, "")
+}
+
+// GetHXLDictionary devuelve el diccionario de datos de la exportación HXL
+func GetHXLDictionary(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", publicCacheControl)
+	json.NewEncoder(w).Encode(map[string]any{
+		"standard": "https://hxlstandard.org/standard/1-1final/",
+		"columns":  export.Dictionary(),
+	})
+}
+
+func streamExport(w http.ResponseWriter, f export.Format, filter models.PuntoFilter, rol string) {
+	filename := fmt.Sprintf("puntos-%s.%s", time.Now().Format("20060102-1504"), f.Extension)
+	w.Header().Set("Content-Type", f.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	writer, err := f.New(w, rol)
+	if err != nil {
+		log.Printf("❌ Error iniciando exportación %s: %v", f.Extension, err)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	count := 0
+	err = database.EachPunto(filter, func(p *models.Punto) error {
+		count++
+		if flusher != nil && count%500 == 0 {
+			flusher.Flush()
+		}
+		return writer.Write(p)
+	})
+	if err != nil {
+		// Los encabezados ya se enviaron: solo queda cortar la respuesta
+		log.Printf("❌ Error exportando puntos (%s): %v", f.Extension, err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("❌ Error cerrando exportación %s: %v", f.Extension, err)
+	}
+}
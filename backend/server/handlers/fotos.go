@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/fotos"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/storage"
+)
+
+// UploadPuntoFoto recibe una foto (multipart, campo "foto") y la guarda en store
+// sin metadatos EXIF, con su miniatura. Una foto tipo "evidencia" se agrega a
+// evidencia_fotos y una tipo "asbesto" reemplaza foto_asbesto
+// (POST /api/admin/puntos/{id}/fotos).
+func UploadPuntoFoto(store storage.Storage, maxBytes int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		punto := loadManagedPunto(w, r)
+		if punto == nil {
+			return
+		}
+
+		data, ok := readUpload(w, r, "foto", maxBytes)
+		if !ok {
+			return
+		}
+
+		tipo := r.FormValue("tipo")
+		if tipo == "" {
+			tipo = models.FotoEvidencia
+		}
+		if tipo != models.FotoEvidencia && tipo != models.FotoAsbesto {
+			writeJSONError(w, "Invalid tipo, expected evidencia or asbesto", http.StatusBadRequest)
+			return
+		}
+		if tipo == models.FotoEvidencia && len(punto.EvidenciaFotos) >= models.MaxEvidenciaFotos {
+			writeJSONError(w, fmt.Sprintf("Punto already has %d photos", models.MaxEvidenciaFotos), http.StatusConflict)
+			return
+		}
+
+		foto, err := fotos.Process(data)
+		switch {
+		case errors.Is(err, fotos.ErrFormato):
+			writeJSONError(w, "Unsupported image, expected JPEG or PNG", http.StatusUnsupportedMediaType)
+			return
+		case errors.Is(err, fotos.ErrDimensiones):
+			writeJSONError(w, "Image dimensions too large", http.StatusBadRequest)
+			return
+		case err != nil:
+			log.Printf("❌ Error procesando foto: %v", err)
+			http.Error(w, `{"error":"Error processing foto"}`, http.StatusInternalServerError)
+			return
+		}
+
+		name := uploadName(punto.ID)
+		key, thumbKey := name+foto.Extension, name+"_thumb"+foto.Extension
+		url, err := store.Put(r.Context(), key, foto.ContentType, foto.Imagen)
+		if err != nil {
+			log.Printf("❌ Error guardando foto %s: %v", key, err)
+			http.Error(w, `{"error":"Error storing foto"}`, http.StatusInternalServerError)
+			return
+		}
+		thumbURL, err := store.Put(r.Context(), thumbKey, foto.ContentType, foto.Miniatura)
+		if err != nil {
+			log.Printf("❌ Error guardando miniatura %s: %v", thumbKey, err)
+			deleteUploads(store, key)
+			http.Error(w, `{"error":"Error storing foto"}`, http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := database.AddPuntoFoto(punto.ID, tipo, url)
+		if err != nil {
+			deleteUploads(store, key, thumbKey)
+			if errors.Is(err, database.ErrMaxFotos) {
+				writeJSONError(w, fmt.Sprintf("Punto already has %d photos", models.MaxEvidenciaFotos), http.StatusConflict)
+				return
+			}
+			log.Printf("❌ Error guardando foto en punto %s: %v", punto.ID, err)
+			http.Error(w, `{"error":"Error saving foto"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(models.FotoUploadResponse{
+			URL:          url,
+			ThumbnailURL: thumbURL,
+			Tipo:         tipo,
+			ContentType:  foto.ContentType,
+			Ancho:        foto.Ancho,
+			Alto:         foto.Alto,
+			Punto:        updated.ForRole(middleware.GetUserRole(r)),
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/matching"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultInventarioLimit = 100
+	maxInventarioLimit     = 500
+)
+
+// GetPuntoInventario devuelve el stock actual de un punto publicado (GET /api/puntos/{id}/inventario)
+func GetPuntoInventario(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	punto, err := database.GetPuntoByID(id)
+	if err != nil || punto.Estado != "publicado" {
+		http.Error(w, `{"error":"Punto not found"}`, http.StatusNotFound)
+		return
+	}
+
+	items, err := database.GetInventario(id)
+	if err != nil {
+		log.Printf("❌ Error listando inventario de %s: %v", id, err)
+		http.Error(w, `{"error":"Error fetching inventario"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, models.InventarioResponse{Data: items})
+}
+
+// SearchInventario busca stock entre todos los puntos publicados (GET /api/inventario),
+// p. ej. quién tiene agua en una comuna o a qué acopios les falta
+func SearchInventario(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit, err := parseLimit(r, defaultInventarioLimit, maxInventarioLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f := models.InventarioFilter{
+		Categoria: q.Get("categoria"),
+		Item:      matching.Normalize(q.Get("item")),
+		Ciudad:    q.Get("ciudad"),
+		Limit:     limit,
+	}
+	if raw := q.Get("faltantes"); raw != "" {
+		if f.SoloFaltantes, err = strconv.ParseBool(raw); err != nil {
+			writeJSONError(w, "Invalid faltantes, expected true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	items, err := database.SearchInventario(f)
+	if err != nil {
+		log.Printf("❌ Error buscando inventario: %v", err)
+		http.Error(w, `{"error":"Error fetching inventario"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, models.InventarioResponse{Data: items})
+}
+
+// GetAdminInventario devuelve el inventario de un punto en cualquier estado
+func GetAdminInventario(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	items, err := database.GetInventario(punto.ID)
+	if err != nil {
+		log.Printf("❌ Error listando inventario de %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error fetching inventario"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl, models.InventarioResponse{Data: items})
+}
+
+// CreateInventarioItem agrega un ítem al inventario de un punto. Si ya existe uno con
+// el mismo nombre y unidad responde 409: el stock se mueve con /movimientos.
+func CreateInventarioItem(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	var req models.InventarioItemCreateRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	if matching.Normalize(req.Nombre) == "" {
+		writeJSONError(w, "nombre is required", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := database.FindInventarioItem(punto.ID, req.Nombre, req.Unidad)
+	if err != nil {
+		log.Printf("❌ Error buscando ítem de inventario: %v", err)
+		http.Error(w, `{"error":"Error creating inventario item"}`, http.StatusInternalServerError)
+		return
+	}
+	if existing != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.DuplicateInventarioResponse{
+			Error:  "Item already exists in this punto, register a movimiento instead",
+			ItemID: existing,
+		})
+		return
+	}
+
+	item, err := database.CreateInventarioItem(punto.ID, req, middleware.GetUserID(r))
+	if err != nil {
+		log.Printf("❌ Error creando ítem de inventario: %v", err)
+		http.Error(w, `{"error":"Error creating inventario item"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+// UpdateInventarioItem cambia nombre, categoría, unidad u objetivo de un ítem (no el stock)
+func UpdateInventarioItem(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	var req models.InventarioItemUpdateRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	item, err := database.UpdateInventarioItem(punto.ID, chi.URLParam(r, "itemId"), req)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Inventario item not found"}`, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, database.ErrInventarioDuplicado) {
+		writeJSONError(w, "Another item with that nombre and unidad already exists in this punto", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error actualizando ítem de inventario: %v", err)
+		http.Error(w, `{"error":"Error updating inventario item"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// DeleteInventarioItem quita un ítem del inventario con su historial
+func DeleteInventarioItem(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	err := database.DeleteInventarioItem(punto.ID, chi.URLParam(r, "itemId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Inventario item not found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error borrando ítem de inventario: %v", err)
+		http.Error(w, `{"error":"Error deleting inventario item"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"message":"Inventario item deleted successfully"}`))
+}
+
+// CreateMovimiento registra una entrada, salida o ajuste de stock y devuelve el ítem actualizado
+func CreateMovimiento(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	var req models.MovimientoRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	if req.Tipo != models.MovimientoAjuste && req.Cantidad <= 0 {
+		writeJSONError(w, "cantidad must be greater than 0", http.StatusBadRequest)
+		return
+	}
+
+	item, err := database.RegisterMovimiento(punto.ID, chi.URLParam(r, "itemId"), req, middleware.GetUserID(r))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, `{"error":"Inventario item not found"}`, http.StatusNotFound)
+		return
+	case errors.Is(err, database.ErrStockInsuficiente):
+		writeJSONError(w, "Not enough stock to dispatch that cantidad", http.StatusConflict)
+		return
+	case err != nil:
+		log.Printf("❌ Error registrando movimiento: %v", err)
+		http.Error(w, `{"error":"Error saving movimiento"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+// GetMovimientos devuelve el historial de stock de un ítem, del más reciente al más antiguo
+func GetMovimientos(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r, defaultHistorialLimit, maxHistorialLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	movimientos, err := database.GetMovimientos(punto.ID, chi.URLParam(r, "itemId"), limit)
+	if err != nil {
+		log.Printf("❌ Error listando movimientos: %v", err)
+		http.Error(w, `{"error":"Error fetching movimientos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl,
+		models.MovimientosResponse{Data: movimientos, Limit: limit})
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/matching"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// defaultMatchDistanceM es hasta dónde se buscan acopios si no se indica max_distance_m
+	defaultMatchDistanceM = 20000
+	defaultMatchesPerNeed = 3
+	maxMatchesPerNeed     = 10
+)
+
+// parseMatchParams lee max_distance_m y per_need
+func parseMatchParams(r *http.Request) (float64, int, error) {
+	q := r.URL.Query()
+
+	maxDistance := float64(defaultMatchDistanceM)
+	if raw := q.Get("max_distance_m"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v <= 0 || v > maxRadiusM {
+			return 0, 0, errors.New("Invalid max_distance_m")
+		}
+		maxDistance = v
+	}
+
+	perNeed := defaultMatchesPerNeed
+	if raw := q.Get("per_need"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 || v > maxMatchesPerNeed {
+			return 0, 0, errors.New("Invalid per_need, expected 1 to 10")
+		}
+		perNeed = v
+	}
+
+	return maxDistance, perNeed, nil
+}
+
+// loadAcopios trae los acopios publicados del filtro que tienen algo en stock
+func loadAcopios(f models.PuntoFilter) ([]matching.Acopio, error) {
+	f.Categoria = "acopio"
+	f.Estado = "publicado"
+
+	stock, err := database.GetStockAcopios()
+	if err != nil {
+		return nil, err
+	}
+
+	var acopios []matching.Acopio
+	err = database.EachPunto(f, func(p *models.Punto) error {
+		if items := stock[p.ID]; len(items) > 0 {
+			acopios = append(acopios, matching.NewAcopio(p, items))
+		}
+		return nil
+	})
+	return acopios, err
+}
+
+// GetPuntoMatches sugiere, para cada necesidad de un punto publicado, los acopios
+// más cercanos que la tienen en su inventario
+func GetPuntoMatches(w http.ResponseWriter, r *http.Request) {
+	maxDistance, perNeed, err := parseMatchParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	punto, err := database.GetPuntoByID(chi.URLParam(r, "id"))
+	if err != nil || punto.Estado != "publicado" {
+		http.Error(w, `{"error":"Punto not found"}`, http.StatusNotFound)
+		return
+	}
+	if punto.Categoria == "acopio" {
+		http.Error(w, `{"error":"Acopios offer supplies, matches are computed for the puntos that need them"}`, http.StatusBadRequest)
+		return
+	}
+
+	acopios, err := loadAcopios(models.PuntoFilter{
+		Near:    &models.GeoPoint{Lat: punto.Latitud, Lng: punto.Longitud},
+		RadiusM: maxDistance,
+	})
+	if err != nil {
+		log.Printf("❌ Error cargando acopios: %v", err)
+		http.Error(w, `{"error":"Error computing matches"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, matching.Match(punto, acopios, maxDistance, perNeed))
+}
+
+// GetMatches cruza todas las zonas publicadas del filtro (categoria=sos por defecto)
+// con los acopios. Con ?unmet=true solo vienen las que tienen necesidades sin cubrir.
+func GetMatches(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePuntoFilter(r, defaultMaxLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	maxDistance, perNeed, err := parseMatchParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	unmet, _ := strconv.ParseBool(r.URL.Query().Get("unmet"))
+
+	filter.Estado = "publicado"
+	if filter.Categoria == "" {
+		filter.Categoria = "sos"
+	}
+	if filter.Categoria == "acopio" {
+		writeJSONError(w, "categoria=acopio is the supply side, choose the puntos that need supplies", http.StatusBadRequest)
+		return
+	}
+
+	acopios, err := loadAcopios(models.PuntoFilter{})
+	if err != nil {
+		log.Printf("❌ Error cargando acopios: %v", err)
+		http.Error(w, `{"error":"Error computing matches"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := models.MatchesResponse{MaxDistanceM: maxDistance, Data: []models.PuntoMatches{}}
+	err = database.EachPunto(filter, func(p *models.Punto) error {
+		m := matching.Match(p, acopios, maxDistance, perNeed)
+		if m.Total == 0 || (unmet && m.Cubiertas == m.Total) {
+			return nil
+		}
+		response.Data = append(response.Data, m)
+		return nil
+	})
+	if err != nil {
+		log.Printf("❌ Error calculando matches: %v", err)
+		http.Error(w, `{"error":"Error computing matches"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, response)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultHistorialLimit = 50
+	maxHistorialLimit     = 500
+)
+
+// loadManagedPunto busca el punto de la URL y revisa que el usuario pueda gestionarlo:
+// verificadores y admins cualquier punto, un encargado solo los que tiene asignados.
+// Si no puede, responde el error y devuelve nil.
+func loadManagedPunto(w http.ResponseWriter, r *http.Request) *models.Punto {
+	id := chi.URLParam(r, "id")
+
+	punto, err := database.GetPuntoByID(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && punto.Estado == "oculto") {
+		http.Error(w, `{"error":"Punto not found"}`, http.StatusNotFound)
+		return nil
+	}
+	if err != nil {
+		log.Printf("❌ Error buscando punto %s: %v", id, err)
+		http.Error(w, `{"error":"Error fetching punto"}`, http.StatusInternalServerError)
+		return nil
+	}
+
+	if middleware.GetUserRole(r) == "encargado" {
+		ok, err := database.IsEncargado(id, middleware.GetUserID(r))
+		if err != nil {
+			log.Printf("❌ Error revisando encargado de %s: %v", id, err)
+			http.Error(w, `{"error":"Error fetching punto"}`, http.StatusInternalServerError)
+			return nil
+		}
+		if !ok {
+			http.Error(w, `{"error":"You are not in charge of this punto"}`, http.StatusForbidden)
+			return nil
+		}
+	}
+
+	return punto
+}
+
+// parseLimit lee ?limit= entre 1 y max; sin el parámetro devuelve def
+func parseLimit(r *http.Request, def, max int) (int, error) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 1 || v > max {
+		return 0, fmt.Errorf("Invalid limit, expected 1 to %d", max)
+	}
+	return v, nil
+}
+
+// ReportOcupacion registra la ocupación actual de un albergue (POST /api/admin/puntos/{id}/ocupacion)
+func ReportOcupacion(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+	if punto.Categoria != "albergue" {
+		writeJSONError(w, "Occupancy can only be reported for albergues", http.StatusBadRequest)
+		return
+	}
+
+	var req models.OcupacionRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	capacidad := punto.CapacidadTotal
+	if req.CapacidadTotal != nil {
+		capacidad = *req.CapacidadTotal
+	}
+	if capacidad <= 0 {
+		writeJSONError(w, "capacidad_total must be declared before reporting occupancy", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := database.ReportOcupacion(punto.ID, req, middleware.GetUserID(r))
+	if err != nil {
+		log.Printf("❌ Error reportando ocupación de %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error saving occupancy"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated.ForRole(middleware.GetUserRole(r)))
+}
+
+// GetOcupacionHistorial devuelve los reportes de ocupación de un albergue, del más reciente al más antiguo
+func GetOcupacionHistorial(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r, defaultHistorialLimit, maxHistorialLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	registros, err := database.GetOcupacionHistorial(punto.ID, limit)
+	if err != nil {
+		log.Printf("❌ Error listando ocupación de %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error fetching occupancy history"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl,
+		models.OcupacionHistorialResponse{Data: registros, Limit: limit})
+}
+
+// GetMisPuntos lista los puntos a cargo del usuario autenticado (GET /api/admin/mis-puntos)
+func GetMisPuntos(w http.ResponseWriter, r *http.Request) {
+	puntos, err := database.GetPuntosByEncargado(middleware.GetUserID(r))
+	if err != nil {
+		log.Printf("❌ Error listando puntos del encargado: %v", err)
+		http.Error(w, `{"error":"Error fetching puntos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := &models.PuntosListResponse{Data: puntos, Limit: len(puntos)}
+	writeJSONWithETag(w, r, "application/json", privateCacheControl, response.ForRole(middleware.GetUserRole(r)))
+}
+
+// GetPuntoEncargados lista los usuarios a cargo de un punto
+func GetPuntoEncargados(w http.ResponseWriter, r *http.Request) {
+	users, err := database.GetEncargados(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Printf("❌ Error listando encargados: %v", err)
+		http.Error(w, `{"error":"Error fetching encargados"}`, http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]models.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = user.ToResponse()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responses)
+}
+
+// AssignEncargado deja a un usuario a cargo de un punto (PUT /api/admin/puntos/{id}/encargados/{userId})
+func AssignEncargado(w http.ResponseWriter, r *http.Request) {
+	puntoID := chi.URLParam(r, "id")
+	userID := chi.URLParam(r, "userId")
+
+	if _, err := database.GetPuntoByID(puntoID); err != nil {
+		http.Error(w, `{"error":"Punto not found"}`, http.StatusNotFound)
+		return
+	}
+
+	err := database.AssignEncargado(puntoID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"User not found"}`, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, database.ErrNoEsEncargado) {
+		writeJSONError(w, "User must have rol encargado to be assigned to a punto", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error asignando encargado: %v", err)
+		http.Error(w, `{"error":"Error assigning encargado"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"message":"Encargado assigned successfully"}`))
+}
+
+// RemoveEncargado quita a un usuario de los encargados de un punto
+func RemoveEncargado(w http.ResponseWriter, r *http.Request) {
+	if err := database.RemoveEncargado(chi.URLParam(r, "id"), chi.URLParam(r, "userId")); err != nil {
+		log.Printf("❌ Error quitando encargado: %v", err)
+		http.Error(w, `{"error":"Error removing encargado"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"message":"Encargado removed successfully"}`))
+}
@@ -0,0 +1,527 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/export"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/openapi"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/pow"
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	rolesEncargado   = []string{"encargado", "verificador", "admin", "superadmin"}
+	rolesVerificador = []string{"verificador", "admin", "superadmin"}
+	rolesAdmin       = []string{"admin", "superadmin"}
+	rolesSuperadmin  = []string{"superadmin"}
+)
+
+// puntoFilterParams son los filtros comunes de los listados de puntos (parsePuntoFilter)
+var puntoFilterParams = []openapi.Parameter{
+	openapi.QueryParam("categoria", "string", "acopio, albergue, hidratacion o sos"),
+	openapi.QueryParam("subtipo", "string", ""),
+	openapi.QueryParam("ciudad", "string", ""),
+	openapi.QueryParam("q", "string", "Búsqueda de texto (sin acentos, con plurales)"),
+	openapi.QueryParam("bbox", "string", "minLng,minLat,maxLng,maxLat"),
+	openapi.QueryParam("near", "string", "lat,lng"),
+	openapi.QueryParam("radius_m", "number", "Radio en metros desde near"),
+	openapi.QueryParam("sort", "string", "created, distance (requiere near) o relevance (requiere q)"),
+	openapi.QueryParam("categorias_ayuda", "string", "Lista separada por comas"),
+	openapi.QueryParam("categorias_ayuda_match", "string", "any, all u only"),
+	openapi.QueryParam("tipos_acceso", "string", "Lista separada por comas"),
+	openapi.QueryParam("tipos_acceso_match", "string", "any, all u only"),
+	openapi.QueryParam("nivel_urgencia", "string", "Lista separada por comas"),
+	openapi.QueryParam("riesgo_asbesto", "string", "Lista separada por comas"),
+	openapi.QueryParam("requiere_voluntarios", "boolean", ""),
+	openapi.QueryParam("habitado_actualmente", "boolean", ""),
+	openapi.QueryParam("tiene_banos", "boolean", ""),
+	openapi.QueryParam("tiene_electricidad", "boolean", ""),
+	openapi.QueryParam("tiene_senal", "boolean", ""),
+	openapi.QueryParam("min_camas", "integer", "Solo albergues con al menos esa cantidad de camas disponibles"),
+	openapi.QueryParam("open_now", "boolean", "Solo puntos con horario_osm abiertos ahora (hora de Chile)"),
+	openapi.QueryParam("open_at", "string", "Como open_now en otro instante: RFC3339 o YYYY-MM-DDTHH:MM hora de Chile"),
+}
+
+var paginationParams = []openapi.Parameter{
+	openapi.QueryParam("page", "integer", "Página (modo page/limit)"),
+	openapi.QueryParam("limit", "integer", "Resultados por página"),
+	openapi.QueryParam("cursor", "string", "Paginación keyset: vacío para la primera página, luego next_cursor"),
+}
+
+var estadoParam = openapi.QueryParam("estado", "string", "Estado del punto")
+
+// voluntarioFilterParams son los filtros del listado y de las sugerencias de voluntarios
+var voluntarioFilterParams = []openapi.Parameter{
+	openapi.QueryParam("ciudad", "string", "Sin distinguir acentos ni mayúsculas"),
+	openapi.QueryParam("habilidad", "string", ""),
+	openapi.QueryParam("vehiculo", "string", "Lista separada por comas: pie, auto, 4x4, camion"),
+	openapi.QueryParam("activo", "boolean", ""),
+	openapi.QueryParam("libres", "boolean", "Solo voluntarios sin punto asignado"),
+	openapi.QueryParam("disponible_ahora", "boolean", "Solo voluntarios con disponibilidad_osm que cubre este momento"),
+	openapi.QueryParam("disponible_at", "string", "Como disponible_ahora en otro instante: RFC3339 o YYYY-MM-DDTHH:MM hora de Chile"),
+	openapi.QueryParam("limit", "integer", "Default 100, max 500"),
+}
+
+// turnoFilterParams son los filtros de los listados de turnos (parseTurnoFilter)
+var turnoFilterParams = []openapi.Parameter{
+	openapi.QueryParam("desde", "string", "Turnos que terminan después de este instante (default ahora): RFC3339 o YYYY-MM-DDTHH:MM hora de Chile"),
+	openapi.QueryParam("hasta", "string", "Turnos que empiezan antes de este instante"),
+	openapi.QueryParam("libres", "boolean", "Solo turnos con cupos libres"),
+	openapi.QueryParam("limit", "integer", "Default 200, max 1000"),
+}
+
+// voluntarioTokenParams identifican al voluntario en sus rutas de turnos
+var voluntarioTokenParams = []openapi.Parameter{
+	{Name: middleware.VoluntarioTokenHeader, In: "header", Description: "Token recibido al inscribirse", Schema: &openapi.Schema{Type: "string"}},
+	openapi.QueryParam("token", "string", "Alternativa al header, para suscribirse al calendario"),
+}
+
+var matchParams = []openapi.Parameter{
+	openapi.QueryParam("max_distance_m", "number", "Distancia máxima a los acopios (default 20000)"),
+	openapi.QueryParam("per_need", "integer", "Acopios sugeridos por necesidad (default 3, max 10)"),
+}
+
+var statsFormatParam = openapi.QueryParam("format", "string", "json (default) o csv (una fila por comuna)")
+
+func params(groups ...[]openapi.Parameter) []openapi.Parameter {
+	var out []openapi.Parameter
+	for _, g := range groups {
+		out = append(out, g...)
+	}
+	return out
+}
+
+// Listado de puntos en la vista pública (PuntosListView con PuntoPublic)
+type puntosPublicList struct {
+	Data       []models.PuntoPublic `json:"data"`
+	Total      *int                 `json:"total,omitempty"`
+	Page       int                  `json:"page,omitempty"`
+	Limit      int                  `json:"limit"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+// apiRoutes documenta las rutas de main.go. Una ruta registrada que no esté aquí
+// aparece igual en el documento, sin cuerpo ni respuesta tipada.
+var apiRoutes = map[string]openapi.Route{
+	// --- Públicas ---
+	"GET /api/puntos": {
+		Summary: "Lista los puntos publicados", Tag: "puntos",
+		Query: params(puntoFilterParams, paginationParams), Response: puntosPublicList{},
+	},
+	"GET /api/puntos.geojson": {
+		Summary: "Puntos publicados como GeoJSON FeatureCollection", Tag: "puntos",
+		Query: params(puntoFilterParams, paginationParams), Response: models.GeoJSONFeatureCollection{},
+	},
+	"GET /api/puntos.hxl.csv": {
+		Summary: "Puntos publicados como CSV con hashtags HXL", Tag: "exportacion",
+		Query: puntoFilterParams, ContentType: "text/csv",
+	},
+	"GET /api/hxl/dictionary": {
+		Summary: "Diccionario de datos de la exportación HXL", Tag: "exportacion",
+		Response: struct {
+			Standard string                   `json:"standard"`
+			Columns  []export.DictionaryEntry `json:"columns"`
+		}{},
+	},
+	"GET /api/puntos/clusters": {
+		Summary: "Agrupa los puntos del bbox según el zoom", Tag: "puntos",
+		Query:    params(puntoFilterParams, []openapi.Parameter{openapi.QueryParam("zoom", "integer", "Nivel de zoom (0-22)")}),
+		Response: models.ClustersResponse{},
+	},
+	"GET /api/puntos/changes": {
+		Summary: "Cambios desde el último token (sincronización incremental)", Tag: "puntos",
+		Query:    []openapi.Parameter{openapi.QueryParam("since", "string", "next_token de la respuesta anterior")},
+		Response: models.PuntoChangesResponse{},
+	},
+	"GET /api/puntos/stream": {
+		Summary: "Cambios en vivo por Server-Sent Events", Tag: "puntos",
+		Query:       []openapi.Parameter{openapi.QueryParam("bbox", "string", ""), openapi.QueryParam("categoria", "string", "")},
+		ContentType: "text/event-stream",
+	},
+	"GET /api/puntos/{id}": {
+		Summary: "Obtiene un punto publicado", Tag: "puntos", Response: models.PuntoPublic{},
+	},
+	"GET /api/puntos/{id}/matches": {
+		Summary: "Acopios cercanos que cubren cada necesidad del punto", Tag: "matching",
+		Query: matchParams, Response: models.PuntoMatches{},
+	},
+	"GET /api/matches": {
+		Summary: "Cruce de necesidades de las zonas (categoria=sos por defecto) con los acopios", Tag: "matching",
+		Query: params(puntoFilterParams, matchParams, []openapi.Parameter{
+			openapi.QueryParam("unmet", "boolean", "Solo puntos con necesidades sin cubrir"),
+		}),
+		Response: models.MatchesResponse{},
+	},
+	"GET /api/puntos/{id}/inventario": {
+		Summary: "Stock actual de un punto publicado", Tag: "inventario", Response: models.InventarioResponse{},
+	},
+	"GET /api/inventario": {
+		Summary: "Busca stock entre los puntos publicados (lo que más falta primero)", Tag: "inventario",
+		Query: []openapi.Parameter{
+			openapi.QueryParam("categoria", "string", "alimentos, herramientas, techo_abrigo, animales, medicamentos, olla_comun u otros"),
+			openapi.QueryParam("item", "string", "Nombre del ítem (sin distinguir acentos ni mayúsculas)"),
+			openapi.QueryParam("ciudad", "string", ""),
+			openapi.QueryParam("faltantes", "boolean", "Solo ítems bajo su objetivo"),
+			openapi.QueryParam("limit", "integer", "Default 100, max 500"),
+		},
+		Response: models.InventarioResponse{},
+	},
+	"GET /api/stats": {
+		Summary: "Conteos de puntos publicados por comuna, categoría, estado y urgencia", Tag: "estadisticas",
+		Query: params(puntoFilterParams, []openapi.Parameter{statsFormatParam}), Response: models.PuntoStats{},
+	},
+	"GET /api/tiles/{z}/{x}/{y}.mvt": {
+		Summary: "Tile vectorial con los puntos publicados", Tag: "puntos",
+		Query:       []openapi.Parameter{openapi.QueryParam("categoria", "string", ""), openapi.QueryParam("subtipo", "string", "")},
+		ContentType: "application/vnd.mapbox-vector-tile",
+	},
+	"GET /api/challenge": {
+		Summary: "Desafío de prueba de trabajo para envíos públicos", Tag: "envios", Response: pow.Challenge{},
+	},
+	"POST /api/puntos": {
+		Summary: "Propone un punto (queda pendiente de moderación)", Tag: "envios",
+		Request: models.PuntoSubmissionRequest{}, Response: models.SolicitudAcceptedResponse{}, Status: http.StatusAccepted,
+	},
+	"POST /api/solicitudes": {
+		Summary: "Envía un mensaje y/o punto propuesto a moderación", Tag: "envios",
+		Request: models.SolicitudCreateRequest{}, Response: models.SolicitudAcceptedResponse{}, Status: http.StatusAccepted,
+	},
+	"POST /api/voluntarios": {
+		Summary: "Inscribe a un voluntario (teléfono o email obligatorio)", Tag: "voluntarios",
+		Request: models.VoluntarioCreateRequest{}, Response: models.VoluntarioAcceptedResponse{}, Status: http.StatusCreated,
+	},
+	"GET /api/puntos/{id}/compromisos": {
+		Summary: "Lo comprometido por donantes frente a lo que falta, por necesidad", Tag: "compromisos",
+		Response: models.PuntoCompromisosResponse{},
+	},
+	"POST /api/puntos/{id}/compromisos": {
+		Summary: "Compromete una donación para una necesidad del punto", Tag: "compromisos",
+		Request: models.CompromisoCreateRequest{}, Response: models.CompromisoAcceptedResponse{}, Status: http.StatusCreated,
+	},
+	"POST /api/compromisos/{id}/cancelar": {
+		Summary: "Cancela un compromiso con el token del donante", Tag: "compromisos",
+		Request: models.CompromisoCancelarRequest{}, Response: messageResponse{},
+	},
+	"GET /api/puntos/{id}/rutas.geojson": {
+		Summary: "Rutas de acceso de un punto publicado (de su KML) para dibujar en el mapa", Tag: "rutas",
+		ContentType: models.GeoJSONContentType,
+	},
+	"GET /api/puntos/{id}/turnos": {
+		Summary: "Turnos de un punto publicado con sus cupos libres", Tag: "turnos",
+		Query: turnoFilterParams, Response: models.TurnosResponse{},
+	},
+	"GET /api/puntos/{id}/turnos.ics": {
+		Summary: "Turnos de un punto publicado como calendario iCalendar", Tag: "turnos", ContentType: "text/calendar",
+	},
+	"GET /api/turnos": {
+		Summary: "Busca turnos entre los puntos publicados", Tag: "turnos",
+		Query: params(turnoFilterParams, []openapi.Parameter{
+			openapi.QueryParam("ciudad", "string", ""),
+			openapi.QueryParam("rol", "string", "Sin distinguir acentos ni mayúsculas"),
+		}),
+		Response: models.TurnosResponse{},
+	},
+	"GET /api/voluntarios/me/turnos": {
+		Summary: "Turnos que tomó el voluntario del token", Tag: "turnos",
+		Query: params(voluntarioTokenParams, turnoFilterParams), Response: models.TurnosResponse{},
+	},
+	"GET /api/voluntarios/me/turnos.ics": {
+		Summary: "Calendario personal del voluntario (incluye la última semana)", Tag: "turnos",
+		Query: voluntarioTokenParams, ContentType: "text/calendar",
+	},
+	"POST /api/turnos/{id}/tomar": {
+		Summary: "Toma un cupo del turno (409 si está lleno, terminó o se cruza con otro turno del voluntario)", Tag: "turnos",
+		Query: voluntarioTokenParams, Response: models.Turno{},
+	},
+	"POST /api/turnos/{id}/soltar": {
+		Summary: "Libera el cupo del voluntario en el turno", Tag: "turnos",
+		Query: voluntarioTokenParams, Response: models.Turno{},
+	},
+	"GET /api/openapi.json": {
+		Summary: "Este documento", Tag: "meta",
+	},
+
+	// --- Auth ---
+	"POST /api/auth/login": {
+		Summary: "Login con email y contraseña", Tag: "auth",
+		Request: models.UserLogin{}, Response: models.LoginResponse{},
+	},
+	"GET /api/auth/me": {
+		Summary: "Usuario autenticado", Tag: "auth", Auth: true, Response: models.UserResponse{},
+	},
+	"POST /api/auth/logout": {
+		Summary: "Logout (el token se descarta en el cliente)", Tag: "auth", Auth: true, Response: messageResponse{},
+	},
+	"POST /api/auth/change-password": {
+		Summary: "Cambia la contraseña", Tag: "auth", Auth: true,
+		Request: models.ChangePasswordRequest{}, Response: messageResponse{},
+	},
+	"POST /api/auth/confirm-password": {
+		Summary: "Confirma o reemplaza la contraseña temporal", Tag: "auth", Auth: true,
+		Request: models.ConfirmPasswordRequest{}, Response: messageResponse{},
+	},
+
+	// --- Admin ---
+	"GET /api/admin/puntos": {
+		Summary: "Lista todos los puntos (vista según rol)", Tag: "admin", Auth: true, Roles: rolesVerificador,
+		Query: params(puntoFilterParams, paginationParams, []openapi.Parameter{estadoParam}), Response: models.PuntosListResponse{},
+	},
+	"POST /api/admin/puntos": {
+		Summary: "Crea un punto", Tag: "admin", Auth: true, Roles: rolesAdmin,
+		Request: models.PuntoCreateRequest{}, Response: models.Punto{}, Status: http.StatusCreated,
+	},
+	"PATCH /api/admin/puntos/{id}": {
+		Summary: "Actualiza un punto (solo los campos enviados)", Tag: "admin", Auth: true, Roles: rolesAdmin,
+		Request: models.PuntoUpdateRequest{}, Response: models.Punto{},
+	},
+	"PATCH /api/admin/puntos/{id}/estado": {
+		Summary: "Cambia el estado de un punto", Tag: "admin", Auth: true, Roles: rolesVerificador,
+		Request: models.EstadoUpdateRequest{}, Response: models.Punto{},
+	},
+	"DELETE /api/admin/puntos/{id}": {
+		Summary: "Oculta un punto (soft delete)", Tag: "admin", Auth: true, Roles: rolesSuperadmin, Response: messageResponse{},
+	},
+	"POST /api/admin/puntos/{id}/fotos": {
+		Summary: "Sube una foto de evidencia o de asbesto al punto", Tag: "fotos", Auth: true, Roles: rolesVerificador,
+		Description: "Se guarda sin metadatos EXIF (ni GPS) y con una miniatura de 400 px (mismo nombre con _thumb). " +
+			"evidencia se agrega a evidencia_fotos (máximo 20) y asbesto reemplaza foto_asbesto. " +
+			"413 si el archivo supera UPLOAD_MAX_MB, 415 si no es JPEG ni PNG.",
+		Request: models.FotoUploadForm{}, RequestType: "multipart/form-data",
+		Response: models.FotoUploadResponse{}, Status: http.StatusCreated,
+	},
+	"POST /api/admin/puntos/{id}/rutas": {
+		Summary: "Sube un KML o KMZ con las rutas de acceso del punto", Tag: "rutas", Auth: true, Roles: rolesVerificador,
+		Description: "Reemplaza las rutas anteriores y deja la URL del archivo en archivo_kml. Se rechaza (400) si no trae " +
+			"geometrías, si pasa de 200 geometrías o 50.000 vértices, o si alguna coordenada cae fuera de Chile. " +
+			"413 si supera UPLOAD_MAX_MB o 5 MB descomprimido, 415 si no es KML ni KMZ.",
+		Request: models.RutasUploadForm{}, RequestType: "multipart/form-data",
+		Response: models.RutasUploadResponse{}, Status: http.StatusCreated,
+	},
+	"DELETE /api/admin/puntos/{id}/rutas": {
+		Summary: "Quita las rutas de acceso del punto y vacía archivo_kml", Tag: "rutas", Auth: true, Roles: rolesVerificador,
+		Response: models.Punto{},
+	},
+	"POST /api/admin/puntos/{id}/ocupacion": {
+		Summary: "Reporta la ocupación actual de un albergue (un encargado solo los suyos)", Tag: "albergues", Auth: true, Roles: rolesEncargado,
+		Request: models.OcupacionRequest{}, Response: models.Punto{},
+	},
+	"GET /api/admin/puntos/{id}/ocupacion": {
+		Summary: "Historial de ocupación de un albergue", Tag: "albergues", Auth: true, Roles: rolesEncargado,
+		Query:    []openapi.Parameter{openapi.QueryParam("limit", "integer", "Reportes a devolver (default 50, max 500)")},
+		Response: models.OcupacionHistorialResponse{},
+	},
+	"GET /api/admin/puntos/{id}/encargados": {
+		Summary: "Lista los encargados de un punto", Tag: "albergues", Auth: true, Roles: rolesAdmin, Response: []models.UserResponse{},
+	},
+	"PUT /api/admin/puntos/{id}/encargados/{userId}": {
+		Summary: "Deja a un usuario con rol encargado a cargo de un punto (409 si tiene otro rol)", Tag: "albergues", Auth: true, Roles: rolesAdmin, Response: messageResponse{},
+	},
+	"DELETE /api/admin/puntos/{id}/encargados/{userId}": {
+		Summary: "Quita a un usuario de los encargados de un punto", Tag: "albergues", Auth: true, Roles: rolesAdmin, Response: messageResponse{},
+	},
+	"GET /api/admin/mis-puntos": {
+		Summary: "Puntos a cargo del usuario autenticado", Tag: "albergues", Auth: true, Roles: rolesEncargado, Response: models.PuntosListResponse{},
+	},
+	"GET /api/admin/puntos/{id}/inventario": {
+		Summary: "Inventario de un punto en cualquier estado", Tag: "inventario", Auth: true, Roles: rolesEncargado,
+		Response: models.InventarioResponse{},
+	},
+	"POST /api/admin/puntos/{id}/inventario": {
+		Summary: "Agrega un ítem al inventario (409 si ya existe con esa unidad)", Tag: "inventario", Auth: true, Roles: rolesEncargado,
+		Request: models.InventarioItemCreateRequest{}, Response: models.InventarioItem{}, Status: http.StatusCreated,
+	},
+	"PATCH /api/admin/puntos/{id}/inventario/{itemId}": {
+		Summary: "Cambia nombre, categoría, unidad u objetivo de un ítem (409 si choca con otro del punto)", Tag: "inventario", Auth: true, Roles: rolesEncargado,
+		Request: models.InventarioItemUpdateRequest{}, Response: models.InventarioItem{},
+	},
+	"DELETE /api/admin/puntos/{id}/inventario/{itemId}": {
+		Summary: "Quita un ítem y su historial", Tag: "inventario", Auth: true, Roles: rolesEncargado, Response: messageResponse{},
+	},
+	"GET /api/admin/puntos/{id}/inventario/{itemId}/movimientos": {
+		Summary: "Historial de stock de un ítem", Tag: "inventario", Auth: true, Roles: rolesEncargado,
+		Query: []openapi.Parameter{openapi.QueryParam("limit", "integer", "Default 50, max 500")}, Response: models.MovimientosResponse{},
+	},
+	"POST /api/admin/puntos/{id}/inventario/{itemId}/movimientos": {
+		Summary: "Registra una entrada, salida o ajuste de stock (409 si no alcanza el stock)", Tag: "inventario", Auth: true, Roles: rolesEncargado,
+		Request: models.MovimientoRequest{}, Response: models.InventarioItem{}, Status: http.StatusCreated,
+	},
+	"GET /api/admin/voluntarios": {
+		Summary: "Voluntarios inscritos, los más recientes primero", Tag: "voluntarios", Auth: true, Roles: rolesVerificador,
+		Query: voluntarioFilterParams, Response: models.VoluntariosResponse{},
+	},
+	"GET /api/admin/voluntarios/puntos": {
+		Summary: "Puntos que piden voluntarios con su cupo y asignados (los que más faltan primero)", Tag: "voluntarios", Auth: true, Roles: rolesVerificador,
+		Query: []openapi.Parameter{openapi.QueryParam("ciudad", "string", "")}, Response: models.PuntosVoluntariosResponse{},
+	},
+	"GET /api/admin/voluntarios/{id}": {
+		Summary: "Ficha de un voluntario", Tag: "voluntarios", Auth: true, Roles: rolesVerificador, Response: models.Voluntario{},
+	},
+	"PATCH /api/admin/voluntarios/{id}": {
+		Summary: "Corrige la ficha de un voluntario (activo=false lo saca de las sugerencias)", Tag: "voluntarios", Auth: true, Roles: rolesVerificador,
+		Request: models.VoluntarioUpdateRequest{}, Response: models.Voluntario{},
+	},
+	"DELETE /api/admin/voluntarios/{id}": {
+		Summary: "Borra la ficha de un voluntario y sus asignaciones", Tag: "voluntarios", Auth: true, Roles: rolesAdmin, Response: messageResponse{},
+	},
+	"GET /api/admin/puntos/{id}/voluntarios": {
+		Summary: "Cupo de voluntarios de un punto y los asignados", Tag: "voluntarios", Auth: true, Roles: rolesEncargado,
+		Response: models.PuntoVoluntariosResponse{},
+	},
+	"GET /api/admin/puntos/{id}/voluntarios/sugeridos": {
+		Summary: "Voluntarios activos de la ciudad del punto cuyo vehículo llega según tipos_acceso", Tag: "voluntarios", Auth: true, Roles: rolesVerificador,
+		Query: voluntarioFilterParams, Response: models.VoluntariosResponse{},
+	},
+	"PUT /api/admin/puntos/{id}/voluntarios/{voluntarioId}": {
+		Summary: "Asigna un voluntario activo al punto", Tag: "voluntarios", Auth: true, Roles: rolesVerificador, Response: models.PuntoVoluntarios{},
+	},
+	"DELETE /api/admin/puntos/{id}/voluntarios/{voluntarioId}": {
+		Summary: "Quita un voluntario del punto", Tag: "voluntarios", Auth: true, Roles: rolesVerificador, Response: models.PuntoVoluntarios{},
+	},
+	"GET /api/admin/puntos/{id}/compromisos": {
+		Summary: "Compromisos de donación del punto con los datos del donante", Tag: "compromisos", Auth: true, Roles: rolesEncargado,
+		Query: []openapi.Parameter{
+			openapi.QueryParam("estado", "string", "comprometido, entregado o cancelado"),
+			openapi.QueryParam("vigentes", "boolean", "Solo comprometidos con entrega desde hoy"),
+			openapi.QueryParam("limit", "integer", "Default 100, max 500"),
+		},
+		Response: models.CompromisosResponse{},
+	},
+	"PATCH /api/admin/puntos/{id}/compromisos/{compromisoId}": {
+		Summary: "Marca un compromiso entregado (suma al inventario) o cancelado", Tag: "compromisos", Auth: true, Roles: rolesEncargado,
+		Request: models.CompromisoUpdateRequest{}, Response: models.Compromiso{},
+	},
+	"POST /api/admin/voluntarios/{id}/token": {
+		Summary: "Genera un token de turnos nuevo e invalida el anterior", Tag: "voluntarios", Auth: true, Roles: rolesVerificador,
+		Response: models.VoluntarioTokenResponse{},
+	},
+	"GET /api/admin/puntos/{id}/turnos": {
+		Summary: "Planilla del punto: turnos con los voluntarios inscritos", Tag: "turnos", Auth: true, Roles: rolesEncargado,
+		Query: turnoFilterParams, Response: models.TurnosResponse{},
+	},
+	"POST /api/admin/puntos/{id}/turnos": {
+		Summary: "Crea uno o varios turnos seguidos", Tag: "turnos", Auth: true, Roles: rolesEncargado,
+		Request: models.TurnoCreateRequest{}, Response: models.TurnosResponse{}, Status: http.StatusCreated,
+	},
+	"PATCH /api/admin/puntos/{id}/turnos/{turnoId}": {
+		Summary: "Cambia un turno (el horario solo si nadie lo ha tomado)", Tag: "turnos", Auth: true, Roles: rolesEncargado,
+		Request: models.TurnoUpdateRequest{}, Response: models.Turno{},
+	},
+	"DELETE /api/admin/puntos/{id}/turnos/{turnoId}": {
+		Summary: "Borra un turno y sus inscripciones", Tag: "turnos", Auth: true, Roles: rolesEncargado, Response: messageResponse{},
+	},
+	"PUT /api/admin/puntos/{id}/turnos/{turnoId}/voluntarios/{voluntarioId}": {
+		Summary: "Inscribe a un voluntario en el turno (mismas reglas que tomarlo)", Tag: "turnos", Auth: true, Roles: rolesEncargado,
+		Response: models.Turno{},
+	},
+	"DELETE /api/admin/puntos/{id}/turnos/{turnoId}/voluntarios/{voluntarioId}": {
+		Summary: "Saca a un voluntario del turno", Tag: "turnos", Auth: true, Roles: rolesEncargado, Response: models.Turno{},
+	},
+	"GET /api/admin/export/puntos.csv": {
+		Summary: "Exporta puntos en CSV", Tag: "exportacion", Auth: true, Roles: rolesVerificador,
+		Query: params(puntoFilterParams, []openapi.Parameter{estadoParam}), ContentType: "text/csv",
+	},
+	"GET /api/admin/export/puntos.kml": {
+		Summary: "Exporta puntos en KML", Tag: "exportacion", Auth: true, Roles: rolesVerificador,
+		Query: params(puntoFilterParams, []openapi.Parameter{estadoParam}), ContentType: "application/vnd.google-earth.kml+xml",
+	},
+	"GET /api/admin/export/puntos.gpx": {
+		Summary: "Exporta puntos en GPX", Tag: "exportacion", Auth: true, Roles: rolesVerificador,
+		Query: params(puntoFilterParams, []openapi.Parameter{estadoParam}), ContentType: "application/gpx+xml",
+	},
+	"GET /api/admin/export/puntos.hxl.csv": {
+		Summary: "Exporta puntos en CSV con hashtags HXL", Tag: "exportacion", Auth: true, Roles: rolesVerificador,
+		Query: params(puntoFilterParams, []openapi.Parameter{estadoParam}), ContentType: "text/csv",
+	},
+	"GET /api/admin/stats": {
+		Summary: "Estadísticas sobre todos los estados (sin ocultos)", Tag: "estadisticas", Auth: true, Roles: rolesVerificador,
+		Query: params(puntoFilterParams, []openapi.Parameter{estadoParam, statsFormatParam}), Response: models.PuntoStats{},
+	},
+	"GET /api/admin/solicitudes": {
+		Summary: "Cola de moderación", Tag: "moderacion", Auth: true, Roles: rolesVerificador,
+		Query: []openapi.Parameter{
+			openapi.QueryParam("estado", "string", "pendiente (default), aprobada o rechazada"),
+			openapi.QueryParam("page", "integer", ""),
+			openapi.QueryParam("limit", "integer", ""),
+		},
+		Response: models.SolicitudesListResponse{},
+	},
+	"PATCH /api/admin/solicitudes/{id}": {
+		Summary: "Corrige el punto de una solicitud pendiente", Tag: "moderacion", Auth: true, Roles: rolesVerificador,
+		Request: models.PuntoUpdateRequest{}, Response: models.Solicitud{},
+	},
+	"POST /api/admin/solicitudes/{id}/aprobar": {
+		Summary: "Aprueba la solicitud y publica su punto", Tag: "moderacion", Auth: true, Roles: rolesVerificador,
+		Request: models.SolicitudAprobarRequest{}, Response: models.Solicitud{},
+	},
+	"POST /api/admin/solicitudes/{id}/rechazar": {
+		Summary: "Rechaza la solicitud con un motivo", Tag: "moderacion", Auth: true, Roles: rolesVerificador,
+		Request: models.SolicitudRechazarRequest{}, Response: models.Solicitud{},
+	},
+	"POST /api/admin/import-csv": {
+		Summary: "Importa puntos desde datos de un CSV", Tag: "admin", Auth: true, Roles: rolesAdmin,
+		Request: models.CSVImportRequest{}, Response: models.CSVImportResponse{},
+	},
+	"GET /api/admin/users": {
+		Summary: "Lista usuarios", Tag: "usuarios", Auth: true, Roles: rolesAdmin, Response: []models.UserResponse{},
+	},
+	"POST /api/admin/users": {
+		Summary: "Crea un usuario", Tag: "usuarios", Auth: true, Roles: rolesSuperadmin,
+		Request: models.CreateUserRequest{}, Response: models.UserResponse{}, Status: http.StatusCreated,
+	},
+	"PATCH /api/admin/users/{id}/rol": {
+		Summary: "Cambia el rol de un usuario", Tag: "usuarios", Auth: true, Roles: rolesSuperadmin,
+		Request: struct {
+			Rol string `json:"rol" validate:"required,enum=superadmin|admin|verificador|encargado"`
+		}{},
+		Response: models.UserResponse{},
+	},
+	"PATCH /api/admin/users/{id}/toggle-active": {
+		Summary: "Activa o desactiva un usuario", Tag: "usuarios", Auth: true, Roles: rolesSuperadmin,
+		Request: struct {
+			Activo bool `json:"activo" validate:"required"`
+		}{},
+		Response: models.UserResponse{},
+	},
+}
+
+// OpenAPI sirve el documento OpenAPI 3 de las rutas registradas en router.
+// Se arma en la primera petición, cuando todas las rutas ya están registradas.
+func OpenAPI(router chi.Routes) http.HandlerFunc {
+	var (
+		once sync.Once
+		body []byte
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			doc, err := openapi.Build(openapi.Info{
+				Title:       "Donde Ayudo CL API",
+				Version:     "1.0.0",
+				Description: "Puntos de ayuda (acopio, albergues, hidratación y zonas SOS) en Chile.",
+			}, router, apiSchemas, apiRoutes)
+			if err != nil {
+				log.Printf("❌ Error generando OpenAPI: %v", err)
+				return
+			}
+			body, _ = json.Marshal(doc)
+		})
+
+		if body == nil {
+			http.Error(w, `{"error":"Error generating OpenAPI document"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", publicCacheControl)
+		w.Write(body)
+	}
+}
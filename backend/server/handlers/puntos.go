@@ -2,38 +2,303 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/horario"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
 	"github.com/go-chi/chi/v5"
 )
 
-func GetPuntos(w http.ResponseWriter, r *http.Request) {
-	categoria := r.URL.Query().Get("categoria")
-	subtipo := r.URL.Query().Get("subtipo")
-	ciudad := r.URL.Query().Get("ciudad")
+// maxRadiusM limita el radio de búsqueda de near (500 km)
+const maxRadiusM = 500000
+
+// maxSearchLength limita el largo del parámetro q
+const maxSearchLength = 200
+
+const (
+	// defaultMaxLimit es el máximo de resultados por página del listado JSON
+	defaultMaxLimit = 100
+	// geoJSONMaxLimit permite a clientes GIS (QGIS, Leaflet) traer más puntos por request
+	geoJSONMaxLimit = 1000
+)
+
+// parsePuntoFilter lee los filtros comunes de los listados de puntos desde la query string
+func parsePuntoFilter(r *http.Request, maxLimit int) (models.PuntoFilter, error) {
+	q := r.URL.Query()
+
+	f := models.PuntoFilter{
+		Categoria: q.Get("categoria"),
+		Subtipo:   q.Get("subtipo"),
+		Ciudad:    q.Get("ciudad"),
+		Q:         strings.TrimSpace(q.Get("q")),
+		Sort:      q.Get("sort"),
+	}
+
+	if len(f.Q) > maxSearchLength {
+		return f, errors.New("q is too long")
+	}
+
+	f.CategoriasAyuda = parseList(q.Get("categorias_ayuda"))
+	f.TiposAcceso = parseList(q.Get("tipos_acceso"))
+	f.NivelesUrgencia = parseList(q.Get("nivel_urgencia"))
+	f.RiesgoAsbesto = parseList(q.Get("riesgo_asbesto"))
+
+	var err error
+	if f.CategoriasAyudaMatch, err = parseArrayMatch(q.Get("categorias_ayuda_match")); err != nil {
+		return f, errors.New("Invalid categorias_ayuda_match, expected any, all or only")
+	}
+	if f.TiposAccesoMatch, err = parseArrayMatch(q.Get("tipos_acceso_match")); err != nil {
+		return f, errors.New("Invalid tipos_acceso_match, expected any, all or only")
+	}
+
+	flags := map[string]**bool{
+		"requiere_voluntarios": &f.RequiereVoluntarios,
+		"habitado_actualmente": &f.HabitadoActualmente,
+		"tiene_banos":          &f.TieneBanos,
+		"tiene_electricidad":   &f.TieneElectricidad,
+		"tiene_senal":          &f.TieneSenal,
+	}
+	for name, dest := range flags {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return f, errors.New("Invalid " + name + ", expected true or false")
+		}
+		*dest = &value
+	}
+
+	if raw := q.Get("min_camas"); raw != "" {
+		minCamas, err := strconv.Atoi(raw)
+		if err != nil || minCamas < 1 {
+			return f, errors.New("Invalid min_camas, expected a positive integer")
+		}
+		f.MinCamas = minCamas
+	}
+
+	if raw := q.Get("open_now"); raw != "" {
+		openNow, err := strconv.ParseBool(raw)
+		if err != nil {
+			return f, errors.New("Invalid open_now, expected true or false")
+		}
+		if openNow {
+			now := time.Now()
+			f.OpenAt = &now
+		}
+	}
+	if raw := q.Get("open_at"); raw != "" {
+		if f.OpenAt != nil {
+			return f, errors.New("Use open_now or open_at, not both")
+		}
+		at, err := parseOpenAt(raw)
+		if err != nil {
+			return f, errors.New("Invalid open_at, expected RFC3339 or YYYY-MM-DDTHH:MM (Chile local time)")
+		}
+		f.OpenAt = &at
+	}
+
+	f.Page, _ = strconv.Atoi(q.Get("page"))
+	if f.Page < 1 {
+		f.Page = 1
+	}
+
+	// La presencia de cursor (aunque esté vacío, para la primera página) activa el modo keyset
+	if _, ok := q["cursor"]; ok {
+		f.UseCursor = true
+		if token := q.Get("cursor"); token != "" {
+			cursor, err := models.DecodePuntoCursor(token)
+			if err != nil {
+				return f, errors.New("Invalid cursor")
+			}
+			f.Cursor = cursor
+		}
+	}
+
+	f.Limit, _ = strconv.Atoi(q.Get("limit"))
+	if f.Limit < 1 || f.Limit > maxLimit {
+		f.Limit = 50
+	}
+
+	if raw := q.Get("bbox"); raw != "" {
+		bbox, err := parseBBox(raw)
+		if err != nil {
+			return f, err
+		}
+		f.BBox = bbox
+	}
+
+	if raw := q.Get("near"); raw != "" {
+		vals, err := parseFloats(raw, 2)
+		if err != nil || vals[0] < -90 || vals[0] > 90 || vals[1] < -180 || vals[1] > 180 {
+			return f, errors.New("Invalid near, expected lat,lng")
+		}
+		f.Near = &models.GeoPoint{Lat: vals[0], Lng: vals[1]}
+	}
+
+	if raw := q.Get("radius_m"); raw != "" {
+		radius, err := strconv.ParseFloat(raw, 64)
+		if err != nil || radius <= 0 || radius > maxRadiusM {
+			return f, errors.New("Invalid radius_m")
+		}
+		if f.Near == nil {
+			return f, errors.New("radius_m requires near")
+		}
+		f.RadiusM = radius
+	}
+
+	switch f.Sort {
+	case "":
+		// Sin orden explícito: cercanía si hay near, relevancia si hay búsqueda
+		// (el modo cursor solo pagina por fecha de creación)
+		if f.UseCursor {
+			break
+		}
+		if f.Near != nil {
+			f.Sort = "distance"
+		} else if f.Q != "" {
+			f.Sort = "relevance"
+		}
+	case "created":
+	case "distance":
+		if f.Near == nil {
+			return f, errors.New("sort=distance requires near")
+		}
+	case "relevance":
+		if f.Q == "" {
+			return f, errors.New("sort=relevance requires q")
+		}
+	default:
+		return f, errors.New("Invalid sort value")
+	}
 
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
+	if f.UseCursor && f.Sort != "" && f.Sort != "created" {
+		return f, errors.New("cursor pagination only supports sort=created")
 	}
 
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	if limit < 1 || limit > 100 {
-		limit = 50
+	return f, nil
+}
+
+// parseOpenAt acepta un instante RFC3339 o una hora local de Chile sin zona
+func parseOpenAt(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
 	}
+	return time.ParseInLocation("2006-01-02T15:04", raw, horario.Santiago)
+}
 
-	response, err := database.GetPuntos(categoria, subtipo, ciudad, "publicado", page, limit)
+// parseList separa un parámetro "a,b,c" ignorando valores vacíos
+func parseList(raw string) []string {
+	values := []string{}
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func parseArrayMatch(raw string) (string, error) {
+	switch raw {
+	case "", "any":
+		return "any", nil
+	case "all", "only":
+		return raw, nil
+	}
+	return "", errors.New("invalid match")
+}
+
+// parseBBox interpreta minLng,minLat,maxLng,maxLat
+func parseBBox(raw string) (*models.BBox, error) {
+	vals, err := parseFloats(raw, 4)
+	if err != nil {
+		return nil, errors.New("Invalid bbox, expected minLng,minLat,maxLng,maxLat")
+	}
+
+	bbox := &models.BBox{MinLng: vals[0], MinLat: vals[1], MaxLng: vals[2], MaxLat: vals[3]}
+	if bbox.MinLng >= bbox.MaxLng || bbox.MinLat >= bbox.MaxLat ||
+		bbox.MinLat < -90 || bbox.MaxLat > 90 || bbox.MinLng < -180 || bbox.MaxLng > 180 {
+		return nil, errors.New("Invalid bbox, expected minLng,minLat,maxLng,maxLat")
+	}
+	return bbox, nil
+}
+
+func parseFloats(raw string, n int) ([]float64, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != n {
+		return nil, errors.New("wrong number of values")
+	}
+
+	vals := make([]float64, n)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
+
+// writeJSONError responde un error con el formato {"error": "..."} de la API
+func writeJSONError(w http.ResponseWriter, message string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// wantsGeoJSON indica si el cliente pidió GeoJSON vía Accept
+func wantsGeoJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), models.GeoJSONContentType)
+}
+
+func GetPuntos(w http.ResponseWriter, r *http.Request) {
+	if wantsGeoJSON(r) {
+		GetPuntosGeoJSON(w, r)
+		return
+	}
+
+	filter, err := parsePuntoFilter(r, defaultMaxLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Estado = "publicado"
+
+	response, err := database.GetPuntos(filter)
 	if err != nil {
 		log.Printf("❌ Error en GetPuntos: %v", err)
 		http.Error(w, `{"error":"Error fetching puntos"}`, http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, response.ForRole(""))
+}
+
+// GetPuntosGeoJSON devuelve los puntos publicados como FeatureCollection (mismos filtros que GetPuntos)
+func GetPuntosGeoJSON(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePuntoFilter(r, geoJSONMaxLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Estado = "publicado"
+
+	response, err := database.GetPuntos(filter)
+	if err != nil {
+		log.Printf("❌ Error en GetPuntosGeoJSON: %v", err)
+		http.Error(w, `{"error":"Error fetching puntos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, models.GeoJSONContentType, publicCacheControl, response.ToGeoJSON())
 }
 
 func GetPunto(w http.ResponseWriter, r *http.Request) {
@@ -50,6 +315,105 @@ func GetPunto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, punto.ToPublic())
+}
+
+// maxZoom es el zoom máximo aceptado por clusters y tiles
+const maxZoom = 22
+
+// GetPuntoClusters agrupa los puntos publicados del bbox según el nivel de zoom del mapa
+func GetPuntoClusters(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePuntoFilter(r, defaultMaxLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.BBox == nil {
+		writeJSONError(w, "bbox is required", http.StatusBadRequest)
+		return
+	}
+
+	zoom, err := strconv.Atoi(r.URL.Query().Get("zoom"))
+	if err != nil || zoom < 0 || zoom > maxZoom {
+		writeJSONError(w, "Invalid zoom", http.StatusBadRequest)
+		return
+	}
+	filter.Estado = "publicado"
+
+	response, err := database.GetPuntoClusters(filter, zoom)
+	if err != nil {
+		log.Printf("❌ Error en GetPuntoClusters: %v", err)
+		http.Error(w, `{"error":"Error fetching clusters"}`, http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(punto)
+	json.NewEncoder(w).Encode(response.ToPublic())
+}
+
+// parseSince acepta el next_token de una respuesta anterior o un timestamp RFC3339
+func parseSince(raw string) (models.PuntoCursor, error) {
+	if raw == "" {
+		return models.PuntoCursor{Created: time.Unix(0, 0).UTC().Format(time.RFC3339Nano)}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		return models.PuntoCursor{Created: t.UTC().Format(time.RFC3339Nano)}, nil
+	}
+	cursor, err := models.DecodePuntoCursor(raw)
+	if err != nil {
+		return models.PuntoCursor{}, err
+	}
+	return *cursor, nil
+}
+
+// GetPuntoChanges devuelve los puntos creados, actualizados o retirados del mapa
+// desde since, para que los clientes offline sincronicen solo lo que cambió.
+// Sin since, entrega todo desde el inicio (sincronización completa paginada).
+func GetPuntoChanges(w http.ResponseWriter, r *http.Request) {
+	since, err := parseSince(r.URL.Query().Get("since"))
+	if err != nil {
+		writeJSONError(w, "Invalid since, expected next_token or RFC3339 timestamp", http.StatusBadRequest)
+		return
+	}
+	sinceTime, _ := time.Parse(time.RFC3339Nano, since.Created)
+
+	puntos, hasMore, err := database.GetPuntoChanges(since, database.ChangesPageSize)
+	if err != nil {
+		log.Printf("❌ Error en GetPuntoChanges: %v", err)
+		http.Error(w, `{"error":"Error fetching changes"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := models.PuntoChangesResponse{
+		Created:   []models.PuntoPublic{},
+		Updated:   []models.PuntoPublic{},
+		Removed:   []string{},
+		NextToken: since.Encode(),
+		HasMore:   hasMore,
+	}
+
+	for i := range puntos {
+		p := &puntos[i]
+		created, _ := time.Parse(time.RFC3339Nano, p.Created)
+
+		switch {
+		case p.Estado != "publicado" && !p.FuePublicado:
+			// Envío en moderación o rechazado: el público nunca lo vio
+			continue
+		case p.Estado != "publicado":
+			// Despublicado u oculto: el cliente lo quita si lo tenía
+			response.Removed = append(response.Removed, p.ID)
+		case created.After(sinceTime):
+			response.Created = append(response.Created, p.ToPublic())
+		default:
+			response.Updated = append(response.Updated, p.ToPublic())
+		}
+	}
+
+	if len(puntos) > 0 {
+		last := puntos[len(puntos)-1]
+		response.NextToken = models.PuntoCursor{Created: last.Updated, ID: last.ID}.Encode()
+	}
+
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, response)
 }
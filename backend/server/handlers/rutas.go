@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/kml"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/storage"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetPuntoRutas devuelve las rutas de acceso de un punto publicado como GeoJSON
+// (GET /api/puntos/{id}/rutas.geojson)
+func GetPuntoRutas(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	punto, err := database.GetPuntoByID(id)
+	if err != nil || punto.Estado != "publicado" {
+		http.Error(w, `{"error":"Punto not found"}`, http.StatusNotFound)
+		return
+	}
+
+	rutas, err := database.GetPuntoRutas(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Punto has no rutas"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error leyendo rutas de %s: %v", id, err)
+		http.Error(w, `{"error":"Error fetching rutas"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, models.GeoJSONContentType, publicCacheControl, rutas)
+}
+
+// UploadPuntoRutas recibe un KML o KMZ (multipart, campo "archivo"), lo valida,
+// guarda en store un KML regenerado con solo las geometrías (su URL queda en
+// archivo_kml; el original no se guarda) y reemplaza las rutas del punto
+// (POST /api/admin/puntos/{id}/rutas)
+func UploadPuntoRutas(store storage.Storage, maxBytes int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		punto := loadManagedPunto(w, r)
+		if punto == nil {
+			return
+		}
+
+		data, ok := readUpload(w, r, "archivo", maxBytes)
+		if !ok {
+			return
+		}
+
+		rutas, err := kml.Parse(data)
+		switch {
+		case errors.Is(err, kml.ErrFormato):
+			writeJSONError(w, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		case errors.Is(err, kml.ErrTooLarge):
+			writeJSONError(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		case err != nil:
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		clean, err := kml.Encode(rutas.GeoJSON)
+		if err != nil {
+			log.Printf("❌ Error generando KML de %s: %v", punto.ID, err)
+			http.Error(w, `{"error":"Error storing archivo"}`, http.StatusInternalServerError)
+			return
+		}
+
+		key := uploadName(punto.ID) + ".kml"
+		url, err := store.Put(r.Context(), key, kml.ContentTypeKML, clean)
+		if err != nil {
+			log.Printf("❌ Error guardando KML %s: %v", key, err)
+			http.Error(w, `{"error":"Error storing archivo"}`, http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := database.SetPuntoRutas(punto.ID, url, rutas.GeoJSON, middleware.GetUserID(r))
+		if err != nil {
+			deleteUploads(store, key)
+			log.Printf("❌ Error guardando rutas de %s: %v", punto.ID, err)
+			http.Error(w, `{"error":"Error saving rutas"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(models.RutasUploadResponse{
+			URL:        url,
+			Geometrias: rutas.Geometrias,
+			Vertices:   rutas.Vertices,
+			BBox:       rutas.GeoJSON.BBox,
+			Punto:      updated.ForRole(middleware.GetUserRole(r)),
+		})
+	}
+}
+
+// DeletePuntoRutas quita las rutas del punto y vacía archivo_kml
+// (DELETE /api/admin/puntos/{id}/rutas)
+func DeletePuntoRutas(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	updated, err := database.DeletePuntoRutas(punto.ID)
+	if err != nil {
+		log.Printf("❌ Error borrando rutas de %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error deleting rutas"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated.ForRole(middleware.GetUserRole(r)))
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxSubmissionBytes limita el tamaño de los envíos públicos
+const maxSubmissionBytes = 64 << 10
+
+// CreateSolicitud recibe un envío público (punto propuesto y/o mensaje) para moderación
+func CreateSolicitud(w http.ResponseWriter, r *http.Request) {
+	var req models.SolicitudCreateRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxSubmissionBytes)
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	saveSolicitud(w, req)
+}
+
+// SubmitPunto recibe un punto propuesto por el público (POST /api/puntos).
+// Queda pendiente hasta que un verificador lo apruebe.
+func SubmitPunto(w http.ResponseWriter, r *http.Request) {
+	var req models.PuntoSubmissionRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxSubmissionBytes)
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	saveSolicitud(w, req.ToSolicitud())
+}
+
+func saveSolicitud(w http.ResponseWriter, req models.SolicitudCreateRequest) {
+	if msg := req.Validate(); msg != "" {
+		writeJSONError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	// Un punto junto a otro de la misma categoría se acepta igual (en una emergencia
+	// es normal que varios vecinos reporten lo mismo); quien modera decide si fusionarlos
+	var duplicado string
+	if req.Punto != nil {
+		var err error
+		duplicado, err = database.FindDuplicatePunto(*req.Punto)
+		if err != nil {
+			log.Printf("❌ Error buscando duplicados: %v", err)
+			http.Error(w, `{"error":"Error saving submission"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	solicitud, err := database.CreateSolicitud(req, duplicado)
+	if err != nil {
+		log.Printf("❌ Error creando solicitud: %v", err)
+		http.Error(w, `{"error":"Error saving submission"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(models.SolicitudAcceptedResponse{
+		ID:      solicitud.ID,
+		Estado:  solicitud.Estado,
+		Message: "Submission received, it will be reviewed before being published",
+	})
+}
+
+// GetSolicitudes lista la cola de moderación (?estado=pendiente|aprobada|rechazada)
+func GetSolicitudes(w http.ResponseWriter, r *http.Request) {
+	estado := r.URL.Query().Get("estado")
+	if estado != "" && estado != models.SolicitudPendiente && estado != models.SolicitudAprobada && estado != models.SolicitudRechazada {
+		http.Error(w, `{"error":"Invalid estado value"}`, http.StatusBadRequest)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	response, err := database.GetSolicitudes(estado, page, limit)
+	if err != nil {
+		log.Printf("❌ Error listando solicitudes: %v", err)
+		http.Error(w, `{"error":"Error fetching solicitudes"}`, http.StatusInternalServerError)
+		return
+	}
+
+	rol := middleware.GetUserRole(r)
+	for i := range response.Data {
+		attachPunto(&response.Data[i], rol)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateSolicitudPunto corrige el punto de una solicitud pendiente antes de aprobarla
+func UpdateSolicitudPunto(w http.ResponseWriter, r *http.Request) {
+	solicitud, ok := pendingSolicitud(w, r)
+	if !ok {
+		return
+	}
+	if solicitud.PuntoID == "" {
+		http.Error(w, `{"error":"Solicitud has no punto"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req models.PuntoUpdateRequest
+	if !decodeValid(w, r, &req) || !validHorarioOSM(w, req.HorarioOSM) {
+		return
+	}
+	// El estado solo cambia al aprobar o rechazar
+	req.Estado = nil
+
+	if _, err := database.UpdatePunto(solicitud.PuntoID, req); err != nil {
+		http.Error(w, `{"error":"Error updating punto"}`, http.StatusInternalServerError)
+		return
+	}
+
+	attachPunto(solicitud, middleware.GetUserRole(r))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(solicitud)
+}
+
+// AprobarSolicitud publica el punto de la solicitud, aplicando antes las correcciones enviadas
+func AprobarSolicitud(w http.ResponseWriter, r *http.Request) {
+	solicitud, ok := pendingSolicitud(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.SolicitudAprobarRequest
+	if r.ContentLength != 0 && !decodeValid(w, r, &req) {
+		return
+	}
+	if req.Punto != nil && !validHorarioOSM(w, req.Punto.HorarioOSM) {
+		return
+	}
+
+	userID := middleware.GetUserID(r)
+	puntoID := solicitud.PuntoID
+
+	if puntoID != "" {
+		if req.Punto != nil {
+			req.Punto.Estado = nil
+			if _, err := database.UpdatePunto(puntoID, *req.Punto); err != nil {
+				http.Error(w, `{"error":"Error updating punto"}`, http.StatusInternalServerError)
+				return
+			}
+		}
+		if _, err := database.UpdatePuntoEstado(puntoID, "publicado", userID); err != nil {
+			http.Error(w, `{"error":"Error publishing punto"}`, http.StatusInternalServerError)
+			return
+		}
+	} else if req.PuntoID != "" {
+		// Mensaje libre a partir del cual el verificador creó un punto
+		if _, err := database.GetPuntoByID(req.PuntoID); err != nil {
+			http.Error(w, `{"error":"Punto not found"}`, http.StatusBadRequest)
+			return
+		}
+		puntoID = req.PuntoID
+	}
+
+	moderada, err := database.ModerarSolicitud(solicitud.ID, models.SolicitudAprobada, "", userID, puntoID)
+	if err != nil {
+		writeModeracionError(w, err)
+		return
+	}
+
+	attachPunto(moderada, middleware.GetUserRole(r))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(moderada)
+}
+
+// RechazarSolicitud descarta la solicitud con un motivo y oculta su punto
+func RechazarSolicitud(w http.ResponseWriter, r *http.Request) {
+	solicitud, ok := pendingSolicitud(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.SolicitudRechazarRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	req.Motivo = strings.TrimSpace(req.Motivo)
+	if req.Motivo == "" {
+		http.Error(w, `{"error":"motivo is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	if solicitud.PuntoID != "" {
+		if err := database.DeletePunto(solicitud.PuntoID); err != nil {
+			http.Error(w, `{"error":"Error hiding punto"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	moderada, err := database.ModerarSolicitud(solicitud.ID, models.SolicitudRechazada, req.Motivo, middleware.GetUserID(r), "")
+	if err != nil {
+		writeModeracionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(moderada)
+}
+
+// pendingSolicitud carga la solicitud {id} y verifica que siga pendiente
+func pendingSolicitud(w http.ResponseWriter, r *http.Request) (*models.Solicitud, bool) {
+	solicitud, err := database.GetSolicitudByID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error":"Solicitud not found"}`, http.StatusNotFound)
+		return nil, false
+	}
+	if solicitud.Estado != models.SolicitudPendiente {
+		http.Error(w, `{"error":"Solicitud already moderated"}`, http.StatusConflict)
+		return nil, false
+	}
+	return solicitud, true
+}
+
+func attachPunto(s *models.Solicitud, rol string) {
+	if s.PuntoID == "" {
+		return
+	}
+	if punto, err := database.GetPuntoByID(s.PuntoID); err == nil {
+		s.Punto = punto.ForRole(rol)
+	}
+}
+
+func writeModeracionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Solicitud already moderated"}`, http.StatusConflict)
+		return
+	}
+	log.Printf("❌ Error moderando solicitud: %v", err)
+	http.Error(w, `{"error":"Error moderating solicitud"}`, http.StatusInternalServerError)
+}
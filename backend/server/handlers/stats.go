@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/export"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// GetStats devuelve los conteos de los puntos publicados por comuna, categoría,
+// estado y nivel de urgencia. Acepta los filtros de GetPuntos; ?format=csv da una
+// fila por comuna.
+func GetStats(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePuntoFilter(r, defaultMaxLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Estado = "publicado"
+
+	writeStats(w, r, filter, publicCacheControl)
+}
+
+// GetAdminStats es GetStats sobre todos los estados (?estado= filtra uno); los
+// puntos ocultos no se cuentan
+func GetAdminStats(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePuntoFilter(r, defaultMaxLimit)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Estado = r.URL.Query().Get("estado")
+
+	writeStats(w, r, filter, privateCacheControl)
+}
+
+func writeStats(w http.ResponseWriter, r *http.Request, filter models.PuntoFilter, cacheControl string) {
+	stats, err := database.GetPuntoStats(filter)
+	if err != nil {
+		log.Printf("❌ Error en estadísticas: %v", err)
+		http.Error(w, `{"error":"Error fetching stats"}`, http.StatusInternalServerError)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		writeJSONWithETag(w, r, "application/json", cacheControl, stats)
+	case "csv":
+		filename := "estadisticas-" + time.Now().Format("20060102-1504") + ".csv"
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.Header().Set("Cache-Control", cacheControl)
+		if err := export.WriteStatsCSV(w, stats); err != nil {
+			log.Printf("❌ Error escribiendo estadísticas CSV: %v", err)
+		}
+	default:
+		http.Error(w, `{"error":"Invalid format, expected json or csv"}`, http.StatusBadRequest)
+	}
+}
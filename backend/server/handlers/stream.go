@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/events"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// streamHeartbeat mantiene viva la conexión a través de proxies (nginx corta a los 60s)
+const streamHeartbeat = 25 * time.Second
+
+// StreamPuntos envía por Server-Sent Events los cambios de puntos publicados.
+// Filtros opcionales: bbox y categoria. Eventos: created, updated (data = punto
+// público) y removed (data = {"id": ...}) cuando un punto publicado deja de estarlo.
+func StreamPuntos(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, `{"error":"Streaming not supported"}`, http.StatusInternalServerError)
+		return
+	}
+
+	var bbox *models.BBox
+	if raw := r.URL.Query().Get("bbox"); raw != "" {
+		var err error
+		if bbox, err = parseBBox(raw); err != nil {
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	categoria := r.URL.Query().Get("categoria")
+
+	sub := events.Subscribe()
+	defer events.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Desactiva el buffer de nginx
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+
+		case ev := <-sub.C:
+			eventType, data, ok := publicStreamEvent(ev, bbox, categoria)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data)
+			flusher.Flush()
+		}
+	}
+}
+
+// publicStreamEvent traduce un evento interno a lo que ve el público según los filtros
+func publicStreamEvent(ev events.Event, bbox *models.BBox, categoria string) (string, []byte, bool) {
+	p := ev.Punto
+
+	// Un punto que deja de estar publicado se informa como removed (sin datos
+	// privados). Los que nunca se publicaron (envíos en moderación) no se anuncian.
+	if ev.Type == events.PuntoRemoved || p.Estado != "publicado" {
+		if !p.FuePublicado {
+			return "", nil, false
+		}
+		data, _ := json.Marshal(map[string]string{"id": p.ID})
+		return events.PuntoRemoved, data, true
+	}
+
+	if categoria != "" && p.Categoria != categoria {
+		return "", nil, false
+	}
+	if bbox != nil && (p.Latitud < bbox.MinLat || p.Latitud > bbox.MaxLat ||
+		p.Longitud < bbox.MinLng || p.Longitud > bbox.MaxLng) {
+		return "", nil, false
+	}
+
+	data, err := json.Marshal(p.ToPublic())
+	if err != nil {
+		return "", nil, false
+	}
+	return ev.Type, data, true
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/geo"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/mvt"
+	"github.com/go-chi/chi/v5"
+)
+
+// tileBuffer es el margen (en unidades del extent) para no cortar íconos en el borde del tile
+const tileBuffer = 64
+
+// GetPuntosTile sirve los puntos publicados como Mapbox Vector Tile en la capa "puntos"
+func GetPuntosTile(w http.ResponseWriter, r *http.Request) {
+	z, errZ := strconv.Atoi(chi.URLParam(r, "z"))
+	x, errX := strconv.Atoi(chi.URLParam(r, "x"))
+	y, errY := strconv.Atoi(chi.URLParam(r, "y"))
+	if errZ != nil || errX != nil || errY != nil || z < 0 || z > maxZoom {
+		writeJSONError(w, "Invalid tile coordinates", http.StatusBadRequest)
+		return
+	}
+	if n := 1 << z; x < 0 || y < 0 || x >= n || y >= n {
+		writeJSONError(w, "Invalid tile coordinates", http.StatusBadRequest)
+		return
+	}
+
+	// Expandir el bbox con el buffer del tile
+	minLng, minLat, maxLng, maxLat := geo.TileBounds(z, x, y)
+	padLng := (maxLng - minLng) * tileBuffer / mvt.DefaultExtent
+	padLat := (maxLat - minLat) * tileBuffer / mvt.DefaultExtent
+
+	filter := models.PuntoFilter{
+		Categoria: r.URL.Query().Get("categoria"),
+		Subtipo:   r.URL.Query().Get("subtipo"),
+		Estado:    "publicado",
+		BBox: &models.BBox{
+			MinLng: minLng - padLng, MinLat: minLat - padLat,
+			MaxLng: maxLng + padLng, MaxLat: maxLat + padLat,
+		},
+	}
+
+	puntos, err := database.GetPuntoResumenes(filter)
+	if err != nil {
+		log.Printf("❌ Error en GetPuntosTile: %v", err)
+		http.Error(w, `{"error":"Error fetching tile"}`, http.StatusInternalServerError)
+		return
+	}
+
+	layer := mvt.NewLayer("puntos")
+	for _, p := range puntos {
+		px, py := geo.ToTile(p.Longitud, p.Latitud, z, x, y, layer.Extent)
+		layer.AddPoint(px, py, map[string]any{
+			"id":             p.ID,
+			"nombre":         p.Nombre,
+			"categoria":      p.Categoria,
+			"subtipo":        p.Subtipo,
+			"nivel_urgencia": p.NivelUrgencia,
+			"estado":         p.Estado,
+		})
+	}
+
+	w.Header().Set("Content-Type", mvt.ContentType)
+	// Cache corto: nginx lo guarda y absorbe el tráfico del mapa público
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.Write(mvt.Encode(layer))
+}
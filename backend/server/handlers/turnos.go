@@ -0,0 +1,577 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/ical"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultTurnosLimit = 200
+	maxTurnosLimit     = 1000
+
+	// icsHistorial mantiene en los calendarios los turnos de la última semana
+	icsHistorial = 7 * 24 * time.Hour
+)
+
+// parseTurnoFilter lee desde, hasta, libres y limit. Sin desde se listan los turnos
+// que aún no terminan.
+func parseTurnoFilter(r *http.Request) (models.TurnoFilter, error) {
+	q := r.URL.Query()
+	f := models.TurnoFilter{
+		Ciudad: q.Get("ciudad"),
+		Rol:    strings.TrimSpace(q.Get("rol")),
+		Desde:  time.Now(),
+	}
+
+	var err error
+	if f.Limit, err = parseLimit(r, defaultTurnosLimit, maxTurnosLimit); err != nil {
+		return f, err
+	}
+	if raw := q.Get("desde"); raw != "" {
+		if f.Desde, err = parseOpenAt(raw); err != nil {
+			return f, errors.New("Invalid desde, expected RFC3339 or YYYY-MM-DDTHH:MM")
+		}
+	}
+	if raw := q.Get("hasta"); raw != "" {
+		if f.Hasta, err = parseOpenAt(raw); err != nil {
+			return f, errors.New("Invalid hasta, expected RFC3339 or YYYY-MM-DDTHH:MM")
+		}
+	}
+	if raw := q.Get("libres"); raw != "" {
+		if f.SoloLibres, err = strconv.ParseBool(raw); err != nil {
+			return f, errors.New("Invalid libres, expected true or false")
+		}
+	}
+	return f, nil
+}
+
+// parseTurnoHorario valida inicio y fin de un turno
+func parseTurnoHorario(rawInicio, rawFin string) (inicio, fin time.Time, err error) {
+	if inicio, err = parseOpenAt(rawInicio); err != nil {
+		return inicio, fin, errors.New("Invalid inicio, expected RFC3339 or YYYY-MM-DDTHH:MM")
+	}
+	if fin, err = parseOpenAt(rawFin); err != nil {
+		return inicio, fin, errors.New("Invalid fin, expected RFC3339 or YYYY-MM-DDTHH:MM")
+	}
+	return inicio, fin, validTurnoHorario(inicio, fin)
+}
+
+func validTurnoHorario(inicio, fin time.Time) error {
+	if !fin.After(inicio) {
+		return errors.New("fin must be after inicio")
+	}
+	if fin.Sub(inicio) > models.MaxDuracionTurno {
+		return fmt.Errorf("A turno can last at most %d hours", int(models.MaxDuracionTurno.Hours()))
+	}
+	return nil
+}
+
+// GetPuntoTurnos lista los turnos de un punto publicado (GET /api/puntos/{id}/turnos)
+func GetPuntoTurnos(w http.ResponseWriter, r *http.Request) {
+	f, err := parseTurnoFilter(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	punto, err := database.GetPuntoByID(id)
+	if err != nil || punto.Estado != "publicado" {
+		http.Error(w, `{"error":"Punto not found"}`, http.StatusNotFound)
+		return
+	}
+
+	f.PuntoID = punto.ID
+	f.Publicados = true
+	turnos, err := database.GetTurnos(f)
+	if err != nil {
+		log.Printf("❌ Error listando turnos de %s: %v", id, err)
+		http.Error(w, `{"error":"Error fetching turnos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, models.TurnosResponse{Data: turnos})
+}
+
+// GetPuntoTurnosICS es el calendario de turnos de un punto publicado
+// (GET /api/puntos/{id}/turnos.ics)
+func GetPuntoTurnosICS(w http.ResponseWriter, r *http.Request) {
+	punto, err := database.GetPuntoByID(chi.URLParam(r, "id"))
+	if err != nil || punto.Estado != "publicado" {
+		http.Error(w, `{"error":"Punto not found"}`, http.StatusNotFound)
+		return
+	}
+
+	turnos, err := database.GetTurnos(models.TurnoFilter{
+		PuntoID:    punto.ID,
+		Desde:      time.Now().Add(-icsHistorial),
+		Publicados: true,
+		Limit:      maxTurnosLimit,
+	})
+	if err != nil {
+		log.Printf("❌ Error listando turnos de %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error fetching turnos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeTurnosICS(w, "Turnos "+punto.Nombre, turnos, publicCacheControl)
+}
+
+// SearchTurnos lista turnos entre todos los puntos publicados (GET /api/turnos),
+// p. ej. los turnos de cocina con cupos libres en una ciudad
+func SearchTurnos(w http.ResponseWriter, r *http.Request) {
+	f, err := parseTurnoFilter(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.Publicados = true
+
+	turnos, err := database.GetTurnos(f)
+	if err != nil {
+		log.Printf("❌ Error buscando turnos: %v", err)
+		http.Error(w, `{"error":"Error fetching turnos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", publicCacheControl, models.TurnosResponse{Data: turnos})
+}
+
+// voluntarioFromToken identifica al voluntario por su token (header X-Voluntario-Token
+// o ?token= para las suscripciones de calendario). Si no, responde y devuelve nil.
+func voluntarioFromToken(w http.ResponseWriter, r *http.Request) *models.Voluntario {
+	token := strings.TrimSpace(r.Header.Get(middleware.VoluntarioTokenHeader))
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		http.Error(w, `{"error":"Voluntario token required"}`, http.StatusUnauthorized)
+		return nil
+	}
+
+	voluntario, err := database.GetVoluntarioByToken(token)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Invalid voluntario token"}`, http.StatusForbidden)
+		return nil
+	}
+	if err != nil {
+		log.Printf("❌ Error buscando voluntario por token: %v", err)
+		http.Error(w, `{"error":"Error fetching voluntario"}`, http.StatusInternalServerError)
+		return nil
+	}
+	if !voluntario.Activo {
+		http.Error(w, `{"error":"Voluntario is not active"}`, http.StatusForbidden)
+		return nil
+	}
+	return voluntario
+}
+
+// GetMisTurnos lista los turnos que tomó el voluntario (GET /api/voluntarios/me/turnos)
+func GetMisTurnos(w http.ResponseWriter, r *http.Request) {
+	f, err := parseTurnoFilter(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	voluntario := voluntarioFromToken(w, r)
+	if voluntario == nil {
+		return
+	}
+
+	f.VoluntarioID = voluntario.ID
+	turnos, err := database.GetTurnos(f)
+	if err != nil {
+		log.Printf("❌ Error listando turnos de %s: %v", voluntario.ID, err)
+		http.Error(w, `{"error":"Error fetching turnos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl, models.TurnosResponse{Data: turnos})
+}
+
+// GetMisTurnosICS es el calendario personal del voluntario
+// (GET /api/voluntarios/me/turnos.ics?token=...)
+func GetMisTurnosICS(w http.ResponseWriter, r *http.Request) {
+	voluntario := voluntarioFromToken(w, r)
+	if voluntario == nil {
+		return
+	}
+
+	turnos, err := database.GetTurnos(models.TurnoFilter{
+		VoluntarioID: voluntario.ID,
+		Desde:        time.Now().Add(-icsHistorial),
+		Limit:        maxTurnosLimit,
+	})
+	if err != nil {
+		log.Printf("❌ Error listando turnos de %s: %v", voluntario.ID, err)
+		http.Error(w, `{"error":"Error fetching turnos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeTurnosICS(w, "Mis turnos Donde Ayudo", turnos, privateCacheControl)
+}
+
+// TomarTurno inscribe al voluntario del token en el turno (POST /api/turnos/{id}/tomar)
+func TomarTurno(w http.ResponseWriter, r *http.Request) {
+	voluntario := voluntarioFromToken(w, r)
+	if voluntario == nil {
+		return
+	}
+
+	turno, err := database.TomarTurno(chi.URLParam(r, "id"), voluntario.ID)
+	if err != nil {
+		writeTurnoError(w, err, "claiming")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(turno)
+}
+
+// SoltarTurno libera el cupo del voluntario del token (POST /api/turnos/{id}/soltar)
+func SoltarTurno(w http.ResponseWriter, r *http.Request) {
+	voluntario := voluntarioFromToken(w, r)
+	if voluntario == nil {
+		return
+	}
+
+	turno, err := database.SoltarTurno(chi.URLParam(r, "id"), voluntario.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Voluntario is not in this turno"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeTurnoError(w, err, "releasing")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(turno)
+}
+
+// writeTurnoError traduce los errores de tomar un turno a su código HTTP
+func writeTurnoError(w http.ResponseWriter, err error, action string) {
+	var conflicto *database.TurnoConflictoError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, `{"error":"Turno not found"}`, http.StatusNotFound)
+	case errors.As(err, &conflicto):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.TurnoConflictoResponse{
+			Error:   "Voluntario already has an overlapping turno",
+			TurnoID: conflicto.TurnoID,
+		})
+	case errors.Is(err, database.ErrTurnoLleno):
+		writeJSONError(w, "Turno is full", http.StatusConflict)
+	case errors.Is(err, database.ErrTurnoTerminado):
+		writeJSONError(w, "Turno already ended", http.StatusConflict)
+	default:
+		log.Printf("❌ Error %s turno: %v", action, err)
+		writeJSONError(w, "Error "+action+" turno", http.StatusInternalServerError)
+	}
+}
+
+// writeTurnosICS responde los turnos como calendario iCalendar
+func writeTurnosICS(w http.ResponseWriter, name string, turnos []models.Turno, cacheControl string) {
+	// DTSTAMP es la hora de generación: los clientes vuelven a leer todo el feed
+	now := time.Now()
+	events := make([]ical.Event, len(turnos))
+	for i, t := range turnos {
+		description := fmt.Sprintf("Cupos: %d de %d", t.Ocupados, t.Cupos)
+		if t.Nota != "" {
+			description = t.Nota + "\n" + description
+		}
+		location := t.PuntoNombre
+		if t.Direccion != "" {
+			location += ", " + t.Direccion
+		}
+		if t.Ciudad != "" {
+			location += ", " + t.Ciudad
+		}
+		events[i] = ical.Event{
+			UID:         t.ID + "@dondeayudo.cl",
+			Start:       t.Inicio,
+			End:         t.Fin,
+			Stamp:       now,
+			Summary:     t.Rol + " · " + t.PuntoNombre,
+			Description: description,
+			Location:    location,
+			Lat:         t.Latitud,
+			Lng:         t.Longitud,
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", cacheControl)
+	if err := ical.Write(w, name, events); err != nil {
+		log.Printf("❌ Error escribiendo calendario: %v", err)
+	}
+}
+
+// loadManagedTurno busca un turno del punto; responde 404 si es de otro punto
+func loadManagedTurno(w http.ResponseWriter, punto *models.Punto, id string) *models.Turno {
+	turno, err := database.GetTurnoByID(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && turno.PuntoID != punto.ID) {
+		http.Error(w, `{"error":"Turno not found"}`, http.StatusNotFound)
+		return nil
+	}
+	if err != nil {
+		log.Printf("❌ Error buscando turno %s: %v", id, err)
+		http.Error(w, `{"error":"Error fetching turno"}`, http.StatusInternalServerError)
+		return nil
+	}
+	return turno
+}
+
+// GetAdminTurnos es la planilla del punto: sus turnos con quiénes los tomaron
+// (GET /api/admin/puntos/{id}/turnos)
+func GetAdminTurnos(w http.ResponseWriter, r *http.Request) {
+	f, err := parseTurnoFilter(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	f.PuntoID = punto.ID
+	turnos, err := database.GetTurnos(f)
+	if err == nil {
+		err = database.AttachInscritos(turnos)
+	}
+	if err != nil {
+		log.Printf("❌ Error listando turnos de %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error fetching turnos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl, models.TurnosResponse{Data: turnos})
+}
+
+// CreateTurnos crea uno o varios turnos seguidos en el punto
+func CreateTurnos(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	var req models.TurnoCreateRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Rol) == "" {
+		writeJSONError(w, "rol is required", http.StatusBadRequest)
+		return
+	}
+	inicio, fin, err := parseTurnoHorario(req.Inicio, req.Fin)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	turnos, err := database.CreateTurnos(punto.ID, inicio, fin, req, middleware.GetUserID(r))
+	if err != nil {
+		log.Printf("❌ Error creando turnos en %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error creating turnos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.TurnosResponse{Data: turnos})
+}
+
+// UpdateTurno cambia rol, cupos, nota u horario de un turno
+func UpdateTurno(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	var req models.TurnoUpdateRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	if req.Rol != nil && strings.TrimSpace(*req.Rol) == "" {
+		writeJSONError(w, "rol cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	actual := loadManagedTurno(w, punto, chi.URLParam(r, "turnoId"))
+	if actual == nil {
+		return
+	}
+
+	var inicio, fin *time.Time
+	if req.Inicio != nil {
+		t, err := parseOpenAt(*req.Inicio)
+		if err != nil {
+			writeJSONError(w, "Invalid inicio, expected RFC3339 or YYYY-MM-DDTHH:MM", http.StatusBadRequest)
+			return
+		}
+		inicio = &t
+	}
+	if req.Fin != nil {
+		t, err := parseOpenAt(*req.Fin)
+		if err != nil {
+			writeJSONError(w, "Invalid fin, expected RFC3339 or YYYY-MM-DDTHH:MM", http.StatusBadRequest)
+			return
+		}
+		fin = &t
+	}
+	if inicio != nil || fin != nil {
+		desde, hasta := actual.Inicio, actual.Fin
+		if inicio != nil {
+			desde = *inicio
+		}
+		if fin != nil {
+			hasta = *fin
+		}
+		if err := validTurnoHorario(desde, hasta); err != nil {
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	turno, err := database.UpdateTurno(punto.ID, actual.ID, inicio, fin, req)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, `{"error":"Turno not found"}`, http.StatusNotFound)
+		return
+	case errors.Is(err, database.ErrTurnoConInscritos):
+		writeJSONError(w, "Turno already has voluntarios, release them before moving it", http.StatusConflict)
+		return
+	case errors.Is(err, database.ErrCuposOcupados):
+		writeJSONError(w, "cupos cannot be lower than the voluntarios already in the turno", http.StatusConflict)
+		return
+	case err != nil:
+		log.Printf("❌ Error actualizando turno: %v", err)
+		http.Error(w, `{"error":"Error updating turno"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(turno)
+}
+
+// DeleteTurno borra el turno y sus inscripciones
+func DeleteTurno(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	err := database.DeleteTurno(punto.ID, chi.URLParam(r, "turnoId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Turno not found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error borrando turno: %v", err)
+		http.Error(w, `{"error":"Error deleting turno"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"message":"Turno deleted successfully"}`))
+}
+
+// AssignTurnoVoluntario inscribe a un voluntario en el turno en su nombre, p. ej. si
+// lo pidió por teléfono. Aplica los mismos cupos y cruces que tomarlo.
+// (PUT /api/admin/puntos/{id}/turnos/{turnoId}/voluntarios/{voluntarioId})
+func AssignTurnoVoluntario(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+	actual := loadManagedTurno(w, punto, chi.URLParam(r, "turnoId"))
+	if actual == nil {
+		return
+	}
+
+	voluntario, err := database.GetVoluntarioByID(chi.URLParam(r, "voluntarioId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Voluntario not found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error buscando voluntario: %v", err)
+		http.Error(w, `{"error":"Error assigning voluntario"}`, http.StatusInternalServerError)
+		return
+	}
+	if !voluntario.Activo {
+		writeJSONError(w, "Voluntario is not active", http.StatusConflict)
+		return
+	}
+
+	turno, err := database.TomarTurno(actual.ID, voluntario.ID)
+	if err != nil {
+		writeTurnoError(w, err, "assigning")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(turno)
+}
+
+// RemoveTurnoVoluntario saca a un voluntario del turno
+func RemoveTurnoVoluntario(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+	actual := loadManagedTurno(w, punto, chi.URLParam(r, "turnoId"))
+	if actual == nil {
+		return
+	}
+
+	turno, err := database.SoltarTurno(actual.ID, chi.URLParam(r, "voluntarioId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Voluntario is not in this turno"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeTurnoError(w, err, "removing")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(turno)
+}
+
+// ResetVoluntarioToken genera un token nuevo para el voluntario e invalida el anterior
+// (POST /api/admin/voluntarios/{id}/token)
+func ResetVoluntarioToken(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	token, err := database.ResetVoluntarioToken(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Voluntario not found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error generando token de %s: %v", id, err)
+		http.Error(w, `{"error":"Error generating token"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.VoluntarioTokenResponse{ID: id, Token: token})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/horario"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/storage"
+)
+
+// multipartOverhead es el margen sobre el tamaño máximo del archivo para los
+// boundaries y el resto de los campos del formulario
+const multipartOverhead = 64 << 10
+
+// readUpload lee el archivo field de un formulario multipart de hasta maxBytes;
+// los demás campos quedan en r.FormValue. Si falla responde el error y devuelve false.
+func readUpload(w http.ResponseWriter, r *http.Request, field string, maxBytes int64) ([]byte, bool) {
+	tooLarge := fmt.Sprintf("File too large, max %d MB", maxBytes>>20)
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+multipartOverhead)
+	if err := r.ParseMultipartForm(maxBytes + multipartOverhead); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeJSONError(w, tooLarge, http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+		writeJSONError(w, "Expected multipart/form-data", http.StatusBadRequest)
+		return nil, false
+	}
+	// Los archivos grandes quedan en temporales; ya leídos no se necesitan
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		writeJSONError(w, field+" is required", http.StatusBadRequest)
+		return nil, false
+	}
+	defer file.Close()
+	if header.Size > maxBytes {
+		writeJSONError(w, tooLarge, http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeJSONError(w, "Error reading "+field, http.StatusBadRequest)
+		return nil, false
+	}
+	return data, true
+}
+
+// uploadName arma la key de un archivo del punto sin extensión:
+// puntos/{id}/{yyyymmdd}-{aleatorio}
+func uploadName(puntoID string) string {
+	suffix := make([]byte, 8)
+	rand.Read(suffix)
+	return "puntos/" + puntoID + "/" + time.Now().In(horario.Santiago).Format("20060102") + "-" + hex.EncodeToString(suffix)
+}
+
+// deleteUploads borra archivos ya subidos cuando no alcanzaron a guardarse en el punto
+func deleteUploads(store storage.Storage, keys ...string) {
+	for _, key := range keys {
+		if err := store.Delete(context.Background(), key); err != nil {
+			log.Printf("⚠️ No se pudo borrar %s: %v", key, err)
+		}
+	}
+}
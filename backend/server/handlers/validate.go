@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/horario"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/openapi"
+)
+
+// apiSchemas genera los schemas del documento OpenAPI y valida los cuerpos contra ellos
+var apiSchemas = openapi.NewRegistry()
+
+// maxBodyBytes limita los cuerpos JSON de la API
+const maxBodyBytes = 1 << 20
+
+// decodeValid valida el cuerpo contra el schema de dst y lo decodifica. Si falla,
+// responde 400 con los errores por campo y devuelve false.
+func decodeValid(w http.ResponseWriter, r *http.Request, dst any) bool {
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return false
+	}
+
+	if errs := apiSchemas.Validate(apiSchemas.SchemaOf(dst), raw); len(errs) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(openapi.ValidationError{Error: "Validation failed", Fields: errs})
+		return false
+	}
+
+	if err := json.Unmarshal(raw, dst); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// validHorarioOSM revisa la sintaxis de horario_osm y la deja en forma canónica.
+// Si no se entiende responde 400 con el mismo formato de decodeValid.
+func validHorarioOSM(w http.ResponseWriter, value *string) bool {
+	return validOpeningHours(w, "horario_osm", value)
+}
+
+// validOpeningHours es validHorarioOSM para cualquier campo en formato opening_hours
+func validOpeningHours(w http.ResponseWriter, field string, value *string) bool {
+	if value == nil || *value == "" {
+		return true
+	}
+
+	sch, err := horario.Parse(*value)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(openapi.ValidationError{
+			Error:  "Validation failed",
+			Fields: []openapi.FieldError{{Field: field, Message: err.Error()}},
+		})
+		return false
+	}
+	*value = sch.String()
+	return true
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/horario"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/matching"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultVoluntariosLimit = 100
+	maxVoluntariosLimit     = 500
+)
+
+// CreateVoluntario inscribe a un voluntario desde el formulario público (POST /api/voluntarios)
+func CreateVoluntario(w http.ResponseWriter, r *http.Request) {
+	var req models.VoluntarioCreateRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxSubmissionBytes)
+	if !decodeValid(w, r, &req) || !validOpeningHours(w, "disponibilidad_osm", &req.DisponibilidadOSM) {
+		return
+	}
+	if strings.TrimSpace(req.Nombre) == "" || strings.TrimSpace(req.Ciudad) == "" {
+		writeJSONError(w, "nombre and ciudad are required", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Telefono) == "" && strings.TrimSpace(req.Email) == "" {
+		writeJSONError(w, "telefono or email is required", http.StatusBadRequest)
+		return
+	}
+
+	voluntario, token, err := database.CreateVoluntario(req)
+	if err != nil {
+		log.Printf("❌ Error inscribiendo voluntario: %v", err)
+		http.Error(w, `{"error":"Error saving voluntario"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.VoluntarioAcceptedResponse{
+		ID:      voluntario.ID,
+		Token:   token,
+		Message: "Thanks for signing up, a coordinator will contact you",
+	})
+}
+
+// parseVoluntarioFilter lee los filtros comunes del listado y de las sugerencias
+func parseVoluntarioFilter(r *http.Request) (models.VoluntarioFilter, error) {
+	q := r.URL.Query()
+
+	f := models.VoluntarioFilter{
+		Ciudad:           q.Get("ciudad"),
+		Habilidad:        matching.Normalize(q.Get("habilidad")),
+		Vehiculos:        parseList(q.Get("vehiculo")),
+		DisponibleMinuto: -1,
+	}
+
+	var err error
+	if f.Limit, err = parseLimit(r, defaultVoluntariosLimit, maxVoluntariosLimit); err != nil {
+		return f, err
+	}
+
+	if raw := q.Get("activo"); raw != "" {
+		activo, err := strconv.ParseBool(raw)
+		if err != nil {
+			return f, errors.New("Invalid activo, expected true or false")
+		}
+		f.Activo = &activo
+	}
+	if raw := q.Get("libres"); raw != "" {
+		if f.Libres, err = strconv.ParseBool(raw); err != nil {
+			return f, errors.New("Invalid libres, expected true or false")
+		}
+	}
+
+	var at *time.Time
+	if raw := q.Get("disponible_ahora"); raw != "" {
+		ahora, err := strconv.ParseBool(raw)
+		if err != nil {
+			return f, errors.New("Invalid disponible_ahora, expected true or false")
+		}
+		if ahora {
+			now := time.Now()
+			at = &now
+		}
+	}
+	if raw := q.Get("disponible_at"); raw != "" {
+		if at != nil {
+			return f, errors.New("Use disponible_ahora or disponible_at, not both")
+		}
+		t, err := parseOpenAt(raw)
+		if err != nil {
+			return f, errors.New("Invalid disponible_at, expected RFC3339 or YYYY-MM-DDTHH:MM (Chile local time)")
+		}
+		at = &t
+	}
+	if at != nil {
+		f.DisponibleMinuto = horario.MinuteOfWeek(*at)
+	}
+
+	return f, nil
+}
+
+// GetVoluntarios lista los voluntarios inscritos (GET /api/admin/voluntarios)
+func GetVoluntarios(w http.ResponseWriter, r *http.Request) {
+	f, err := parseVoluntarioFilter(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, v := range f.Vehiculos {
+		if !models.IsVehiculo(v) {
+			writeJSONError(w, "Invalid vehiculo, expected "+strings.Join(models.Vehiculos, ", "), http.StatusBadRequest)
+			return
+		}
+	}
+
+	voluntarios, err := database.GetVoluntarios(f)
+	if err != nil {
+		log.Printf("❌ Error listando voluntarios: %v", err)
+		http.Error(w, `{"error":"Error fetching voluntarios"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl,
+		models.VoluntariosResponse{Data: voluntarios, Limit: f.Limit})
+}
+
+func GetVoluntario(w http.ResponseWriter, r *http.Request) {
+	voluntario, err := database.GetVoluntarioByID(chi.URLParam(r, "id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Voluntario not found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error buscando voluntario: %v", err)
+		http.Error(w, `{"error":"Error fetching voluntario"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voluntario)
+}
+
+// UpdateVoluntario corrige la ficha, o la desactiva con activo=false
+func UpdateVoluntario(w http.ResponseWriter, r *http.Request) {
+	var req models.VoluntarioUpdateRequest
+	if !decodeValid(w, r, &req) || !validOpeningHours(w, "disponibilidad_osm", req.DisponibilidadOSM) {
+		return
+	}
+
+	voluntario, err := database.UpdateVoluntario(chi.URLParam(r, "id"), req)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Voluntario not found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error actualizando voluntario: %v", err)
+		http.Error(w, `{"error":"Error updating voluntario"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voluntario)
+}
+
+// DeleteVoluntario borra la ficha y sus asignaciones
+func DeleteVoluntario(w http.ResponseWriter, r *http.Request) {
+	err := database.DeleteVoluntario(chi.URLParam(r, "id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Voluntario not found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error borrando voluntario: %v", err)
+		http.Error(w, `{"error":"Error deleting voluntario"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"message":"Voluntario deleted successfully"}`))
+}
+
+// GetPuntosNecesitanVoluntarios lista los puntos que piden voluntarios con cuántos
+// tienen asignados, los que más faltan primero (GET /api/admin/voluntarios/puntos)
+func GetPuntosNecesitanVoluntarios(w http.ResponseWriter, r *http.Request) {
+	puntos, err := database.GetPuntosNecesitanVoluntarios(r.URL.Query().Get("ciudad"))
+	if err != nil {
+		log.Printf("❌ Error listando puntos que piden voluntarios: %v", err)
+		http.Error(w, `{"error":"Error fetching puntos"}`, http.StatusInternalServerError)
+		return
+	}
+
+	data := make([]models.PuntoVoluntarios, len(puntos))
+	for i := range puntos {
+		data[i] = models.NewPuntoVoluntarios(&puntos[i])
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl, models.PuntosVoluntariosResponse{Data: data})
+}
+
+// GetPuntoVoluntarios devuelve el cupo del punto y los voluntarios asignados
+func GetPuntoVoluntarios(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	voluntarios, err := database.GetPuntoVoluntarios(punto.ID)
+	if err != nil {
+		log.Printf("❌ Error listando voluntarios de %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error fetching voluntarios"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl, models.PuntoVoluntariosResponse{
+		PuntoVoluntarios: models.NewPuntoVoluntarios(punto),
+		Data:             voluntarios,
+	})
+}
+
+// GetVoluntariosSugeridos propone voluntarios activos para un punto: de su ciudad,
+// con un vehículo que llega según tipos_acceso y que aún no están asignados a él.
+// Acepta los mismos filtros que el listado (habilidad, libres, disponible_ahora...).
+func GetVoluntariosSugeridos(w http.ResponseWriter, r *http.Request) {
+	f, err := parseVoluntarioFilter(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	activo := true
+	f.Activo = &activo
+	f.Excluir = punto.ID
+	if f.Ciudad == "" {
+		f.Ciudad = punto.Ciudad
+	}
+	f.Vehiculos = []string{}
+	for _, v := range models.Vehiculos {
+		if models.PuedeLlegar(v, punto.TiposAcceso) {
+			f.Vehiculos = append(f.Vehiculos, v)
+		}
+	}
+
+	voluntarios, err := database.GetVoluntarios(f)
+	if err != nil {
+		log.Printf("❌ Error sugiriendo voluntarios para %s: %v", punto.ID, err)
+		http.Error(w, `{"error":"Error fetching voluntarios"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONWithETag(w, r, "application/json", privateCacheControl,
+		models.VoluntariosResponse{Data: voluntarios, Limit: f.Limit})
+}
+
+// AssignVoluntario asigna un voluntario activo al punto y devuelve el nuevo conteo
+// (PUT /api/admin/puntos/{id}/voluntarios/{voluntarioId})
+func AssignVoluntario(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	voluntario, err := database.GetVoluntarioByID(chi.URLParam(r, "voluntarioId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Voluntario not found"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error buscando voluntario: %v", err)
+		http.Error(w, `{"error":"Error assigning voluntario"}`, http.StatusInternalServerError)
+		return
+	}
+	if !voluntario.Activo {
+		writeJSONError(w, "Voluntario is not active", http.StatusConflict)
+		return
+	}
+
+	updated, err := database.AssignVoluntario(punto.ID, voluntario.ID, middleware.GetUserID(r))
+	if err != nil {
+		log.Printf("❌ Error asignando voluntario: %v", err)
+		http.Error(w, `{"error":"Error assigning voluntario"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewPuntoVoluntarios(updated))
+}
+
+// RemoveVoluntario quita un voluntario del punto y devuelve el nuevo conteo
+func RemoveVoluntario(w http.ResponseWriter, r *http.Request) {
+	punto := loadManagedPunto(w, r)
+	if punto == nil {
+		return
+	}
+
+	updated, err := database.RemoveVoluntario(punto.ID, chi.URLParam(r, "voluntarioId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, `{"error":"Voluntario is not assigned to this punto"}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error quitando voluntario: %v", err)
+		http.Error(w, `{"error":"Error removing voluntario"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NewPuntoVoluntarios(updated))
+}
@@ -0,0 +1,334 @@
+// Package horario interpreta horarios de atención en el formato opening_hours de
+// OpenStreetMap (el subconjunto semanal: "Mo-Fr 09:00-18:00; Sa 10:00-14:00",
+// "24/7", "Su off") y los evalúa en la hora de Chile continental.
+package horario
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // La imagen Docker (alpine) no trae la base de zonas horarias
+)
+
+const (
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+)
+
+// Santiago es la zona horaria en que se evalúan los horarios
+var Santiago = mustLoadLocation("America/Santiago")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+var (
+	ErrEmpty       = errors.New("empty opening hours")
+	ErrUnsupported = errors.New("unsupported opening hours syntax")
+)
+
+// dayCodes en el orden de la semana ISO (lunes = 0)
+var dayCodes = []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}
+
+// span es un tramo de un día en minutos desde la medianoche; end puede pasar de
+// 1440 si el tramo cruza la medianoche (22:00-02:00)
+type span struct{ start, end int }
+
+// Schedule es un horario semanal: los tramos de cada día, lunes primero
+type Schedule struct {
+	days [7][]span
+}
+
+// Parse interpreta un horario opening_hours. Las reglas se aplican en orden y una
+// regla reemplaza lo que las anteriores decían de sus días, como en OSM. Las
+// reglas de feriados (PH) se ignoran.
+func Parse(s string) (Schedule, error) {
+	var sch Schedule
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return sch, ErrEmpty
+	}
+
+	for _, rule := range strings.Split(s, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if rule == "24/7" {
+			for d := range sch.days {
+				sch.days[d] = []span{{0, minutesPerDay}}
+			}
+			continue
+		}
+
+		fields := strings.Fields(rule)
+		days, rest, err := parseDays(fields)
+		if err != nil {
+			return sch, err
+		}
+		if days == nil {
+			// Regla solo de feriados
+			continue
+		}
+
+		spans, err := parseSpans(rest)
+		if err != nil {
+			return sch, err
+		}
+		for _, d := range days {
+			sch.days[d] = spans
+		}
+	}
+
+	if sch.IsZero() && !strings.Contains(s, "off") && !strings.Contains(s, "closed") {
+		return sch, ErrEmpty
+	}
+	return sch, nil
+}
+
+// parseDays lee el selector de días al comienzo de la regla ("Mo-Fr,Su"). Sin
+// selector la regla aplica a todos los días. Devuelve nil si solo nombra feriados.
+func parseDays(fields []string) ([]int, []string, error) {
+	if len(fields) == 0 || !isDaySelector(fields[0]) {
+		return []int{0, 1, 2, 3, 4, 5, 6}, fields, nil
+	}
+
+	days := []int{}
+	for _, item := range strings.Split(fields[0], ",") {
+		if item == "PH" || item == "SH" {
+			continue
+		}
+		from, to, isRange := strings.Cut(item, "-")
+		a := dayIndex(from)
+		b := a
+		if isRange {
+			b = dayIndex(to)
+		}
+		if a < 0 || b < 0 {
+			return nil, nil, fmt.Errorf("%w: day %q", ErrUnsupported, item)
+		}
+		// Los rangos pueden dar la vuelta a la semana (Fr-Mo)
+		for d := a; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == b {
+				break
+			}
+		}
+	}
+	if len(days) == 0 {
+		return nil, fields[1:], nil
+	}
+	return days, fields[1:], nil
+}
+
+func isDaySelector(field string) bool {
+	if len(field) < 2 {
+		return false
+	}
+	prefix := field[:2]
+	return dayIndex(prefix) >= 0 || prefix == "PH" || prefix == "SH"
+}
+
+func dayIndex(code string) int {
+	for i, c := range dayCodes {
+		if c == code {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseSpans lee "09:00-13:00,15:00-19:00", "off" o nada (todo el día)
+func parseSpans(fields []string) ([]span, error) {
+	switch {
+	case len(fields) == 0:
+		return []span{{0, minutesPerDay}}, nil
+	case len(fields) == 1 && (fields[0] == "off" || fields[0] == "closed"):
+		return nil, nil
+	case len(fields) > 1:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupported, strings.Join(fields, " "))
+	}
+
+	var spans []span
+	for _, item := range strings.Split(fields[0], ",") {
+		from, to, ok := strings.Cut(item, "-")
+		if !ok {
+			return nil, fmt.Errorf("%w: time range %q", ErrUnsupported, item)
+		}
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, err
+		}
+		if start == minutesPerDay {
+			return nil, fmt.Errorf("%w: time range %q", ErrUnsupported, item)
+		}
+		if end <= start {
+			end += minutesPerDay
+		}
+		spans = append(spans, span{start, end})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	return spans, nil
+}
+
+// parseClock lee HH:MM (hasta 24:00)
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(s, ":")
+	hour, err1 := strconv.Atoi(h)
+	minute, err2 := strconv.Atoi(m)
+	if !ok || len(m) != 2 || err1 != nil || err2 != nil ||
+		hour < 0 || minute < 0 || minute > 59 || hour*60+minute > minutesPerDay {
+		return 0, fmt.Errorf("%w: time %q", ErrUnsupported, s)
+	}
+	return hour*60 + minute, nil
+}
+
+// IsZero indica si el horario no abre ningún día
+func (s Schedule) IsZero() bool {
+	for _, spans := range s.days {
+		if len(spans) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Intervals devuelve los tramos abiertos de la semana como [inicio, fin) en minutos
+// desde el lunes 00:00, ordenados y sin solapes. Un tramo que cruza la medianoche
+// del domingo se parte en dos.
+func (s Schedule) Intervals() [][2]int {
+	var raw [][2]int
+	for d, spans := range s.days {
+		for _, sp := range spans {
+			start, end := d*minutesPerDay+sp.start, d*minutesPerDay+sp.end
+			if end > minutesPerWeek {
+				raw = append(raw, [2]int{0, end - minutesPerWeek})
+				end = minutesPerWeek
+			}
+			raw = append(raw, [2]int{start, end})
+		}
+	}
+	sort.Slice(raw, func(i, j int) bool { return raw[i][0] < raw[j][0] })
+
+	out := [][2]int{}
+	for _, iv := range raw {
+		if n := len(out); n > 0 && iv[0] <= out[n-1][1] {
+			if iv[1] > out[n-1][1] {
+				out[n-1][1] = iv[1]
+			}
+			continue
+		}
+		out = append(out, iv)
+	}
+	return out
+}
+
+// MinuteOfWeek es el minuto de la semana (lunes 00:00 = 0) de t en hora de Santiago
+func MinuteOfWeek(t time.Time) int {
+	t = t.In(Santiago)
+	weekday := (int(t.Weekday()) + 6) % 7
+	return weekday*minutesPerDay + t.Hour()*60 + t.Minute()
+}
+
+// IsOpen indica si el horario está abierto en t (hora de Santiago)
+func (s Schedule) IsOpen(t time.Time) bool {
+	m := MinuteOfWeek(t)
+	for _, iv := range s.Intervals() {
+		if iv[0] <= m && m < iv[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// String devuelve el horario en forma canónica: una regla por cada grupo de días
+// con los mismos tramos ("Mo-Fr 09:00-18:00; Sa 10:00-14:00")
+func (s Schedule) String() string {
+	allDay := true
+	for _, spans := range s.days {
+		if len(spans) != 1 || spans[0] != (span{0, minutesPerDay}) {
+			allDay = false
+			break
+		}
+	}
+	if allDay {
+		return "24/7"
+	}
+
+	var rules []string
+	done := [7]bool{}
+	for d := 0; d < 7; d++ {
+		if done[d] || len(s.days[d]) == 0 {
+			continue
+		}
+		var group []int
+		for e := d; e < 7; e++ {
+			if !done[e] && equalSpans(s.days[e], s.days[d]) {
+				group = append(group, e)
+				done[e] = true
+			}
+		}
+		rules = append(rules, formatDays(group)+" "+formatSpans(s.days[d]))
+	}
+	if len(rules) == 0 {
+		return "off"
+	}
+	return strings.Join(rules, "; ")
+}
+
+// formatDays escribe días ordenados como "Mo-Fr" o "Mo,We,Fr-Su"
+func formatDays(days []int) string {
+	var parts []string
+	for i := 0; i < len(days); {
+		j := i
+		for j+1 < len(days) && days[j+1] == days[j]+1 {
+			j++
+		}
+		part := dayCodes[days[i]]
+		if j > i {
+			part += "-" + dayCodes[days[j]]
+		}
+		parts = append(parts, part)
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+func equalSpans(a, b []span) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func formatSpans(spans []span) string {
+	parts := make([]string, len(spans))
+	for i, sp := range spans {
+		end := sp.end
+		if end > minutesPerDay {
+			end -= minutesPerDay
+		}
+		parts[i] = formatClock(sp.start) + "-" + formatClock(end)
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatClock(m int) string {
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
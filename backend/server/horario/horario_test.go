@@ -0,0 +1,93 @@
+package horario
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCanonical(t *testing.T) {
+	cases := map[string]string{
+		"24/7":                               "24/7",
+		"Mo-Fr 09:00-18:00; Sa 10:00-14:00":  "Mo-Fr 09:00-18:00; Sa 10:00-14:00",
+		"Mo,Tu,We,Th,Fr 09:00-18:00; PH off": "Mo-Fr 09:00-18:00",
+		"Mo-Su 08:00-20:00; Su off":          "Mo-Sa 08:00-20:00",
+		"Fr-Mo 22:00-02:00":                  "Mo,Fr-Su 22:00-02:00",
+		"Mo-Fr 09:00-13:00,15:00-19:00":      "Mo-Fr 09:00-13:00,15:00-19:00",
+		"Sa":                                 "Sa 00:00-24:00",
+	}
+	for in, want := range cases {
+		sch, err := Parse(in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", in, err)
+			continue
+		}
+		if got := sch.String(); got != want {
+			t.Errorf("Parse(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	for _, bad := range []string{"", "Mo-Fr 9-18", "Lunes 09:00-18:00", "Mo-Fr 09:00+", "Jan 09:00-18:00"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) debería fallar", bad)
+		}
+	}
+}
+
+func TestIsOpenSantiago(t *testing.T) {
+	sch, _ := Parse("Mo-Fr 09:00-18:00; Sa 22:00-02:00")
+
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, Santiago)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	cases := map[string]bool{
+		"2026-10-16 09:00": true,  // viernes
+		"2026-10-16 18:00": false, // cierre exclusivo
+		"2026-10-17 23:30": true,  // sábado en la noche
+		"2026-10-18 01:59": true,  // domingo de madrugada, tramo del sábado
+		"2026-10-18 02:00": false,
+	}
+	for s, want := range cases {
+		if got := sch.IsOpen(at(s)); got != want {
+			t.Errorf("IsOpen(%s) = %v, want %v", s, got, want)
+		}
+	}
+
+	// El mismo instante en UTC se evalúa en hora de Chile
+	if !sch.IsOpen(at("2026-10-16 10:00").UTC()) {
+		t.Error("IsOpen debería evaluar en America/Santiago")
+	}
+}
+
+func TestFromText(t *testing.T) {
+	cases := map[string]string{
+		"Lunes a viernes 9:00 a 18:00":                   "Mo-Fr 09:00-18:00",
+		"Lunes a Viernes de 9 a 18 hrs, sábado 10 a 14":  "Mo-Fr 09:00-18:00; Sa 10:00-14:00",
+		"L-V 9:00-13:00 y 15:00-19:00":                   "Mo-Fr 09:00-13:00,15:00-19:00",
+		"Todos los días 10:00 - 20:00":                   "Mo-Su 10:00-20:00",
+		"24 horas":                                       "24/7",
+		"Lunes, miércoles y viernes 10.30 a 12.30":       "Mo,We,Fr 10:30-12:30",
+		"Lunes a sábado 9 a 6":                           "Mo-Sa 09:00-18:00",
+		"De lunes a viernes 9am a 7pm. Domingo cerrado.": "Mo-Fr 09:00-19:00",
+	}
+	for in, want := range cases {
+		sch, err := FromText(in)
+		if err != nil {
+			t.Errorf("FromText(%q): %v", in, err)
+			continue
+		}
+		if got := sch.String(); got != want {
+			t.Errorf("FromText(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	for _, bad := range []string{"Consultar por WhatsApp", "Lunes a viernes", "2 turnos 9 a 13", ""} {
+		if sch, err := FromText(bad); err == nil {
+			t.Errorf("FromText(%q) = %q, debería fallar", bad, sch)
+		}
+	}
+}
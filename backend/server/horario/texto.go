@@ -0,0 +1,203 @@
+package horario
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Palabras de días en los horarios escritos a mano (ya en minúsculas y sin acentos)
+var dayWords = map[string]int{
+	"lunes": 0, "lun": 0, "lu": 0, "l": 0,
+	"martes": 1, "mar": 1, "ma": 1, "m": 1,
+	"miercoles": 2, "mie": 2, "mi": 2, "x": 2,
+	"jueves": 3, "jue": 3, "ju": 3, "j": 3,
+	"viernes": 4, "vie": 4, "vi": 4, "v": 4,
+	"sabado": 5, "sabados": 5, "sab": 5, "sa": 5, "s": 5,
+	"domingo": 6, "domingos": 6, "dom": 6, "do": 6, "d": 6,
+}
+
+// Frases que equivalen a un grupo de días o a abierto siempre
+var phraseReplacer = strings.NewReplacer(
+	"todos los dias", " todos ",
+	"toda la semana", " todos ",
+	"diariamente", " todos ",
+	"diario", " todos ",
+	"fin de semana", " sabado domingo ",
+	"fines de semana", " sabado domingo ",
+	"24 horas", " 24/7 ",
+	"24hrs", " 24/7 ",
+	"24 hrs", " 24/7 ",
+	"todo el dia", " 00:00 - 24:00 ",
+)
+
+var textReplacer = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+	"-", " - ", "–", " - ", ",", " , ", ";", " ; ", "\n", " ; ", "/", " / ",
+)
+
+// clockRe reconoce horas escritas a mano: "9", "9:30", "9.30", "9hrs", "9am"
+var clockRe = regexp.MustCompile(`^(\d{1,2})(?:[:.](\d{2}))?(?:h|hr|hrs|horas)?(am|pm)?$`)
+
+// FromText intenta convertir un horario escrito a mano ("Lunes a viernes 9:00 a
+// 18:00, sábado 10 a 14") a un Schedule. Devuelve error si no reconoce el texto:
+// en ese caso el punto queda sin horario estructurado.
+func FromText(text string) (Schedule, error) {
+	if sch, err := Parse(text); err == nil {
+		return sch, nil
+	}
+
+	s := " " + strings.ToLower(text) + " "
+	s = strings.ReplaceAll(s, "24/7", " 24/7 ")
+	s = phraseReplacer.Replace(s)
+	// Se protege 24/7 antes de separar las barras
+	s = strings.ReplaceAll(s, "24/7", "§")
+	s = textReplacer.Replace(s)
+	s = strings.ReplaceAll(s, "§", " 24/7 ")
+
+	var (
+		rules   []string
+		days    []int
+		spans   []string
+		pending = -1 // hora a la espera del fin del tramo
+		joining bool // se leyó "a", "al", "hasta" o "-"
+		lastDay = -1
+		closed  bool
+		always  bool
+	)
+
+	flush := func() error {
+		if pending >= 0 {
+			return fmt.Errorf("%w: incomplete time range", ErrUnsupported)
+		}
+		if len(days) == 0 && len(spans) == 0 && !closed {
+			return nil
+		}
+		if len(spans) == 0 && !closed {
+			return fmt.Errorf("%w: days without hours", ErrUnsupported)
+		}
+		selector := ""
+		if len(days) > 0 && len(days) < 7 {
+			codes := make([]string, len(days))
+			for i, d := range days {
+				codes[i] = dayCodes[d]
+			}
+			selector = strings.Join(codes, ",") + " "
+		}
+		if closed {
+			rules = append(rules, selector+"off")
+		} else {
+			rules = append(rules, selector+strings.Join(spans, ","))
+		}
+		days, spans, lastDay, closed = nil, nil, -1, false
+		return nil
+	}
+
+	for _, tok := range strings.Fields(s) {
+		tok = strings.TrimSuffix(tok, ".")
+		switch {
+		case tok == "24/7":
+			always = true
+
+		case tok == "todos":
+			if len(spans) > 0 {
+				if err := flush(); err != nil {
+					return Schedule{}, err
+				}
+			}
+			days = []int{0, 1, 2, 3, 4, 5, 6}
+
+		case tok == "a" || tok == "al" || tok == "hasta" || tok == "-":
+			joining = true
+
+		case tok == "cerrado" || tok == "cerrados":
+			closed = true
+
+		case tok == ";":
+			if err := flush(); err != nil {
+				return Schedule{}, err
+			}
+
+		default:
+			if d, ok := dayWords[tok]; ok {
+				// Un día después de horas empieza una regla nueva
+				if len(spans) > 0 || closed {
+					if err := flush(); err != nil {
+						return Schedule{}, err
+					}
+				}
+				if joining && lastDay >= 0 {
+					for x := (lastDay + 1) % 7; ; x = (x + 1) % 7 {
+						days = append(days, x)
+						if x == d {
+							break
+						}
+					}
+				} else {
+					days = append(days, d)
+				}
+				lastDay, joining = d, false
+				continue
+			}
+
+			if m, ok := parseLooseClock(tok); ok {
+				if pending < 0 {
+					pending = m
+				} else if !joining {
+					// Dos números seguidos sin "a" o "-" entre ellos: no es un tramo
+					return Schedule{}, fmt.Errorf("%w: %q", ErrUnsupported, text)
+				} else {
+					end := m
+					// "9 a 6" se entiende como 09:00-18:00
+					if end <= pending && end < 12*60 && pending < 12*60 && end+12*60 > pending {
+						end += 12 * 60
+					}
+					spans = append(spans, formatClock(pending)+"-"+formatClock(end))
+					pending = -1
+				}
+				joining = false
+				continue
+			}
+			// Palabras de relleno ("de", "horario", "atencion", ",", "y"...) se ignoran
+		}
+	}
+	if err := flush(); err != nil {
+		return Schedule{}, err
+	}
+
+	if always && len(rules) == 0 {
+		return Parse("24/7")
+	}
+	if len(rules) == 0 {
+		return Schedule{}, fmt.Errorf("%w: %q", ErrUnsupported, text)
+	}
+	return Parse(strings.Join(rules, "; "))
+}
+
+// parseLooseClock lee horas escritas a mano: "9", "09:30", "9hrs", "7pm"
+func parseLooseClock(tok string) (int, bool) {
+	m := clockRe.FindStringSubmatch(tok)
+	if m == nil {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	}
+	if minute > 59 || hour*60+minute > minutesPerDay {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
@@ -0,0 +1,103 @@
+// Package ical escribe calendarios iCalendar (RFC 5545) para suscribirse desde
+// Google Calendar, Outlook o el calendario del teléfono
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event es un VEVENT. Las horas se escriben en UTC.
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time // Última modificación (DTSTAMP)
+	Summary     string
+	Description string
+	Location    string
+	Lat, Lng    float64 // GEO; se omite si ambos son 0
+	URL         string
+}
+
+const timeFormat = "20060102T150405Z"
+
+// maxLineOctets es el largo máximo de una línea antes de plegarla
+const maxLineOctets = 75
+
+// Write escribe un VCALENDAR con los eventos. name es el nombre que muestran los
+// clientes al suscribirse.
+func Write(w io.Writer, name string, events []Event) error {
+	cw := &calendarWriter{w: w}
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//Donde Ayudo CL//Turnos//ES")
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	cw.property("X-WR-CALNAME", name)
+
+	for _, e := range events {
+		cw.line("BEGIN:VEVENT")
+		cw.property("UID", e.UID)
+		cw.line("DTSTAMP:" + e.Stamp.UTC().Format(timeFormat))
+		cw.line("DTSTART:" + e.Start.UTC().Format(timeFormat))
+		cw.line("DTEND:" + e.End.UTC().Format(timeFormat))
+		cw.property("SUMMARY", e.Summary)
+		if e.Description != "" {
+			cw.property("DESCRIPTION", e.Description)
+		}
+		if e.Location != "" {
+			cw.property("LOCATION", e.Location)
+		}
+		if e.Lat != 0 || e.Lng != 0 {
+			cw.line(fmt.Sprintf("GEO:%.6f;%.6f", e.Lat, e.Lng))
+		}
+		if e.URL != "" {
+			cw.line("URL:" + e.URL)
+		}
+		cw.line("END:VEVENT")
+	}
+
+	cw.line("END:VCALENDAR")
+	return cw.err
+}
+
+type calendarWriter struct {
+	w   io.Writer
+	err error
+}
+
+// property escribe una propiedad de texto con su valor escapado
+func (cw *calendarWriter) property(name, value string) {
+	cw.line(name + ":" + escape(value))
+}
+
+// line escribe una línea terminada en CRLF, plegada cada 75 octetos sin cortar
+// caracteres UTF-8
+func (cw *calendarWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	_, cw.err = io.WriteString(cw.w, b.String())
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape aplica el escape de valores TEXT de RFC 5545
+func escape(s string) string {
+	return escaper.Replace(s)
+}
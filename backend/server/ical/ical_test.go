@@ -0,0 +1,60 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteEvent(t *testing.T) {
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.FixedZone("CLT", -3*3600))
+	var b strings.Builder
+	err := Write(&b, "Turnos", []Event{{
+		UID:         "tur_1@dondeayudo.cl",
+		Start:       start,
+		End:         start.Add(8 * time.Hour),
+		Stamp:       start,
+		Summary:     "Cocina, Albergue Escuela",
+		Description: "Traer delantal\nCupos: 2 de 4",
+		Lat:         -36.82,
+		Lng:         -73.05,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART:20260301T110000Z\r\n",
+		"DTEND:20260301T190000Z\r\n",
+		`SUMMARY:Cocina\, Albergue Escuela` + "\r\n",
+		`DESCRIPTION:Traer delantal\nCupos: 2 de 4` + "\r\n",
+		"GEO:-36.820000;-73.050000\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("falta %q en:\n%s", want, out)
+		}
+	}
+}
+
+// Las líneas largas se pliegan en 75 octetos sin partir caracteres multibyte
+func TestLineFolding(t *testing.T) {
+	var b strings.Builder
+	Write(&b, strings.Repeat("ñ", 100), nil)
+
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("línea de %d octetos: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("carácter partido en %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "X-WR-CALNAME:"+strings.Repeat("ñ", 100)+"\r\n") {
+		t.Errorf("el nombre no se recupera al desplegar:\n%s", b.String())
+	}
+}
@@ -0,0 +1,90 @@
+package kml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// Estos tipos arman el KML que se guarda en lugar del archivo subido: solo
+// nombre, descripción (como texto) y geometrías, sin estilos, íconos remotos,
+// NetworkLinks ni HTML del original
+type kmlDoc struct {
+	XMLName    xml.Name       `xml:"kml"`
+	Xmlns      string         `xml:"xmlns,attr"`
+	Placemarks []placemarkOut `xml:"Document>Placemark"`
+}
+
+type placemarkOut struct {
+	Nombre      string      `xml:"name,omitempty"`
+	Descripcion string      `xml:"description,omitempty"`
+	Point       *coordsOut  `xml:"Point,omitempty"`
+	LineString  *coordsOut  `xml:"LineString,omitempty"`
+	Polygon     *polygonOut `xml:"Polygon,omitempty"`
+}
+
+type coordsOut struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type polygonOut struct {
+	Exterior   string    `xml:"outerBoundaryIs>LinearRing>coordinates"`
+	Interiores []ringOut `xml:"innerBoundaryIs"`
+}
+
+type ringOut struct {
+	Coordinates string `xml:"LinearRing>coordinates"`
+}
+
+// Encode genera un KML limpio a partir de las rutas ya validadas por Parse,
+// con un Placemark por geometría
+func Encode(rutas models.RutasGeoJSON) ([]byte, error) {
+	doc := kmlDoc{Xmlns: "http://www.opengis.net/kml/2.2"}
+	for _, f := range rutas.Features {
+		pm := placemarkOut{}
+		pm.Nombre, _ = f.Properties["nombre"].(string)
+		pm.Descripcion, _ = f.Properties["descripcion"].(string)
+
+		switch coords := f.Geometry.Coordinates.(type) {
+		case []float64:
+			pm.Point = &coordsOut{formatCoords([][]float64{coords})}
+		case [][]float64:
+			pm.LineString = &coordsOut{formatCoords(coords)}
+		case [][][]float64:
+			if len(coords) == 0 {
+				return nil, errors.New("polygon without rings")
+			}
+			pm.Polygon = &polygonOut{Exterior: formatCoords(coords[0])}
+			for _, ring := range coords[1:] {
+				pm.Polygon.Interiores = append(pm.Polygon.Interiores, ringOut{formatCoords(ring)})
+			}
+		default:
+			return nil, fmt.Errorf("unsupported geometry %s", f.Geometry.Type)
+		}
+		doc.Placemarks = append(doc.Placemarks, pm)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// formatCoords escribe "lng,lat lng,lat ..."
+func formatCoords(coords [][]float64) string {
+	parts := make([]string, len(coords))
+	for i, c := range coords {
+		parts[i] = strconv.FormatFloat(c[0], 'f', -1, 64) + "," + strconv.FormatFloat(c[1], 'f', -1, 64)
+	}
+	return strings.Join(parts, " ")
+}
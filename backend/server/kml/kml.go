@@ -0,0 +1,338 @@
+// Package kml lee las rutas de acceso de un punto desde un archivo KML o KMZ
+// (exportado de Google Earth, My Maps, Wikiloc...) y las valida antes de
+// convertirlas a GeoJSON para el mapa.
+package kml
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+const (
+	MaxBytes       = 5 << 20 // Tamaño máximo del KML (ya descomprimido si viene en un KMZ)
+	MaxGeometrias  = 200
+	MaxVertices    = 50_000
+	maxDescripcion = 2000
+)
+
+// ContentTypeKML es el tipo del KML que genera Encode
+const ContentTypeKML = "application/vnd.google-earth.kml+xml"
+
+var (
+	ErrFormato    = errors.New("invalid file, expected KML or KMZ")
+	ErrTooLarge   = fmt.Errorf("KML too large, max %d MB", MaxBytes>>20)
+	ErrEmpty      = errors.New("KML has no Point, LineString or Polygon")
+	ErrOutOfChile = errors.New("coordinates outside Chile")
+)
+
+// Rutas es el resultado de leer un archivo válido
+type Rutas struct {
+	Geometrias int
+	Vertices   int
+	GeoJSON    models.RutasGeoJSON
+}
+
+// bounds es un rectángulo lat/lng
+type bounds struct{ minLat, maxLat, minLng, maxLng float64 }
+
+// chile son el territorio continental y las islas habitadas (Juan Fernández y Rapa Nui)
+var chile = []bounds{
+	{-56.6, -17.4, -76.0, -66.0},
+	{-34.0, -33.0, -81.0, -78.5},
+	{-27.3, -27.0, -109.5, -109.2},
+}
+
+func inChile(lng, lat float64) bool {
+	for _, b := range chile {
+		if lat >= b.minLat && lat <= b.maxLat && lng >= b.minLng && lng <= b.maxLng {
+			return true
+		}
+	}
+	return false
+}
+
+// placemark toma name y description; el resto de los hijos (geometrías, estilos,
+// ExtendedData...) queda en Hijos y solo se usan las geometrías
+type placemark struct {
+	Nombre      string     `xml:"name"`
+	Descripcion string     `xml:"description"`
+	Hijos       []geometry `xml:",any"`
+}
+
+type geometry struct {
+	XMLName     xml.Name
+	Coordinates string     `xml:"coordinates"`                            // Point, LineString, LinearRing
+	Exterior    string     `xml:"outerBoundaryIs>LinearRing>coordinates"` // Polygon
+	Interiores  []string   `xml:"innerBoundaryIs>LinearRing>coordinates"` // Polygon
+	Coords      []string   `xml:"coord"`                                  // gx:Track ("lng lat alt")
+	Hijos       []geometry `xml:",any"`                                   // MultiGeometry, gx:MultiTrack
+}
+
+// Parse lee un KML o un KMZ (se reconoce por el contenido) y lo valida: al
+// menos una geometría, no más de MaxGeometrias ni MaxVertices, y todas las
+// coordenadas dentro de Chile. Cada geometría queda como un Feature con el
+// nombre y la descripción de su Placemark.
+func Parse(data []byte) (*Rutas, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		doc, err := unzip(data)
+		if err != nil {
+			return nil, err
+		}
+		data = doc
+	}
+	if len(data) > MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	p := &parser{features: []models.GeoJSONFeature{}}
+	if err := p.parse(data); err != nil {
+		return nil, err
+	}
+	if len(p.features) == 0 {
+		return nil, ErrEmpty
+	}
+
+	return &Rutas{
+		Geometrias: len(p.features),
+		Vertices:   p.vertices,
+		GeoJSON: models.RutasGeoJSON{
+			Type:     "FeatureCollection",
+			BBox:     []float64{p.minLng, p.minLat, p.maxLng, p.maxLat},
+			Features: p.features,
+		},
+	}, nil
+}
+
+// unzip devuelve el KML principal de un KMZ: doc.kml o, si no está, el primer .kml
+func unzip(data []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrFormato
+	}
+
+	var doc *zip.File
+	for _, f := range zr.File {
+		if !strings.EqualFold(path.Ext(f.Name), ".kml") {
+			continue
+		}
+		if strings.EqualFold(f.Name, "doc.kml") {
+			doc = f
+			break
+		}
+		if doc == nil {
+			doc = f
+		}
+	}
+	if doc == nil {
+		return nil, ErrFormato
+	}
+
+	rc, err := doc.Open()
+	if err != nil {
+		return nil, ErrFormato
+	}
+	defer rc.Close()
+
+	// Se lee con límite: el tamaño declarado en el zip puede mentir
+	out, err := io.ReadAll(io.LimitReader(rc, MaxBytes+1))
+	if err != nil {
+		return nil, ErrFormato
+	}
+	if len(out) > MaxBytes {
+		return nil, ErrTooLarge
+	}
+	return out, nil
+}
+
+type parser struct {
+	features                       []models.GeoJSONFeature
+	vertices                       int
+	minLng, minLat, maxLng, maxLat float64
+}
+
+func (p *parser) parse(data []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	root := true
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ErrFormato
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if root {
+			if start.Name.Local != "kml" {
+				return ErrFormato
+			}
+			root = false
+		}
+		if start.Name.Local != "Placemark" {
+			continue
+		}
+
+		var pm placemark
+		if err := dec.DecodeElement(&pm, &start); err != nil {
+			return ErrFormato
+		}
+		if err := p.addPlacemark(pm); err != nil {
+			return err
+		}
+	}
+	if root {
+		return ErrFormato
+	}
+	return nil
+}
+
+func (p *parser) addPlacemark(pm placemark) error {
+	props := map[string]any{}
+	if nombre := strings.TrimSpace(pm.Nombre); nombre != "" {
+		props["nombre"] = nombre
+	}
+	if descripcion := truncate(strings.TrimSpace(pm.Descripcion), maxDescripcion); descripcion != "" {
+		props["descripcion"] = descripcion
+	}
+	for _, g := range pm.Hijos {
+		if err := p.addGeometry(g, props); err != nil {
+			return fmt.Errorf("%s: %w", placemarkName(pm), err)
+		}
+	}
+	return nil
+}
+
+func (p *parser) addGeometry(g geometry, props map[string]any) error {
+	var geom models.GeoJSONGeometry
+	switch g.XMLName.Local {
+	case "MultiGeometry", "MultiTrack":
+		for _, h := range g.Hijos {
+			if err := p.addGeometry(h, props); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "Point":
+		coords, err := p.coordinates(g.Coordinates)
+		if err != nil {
+			return err
+		}
+		if len(coords) != 1 {
+			return errors.New("Point must have one coordinate")
+		}
+		geom = models.GeoJSONGeometry{Type: "Point", Coordinates: coords[0]}
+	case "LineString", "LinearRing":
+		coords, err := p.coordinates(g.Coordinates)
+		if err != nil {
+			return err
+		}
+		if len(coords) < 2 {
+			return errors.New("LineString must have at least two coordinates")
+		}
+		geom = models.GeoJSONGeometry{Type: "LineString", Coordinates: coords}
+	case "Track":
+		coords, err := p.coordinates(strings.Join(trackCoords(g.Coords), " "))
+		if err != nil {
+			return err
+		}
+		if len(coords) < 2 {
+			return errors.New("Track must have at least two coordinates")
+		}
+		geom = models.GeoJSONGeometry{Type: "LineString", Coordinates: coords}
+	case "Polygon":
+		var rings [][][]float64
+		for _, raw := range append([]string{g.Exterior}, g.Interiores...) {
+			ring, err := p.coordinates(raw)
+			if err != nil {
+				return err
+			}
+			if len(ring) < 4 {
+				return errors.New("Polygon rings must have at least four coordinates")
+			}
+			rings = append(rings, ring)
+		}
+		geom = models.GeoJSONGeometry{Type: "Polygon", Coordinates: rings}
+	default:
+		return nil
+	}
+
+	if len(p.features) == MaxGeometrias {
+		return fmt.Errorf("KML has more than %d geometries", MaxGeometrias)
+	}
+	p.features = append(p.features, models.GeoJSONFeature{Type: "Feature", Geometry: geom, Properties: props})
+	return nil
+}
+
+// coordinates lee una lista "lng,lat[,alt] lng,lat[,alt] ..." descartando la altura
+func (p *parser) coordinates(raw string) ([][]float64, error) {
+	fields := strings.Fields(raw)
+	coords := make([][]float64, 0, len(fields))
+	for _, f := range fields {
+		parts := strings.Split(f, ",")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid coordinate %q", f)
+		}
+		lng, err1 := strconv.ParseFloat(parts[0], 64)
+		lat, err2 := strconv.ParseFloat(parts[1], 64)
+		if err1 != nil || err2 != nil || math.IsNaN(lng) || math.IsNaN(lat) {
+			return nil, fmt.Errorf("invalid coordinate %q", f)
+		}
+		if !inChile(lng, lat) {
+			return nil, fmt.Errorf("%w: %s", ErrOutOfChile, f)
+		}
+
+		p.vertices++
+		if p.vertices > MaxVertices {
+			return nil, fmt.Errorf("KML has more than %d vertices", MaxVertices)
+		}
+		if p.vertices == 1 {
+			p.minLng, p.maxLng, p.minLat, p.maxLat = lng, lng, lat, lat
+		} else {
+			p.minLng, p.maxLng = math.Min(p.minLng, lng), math.Max(p.maxLng, lng)
+			p.minLat, p.maxLat = math.Min(p.minLat, lat), math.Max(p.maxLat, lat)
+		}
+		coords = append(coords, []float64{lng, lat})
+	}
+	return coords, nil
+}
+
+// trackCoords pasa los gx:coord ("lng lat alt") al formato de coordinates
+func trackCoords(coords []string) []string {
+	out := make([]string, 0, len(coords))
+	for _, c := range coords {
+		out = append(out, strings.Join(strings.Fields(c), ","))
+	}
+	return out
+}
+
+func placemarkName(pm placemark) string {
+	if nombre := strings.TrimSpace(pm.Nombre); nombre != "" {
+		return strconv.Quote(truncate(nombre, 80))
+	}
+	return "Placemark"
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,155 @@
+package kml
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const rutaKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">
+  <Document>
+    <name>Accesos</name>
+    <Folder>
+      <Placemark>
+        <name>Desde Ruta 160</name>
+        <description>Camino de tierra, solo 4x4</description>
+        <styleUrl>#linea</styleUrl>
+        <LineString>
+          <tessellate>1</tessellate>
+          <coordinates>
+            -73.0500,-36.8200,0 -73.0450,-36.8150,0
+            -73.0400,-36.8100,0
+          </coordinates>
+        </LineString>
+      </Placemark>
+      <Placemark>
+        <name>Puente cortado</name>
+        <MultiGeometry>
+          <Point><coordinates>-73.0420,-36.8120</coordinates></Point>
+          <Polygon>
+            <outerBoundaryIs><LinearRing><coordinates>
+              -73.043,-36.813 -73.041,-36.813 -73.041,-36.811 -73.043,-36.813
+            </coordinates></LinearRing></outerBoundaryIs>
+          </Polygon>
+        </MultiGeometry>
+      </Placemark>
+      <Placemark>
+        <gx:Track>
+          <when>2026-03-01T10:00:00Z</when>
+          <gx:coord>-73.05 -36.82 12</gx:coord>
+          <gx:coord>-73.04 -36.81 15</gx:coord>
+        </gx:Track>
+      </Placemark>
+    </Folder>
+  </Document>
+</kml>`
+
+func TestParseKML(t *testing.T) {
+	rutas, err := Parse([]byte(rutaKML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rutas.Geometrias != 4 || rutas.Vertices != 10 {
+		t.Fatalf("got %d geometrías, %d vértices", rutas.Geometrias, rutas.Vertices)
+	}
+
+	var tipos []string
+	for _, f := range rutas.GeoJSON.Features {
+		tipos = append(tipos, f.Geometry.Type)
+	}
+	if got := strings.Join(tipos, ","); got != "LineString,Point,Polygon,LineString" {
+		t.Errorf("tipos = %s", got)
+	}
+	if props := rutas.GeoJSON.Features[0].Properties; props["nombre"] != "Desde Ruta 160" || props["descripcion"] != "Camino de tierra, solo 4x4" {
+		t.Errorf("properties = %v", props)
+	}
+	if bbox := rutas.GeoJSON.BBox; bbox[0] != -73.05 || bbox[1] != -36.82 || bbox[2] != -73.04 || bbox[3] != -36.81 {
+		t.Errorf("bbox = %v", bbox)
+	}
+}
+
+func TestParseKMZ(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"files/icono.png": "png", "doc.kml": rutaKML} {
+		f, _ := zw.Create(name)
+		f.Write([]byte(content))
+	}
+	zw.Close()
+
+	rutas, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rutas.Geometrias != 4 {
+		t.Errorf("got %d geometrías", rutas.Geometrias)
+	}
+}
+
+// El KML que se guarda conserva las geometrías y deja el HTML del original como texto
+func TestEncodeRoundTrip(t *testing.T) {
+	src := strings.Replace(rutaKML, "Camino de tierra, solo 4x4",
+		`<![CDATA[<script>alert(1)</script>]]>`, 1)
+	rutas, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Encode(rutas.GeoJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(out, []byte("<script")) || bytes.Contains(out, []byte("styleUrl")) {
+		t.Errorf("KML sin limpiar:\n%s", out)
+	}
+
+	again, err := Parse(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.GeoJSON, rutas.GeoJSON) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", again.GeoJSON, rutas.GeoJSON)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	line := func(coords string) string {
+		return `<kml><Placemark><LineString><coordinates>` + coords + `</coordinates></LineString></Placemark></kml>`
+	}
+	for _, tc := range []struct {
+		name string
+		data string
+		want string
+	}{
+		{"no es kml", `<gpx><trk></trk></gpx>`, ErrFormato.Error()},
+		{"xml roto", `<kml><Placemark>`, ErrFormato.Error()},
+		{"sin geometrías", `<kml><Document><name>vacío</name></Document></kml>`, ErrEmpty.Error()},
+		{"fuera de Chile", line(`-73.05,-36.82 -58.38,-34.60`), ErrOutOfChile.Error()},
+		{"lat y lng invertidas", line(`-36.82,-73.05 -36.81,-73.04`), ErrOutOfChile.Error()},
+		{"coordenada inválida", line(`-73.05;-36.82 -73.04,-36.81`), "invalid coordinate"},
+		{"una sola coordenada", line(`-73.05,-36.82`), "at least two"},
+	} {
+		_, err := Parse([]byte(tc.data))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+
+	var many strings.Builder
+	many.WriteString("<kml>")
+	for i := 0; i <= MaxGeometrias; i++ {
+		many.WriteString(`<Placemark><Point><coordinates>-70.6,-33.4</coordinates></Point></Placemark>`)
+	}
+	many.WriteString("</kml>")
+	if _, err := Parse([]byte(many.String())); err == nil || !strings.Contains(err.Error(), "geometries") {
+		t.Errorf("%d geometrías: err = %v", MaxGeometrias+1, err)
+	}
+
+	if _, err := Parse(bytes.Repeat([]byte(" "), MaxBytes+1)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("archivo grande: err = %v", err)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/config"
 	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/database"
 	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/handlers"
 	mw "github.com/P1ngu-Dev/donde-ayudo-cl/backend/middleware"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/pow"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/storage"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
@@ -29,10 +32,30 @@ func main() {
 	}
 	defer db.Close()
 
+	// Derivar el horario estructurado de los puntos que aún no lo tienen
+	if _, err := database.BackfillHorarios(); err != nil {
+		log.Printf("⚠️  Error derivando horarios: %v", err)
+	}
+
+	// Almacenamiento de archivos subidos (fotos, rutas KML)
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatalf("❌ Error configurando almacenamiento: %v", err)
+	}
+
+	// Proxies cuyo X-Real-IP se acepta como IP del cliente
+	trustedProxies, err := mw.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("❌ TRUSTED_PROXIES inválido: %v", err)
+	}
+
 	// Crear router
 	r := chi.NewRouter()
 
 	// Middleware global
+	// IP del cliente desde X-Real-IP, solo si la conexión viene de nginx
+	r.Use(mw.RealIP(trustedProxies))
+
 	r.Use(middleware.Logger)    // Log de requests
 	r.Use(middleware.Recoverer) // Recuperación de panics
 	r.Use(mw.CORS())            // CORS para desarrollo
@@ -45,8 +68,61 @@ func main() {
 		w.Write([]byte(`{"message":"Donde Ayudo CL API v1.0","status":"running"}`))
 	})
 
+	// Documento OpenAPI generado a partir de las rutas y los modelos
+	r.Get("/api/openapi.json", handlers.OpenAPI(r))
+
 	r.Get("/api/puntos", handlers.GetPuntos)
+	r.Get("/api/puntos.geojson", handlers.GetPuntosGeoJSON)
+	r.Get("/api/hxl/dictionary", handlers.GetHXLDictionary)
+	r.Get("/api/puntos/clusters", handlers.GetPuntoClusters)
+	r.Get("/api/puntos/changes", handlers.GetPuntoChanges)
+	r.Get("/api/puntos/stream", handlers.StreamPuntos)
 	r.Get("/api/puntos/{id}", handlers.GetPunto)
+	r.Get("/api/puntos/{id}/matches", handlers.GetPuntoMatches)
+	r.Get("/api/puntos/{id}/inventario", handlers.GetPuntoInventario)
+	r.Get("/api/puntos/{id}/compromisos", handlers.GetPuntoCompromisos)
+	r.Get("/api/puntos/{id}/rutas.geojson", handlers.GetPuntoRutas)
+	r.Get("/api/puntos/{id}/turnos", handlers.GetPuntoTurnos)
+	r.Get("/api/puntos/{id}/turnos.ics", handlers.GetPuntoTurnosICS)
+	r.Get("/api/turnos", handlers.SearchTurnos)
+	r.Get("/api/inventario", handlers.SearchInventario)
+	r.Get("/api/matches", handlers.GetMatches)
+	r.Get("/api/stats", handlers.GetStats)
+	r.Get("/api/tiles/{z}/{x}/{y}.mvt", handlers.GetPuntosTile)
+
+	// Exportación completa sin paginación: con su propio límite por IP
+	r.Group(func(r chi.Router) {
+		r.Use(mw.RateLimit(cfg.SubmissionIPLimit, cfg.SubmissionDeviceLimit, cfg.SubmissionWindow))
+
+		r.Get("/api/puntos.hxl.csv", handlers.GetPuntosHXL)
+	})
+
+	// Envíos ciudadanos (quedan pendientes de moderación)
+	powIssuer := pow.NewIssuer(cfg.JWTSecret, cfg.PoWDifficulty, 5*time.Minute)
+	r.Get("/api/challenge", handlers.GetChallenge(powIssuer))
+
+	r.Group(func(r chi.Router) {
+		r.Use(mw.RateLimit(cfg.SubmissionIPLimit, cfg.SubmissionDeviceLimit, cfg.SubmissionWindow))
+		r.Use(mw.Honeypot(mw.HoneypotField))
+		r.Use(mw.RequireProofOfWork(powIssuer))
+		r.Use(mw.DedupSubmissions(cfg.SubmissionWindow))
+
+		r.Post("/api/puntos", handlers.SubmitPunto)
+		r.Post("/api/solicitudes", handlers.CreateSolicitud)
+		r.Post("/api/voluntarios", handlers.CreateVoluntario)
+		r.Post("/api/puntos/{id}/compromisos", handlers.CreateCompromiso)
+	})
+
+	// Turnos del voluntario (token recibido al inscribirse, sin cuenta)
+	r.Get("/api/voluntarios/me/turnos", handlers.GetMisTurnos)
+	r.Get("/api/voluntarios/me/turnos.ics", handlers.GetMisTurnosICS)
+	r.Group(func(r chi.Router) {
+		r.Use(mw.RateLimit(cfg.SubmissionIPLimit, cfg.SubmissionDeviceLimit, cfg.SubmissionWindow))
+
+		r.Post("/api/turnos/{id}/tomar", handlers.TomarTurno)
+		r.Post("/api/turnos/{id}/soltar", handlers.SoltarTurno)
+		r.Post("/api/compromisos/{id}/cancelar", handlers.CancelarCompromiso)
+	})
 
 	// ==================== AUTH ====================
 	r.Post("/api/auth/login", handlers.Login(cfg))
@@ -81,9 +157,105 @@ func main() {
 		// DELETE /api/admin/puntos/:id - Eliminar punto (superadmin)
 		r.With(mw.RequireRole("superadmin")).Delete("/puntos/{id}", handlers.DeletePunto)
 
+		// POST /api/admin/puntos/:id/fotos - Subir foto de evidencia o de asbesto (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Post("/puntos/{id}/fotos", handlers.UploadPuntoFoto(store, cfg.UploadMaxBytes))
+
+		// POST|DELETE /api/admin/puntos/:id/rutas - Subir o quitar el KML/KMZ de rutas de acceso (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Post("/puntos/{id}/rutas", handlers.UploadPuntoRutas(store, cfg.UploadMaxBytes))
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Delete("/puntos/{id}/rutas", handlers.DeletePuntoRutas)
+
 		// POST /api/admin/import-csv - Importar puntos desde CSV (admin, superadmin)
 		r.With(mw.RequireRole("admin", "superadmin")).Post("/import-csv", handlers.ImportCSV)
 
+		// --- ALBERGUES: OCUPACIÓN Y ENCARGADOS ---
+		// POST /api/admin/puntos/:id/ocupacion - Reportar ocupación (encargado del punto, verificador, admin, superadmin)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Post("/puntos/{id}/ocupacion", handlers.ReportOcupacion)
+
+		// GET /api/admin/puntos/:id/ocupacion - Historial de ocupación (encargado del punto, verificador, admin, superadmin)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Get("/puntos/{id}/ocupacion", handlers.GetOcupacionHistorial)
+
+		// GET /api/admin/mis-puntos - Puntos a cargo del usuario (encargado, verificador, admin, superadmin)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Get("/mis-puntos", handlers.GetMisPuntos)
+
+		// GET|PUT|DELETE /api/admin/puntos/:id/encargados[/:userId] - Asignar encargados (admin, superadmin)
+		r.With(mw.RequireRole("admin", "superadmin")).Get("/puntos/{id}/encargados", handlers.GetPuntoEncargados)
+		r.With(mw.RequireRole("admin", "superadmin")).Put("/puntos/{id}/encargados/{userId}", handlers.AssignEncargado)
+		r.With(mw.RequireRole("admin", "superadmin")).Delete("/puntos/{id}/encargados/{userId}", handlers.RemoveEncargado)
+
+		// --- INVENTARIO ---
+		// Inventario y movimientos de stock de un punto (encargado del punto, verificador, admin, superadmin)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Get("/puntos/{id}/inventario", handlers.GetAdminInventario)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Post("/puntos/{id}/inventario", handlers.CreateInventarioItem)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Patch("/puntos/{id}/inventario/{itemId}", handlers.UpdateInventarioItem)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Delete("/puntos/{id}/inventario/{itemId}", handlers.DeleteInventarioItem)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Get("/puntos/{id}/inventario/{itemId}/movimientos", handlers.GetMovimientos)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Post("/puntos/{id}/inventario/{itemId}/movimientos", handlers.CreateMovimiento)
+
+		// --- COMPROMISOS DE DONACIÓN ---
+		// Compromisos de un punto con datos del donante; marcarlos entregados o cancelados (encargado del punto, verificador, admin, superadmin)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Get("/puntos/{id}/compromisos", handlers.GetAdminCompromisos)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Patch("/puntos/{id}/compromisos/{compromisoId}", handlers.UpdateCompromiso)
+
+		// --- VOLUNTARIOS ---
+		// GET /api/admin/voluntarios - Voluntarios inscritos (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/voluntarios", handlers.GetVoluntarios)
+
+		// GET /api/admin/voluntarios/puntos - Puntos que piden voluntarios: cupo vs asignados (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/voluntarios/puntos", handlers.GetPuntosNecesitanVoluntarios)
+
+		// GET|PATCH /api/admin/voluntarios/:id - Ver y corregir la ficha (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/voluntarios/{id}", handlers.GetVoluntario)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Patch("/voluntarios/{id}", handlers.UpdateVoluntario)
+
+		// DELETE /api/admin/voluntarios/:id - Borrar la ficha y sus asignaciones (admin, superadmin)
+		r.With(mw.RequireRole("admin", "superadmin")).Delete("/voluntarios/{id}", handlers.DeleteVoluntario)
+
+		// GET /api/admin/puntos/:id/voluntarios - Voluntarios asignados (encargado del punto, verificador, admin, superadmin)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Get("/puntos/{id}/voluntarios", handlers.GetPuntoVoluntarios)
+
+		// GET /api/admin/puntos/:id/voluntarios/sugeridos - Voluntarios que podrían ir (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/puntos/{id}/voluntarios/sugeridos", handlers.GetVoluntariosSugeridos)
+
+		// PUT|DELETE /api/admin/puntos/:id/voluntarios/:voluntarioId - Asignar y quitar (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Put("/puntos/{id}/voluntarios/{voluntarioId}", handlers.AssignVoluntario)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Delete("/puntos/{id}/voluntarios/{voluntarioId}", handlers.RemoveVoluntario)
+
+		// POST /api/admin/voluntarios/:id/token - Nuevo token de turnos, invalida el anterior (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Post("/voluntarios/{id}/token", handlers.ResetVoluntarioToken)
+
+		// --- TURNOS ---
+		// Turnos del punto, planilla con inscritos e inscripción en nombre de un voluntario (encargado del punto, verificador, admin, superadmin)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Get("/puntos/{id}/turnos", handlers.GetAdminTurnos)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Post("/puntos/{id}/turnos", handlers.CreateTurnos)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Patch("/puntos/{id}/turnos/{turnoId}", handlers.UpdateTurno)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Delete("/puntos/{id}/turnos/{turnoId}", handlers.DeleteTurno)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Put("/puntos/{id}/turnos/{turnoId}/voluntarios/{voluntarioId}", handlers.AssignTurnoVoluntario)
+		r.With(mw.RequireRole("encargado", "verificador", "admin", "superadmin")).Delete("/puntos/{id}/turnos/{turnoId}/voluntarios/{voluntarioId}", handlers.RemoveTurnoVoluntario)
+
+		// --- EXPORTACIÓN ---
+		// GET /api/admin/export/puntos.{csv,kml,gpx,hxl.csv} - Mismos filtros que /puntos (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/export/puntos.csv", handlers.ExportPuntos("csv"))
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/export/puntos.kml", handlers.ExportPuntos("kml"))
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/export/puntos.gpx", handlers.ExportPuntos("gpx"))
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/export/puntos.hxl.csv", handlers.ExportPuntos("hxl"))
+
+		// --- ESTADÍSTICAS ---
+		// GET /api/admin/stats - Estadísticas sobre todos los estados (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/stats", handlers.GetAdminStats)
+
+		// --- MODERACIÓN DE ENVÍOS CIUDADANOS ---
+		// GET /api/admin/solicitudes - Cola de moderación (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Get("/solicitudes", handlers.GetSolicitudes)
+
+		// PATCH /api/admin/solicitudes/:id - Corregir el punto propuesto (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Patch("/solicitudes/{id}", handlers.UpdateSolicitudPunto)
+
+		// POST /api/admin/solicitudes/:id/aprobar - Aprobar y publicar (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Post("/solicitudes/{id}/aprobar", handlers.AprobarSolicitud)
+
+		// POST /api/admin/solicitudes/:id/rechazar - Rechazar con motivo (verificador, admin, superadmin)
+		r.With(mw.RequireRole("verificador", "admin", "superadmin")).Post("/solicitudes/{id}/rechazar", handlers.RechazarSolicitud)
+
 		// --- USUARIOS ---
 		// GET /api/admin/users - Listar usuarios (admin, superadmin)
 		r.With(mw.RequireRole("admin", "superadmin")).Get("/users", handlers.GetUsers)
@@ -98,6 +270,12 @@ func main() {
 		r.With(mw.RequireRole("superadmin")).Patch("/users/{id}/toggle-active", handlers.ToggleUserActive)
 	})
 
+	// ==================== ARCHIVOS SUBIDOS ====================
+	// Con el almacenamiento local los archivos se sirven desde el mismo servidor
+	if local, ok := store.(*storage.Local); ok {
+		r.Handle(storage.LocalPath+"/*", http.StripPrefix(storage.LocalPath+"/", local.Handler()))
+	}
+
 	// ==================== FRONTEND ESTÁTICO ====================
 	// Servir archivos estáticos del frontend
 	staticDir := os.Getenv("STATIC_DIR")
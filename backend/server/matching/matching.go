@@ -0,0 +1,337 @@
+// Package matching cruza las necesidades de las zonas (necesidades_tags y
+// categorias_ayuda de puntos SOS, albergues, etc.) con el stock que declaran los
+// acopios en su inventario, y sugiere los acopios más cercanos que pueden cubrir
+// cada una.
+package matching
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/geo"
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+// categoriaAliases lleva las palabras sueltas (ya normalizadas) a las categorías de
+// models.NecesidadesTags. Un tag que no está aquí se trata como un ítem.
+var categoriaAliases = map[string]string{
+	"alimentos":       "alimentos",
+	"alimento":        "alimentos",
+	"comida":          "alimentos",
+	"viveres":         "alimentos",
+	"mercaderia":      "alimentos",
+	"herramientas":    "herramientas",
+	"herramienta":     "herramientas",
+	"techo_abrigo":    "techo_abrigo",
+	"abrigo":          "techo_abrigo",
+	"techo":           "techo_abrigo",
+	"animales":        "animales",
+	"mascotas":        "animales",
+	"medicamentos":    "medicamentos",
+	"medicamento":     "medicamentos",
+	"remedios":        "medicamentos",
+	"salud":           "medicamentos",
+	"insumos_medicos": "medicamentos",
+	"olla_comun":      "olla_comun",
+}
+
+// listasPorCategoria son las claves de models.NecesidadesTags con lista de ítems
+var listasPorCategoria = []string{"alimentos", "herramientas", "techo_abrigo", "animales"}
+
+// listasLibres son las claves del formato {urgentes, importantes, deseables} del importador CSV
+var listasLibres = []string{"urgentes", "importantes", "deseables"}
+
+var tagReplacer = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+	"-", " ", "_", " ",
+)
+
+// Normalize deja un tag en minúsculas, sin acentos y con _ entre palabras
+func Normalize(s string) string {
+	s = tagReplacer.Replace(strings.ToLower(strings.TrimSpace(s)))
+	return strings.Join(strings.Fields(s), "_")
+}
+
+// Needs extrae las necesidades de un punto desde necesidades_tags y categorias_ayuda,
+// sin repetidos y en el orden en que aparecen
+func Needs(p *models.Punto) []models.Necesidad {
+	var out []models.Necesidad
+	seen := map[models.Necesidad]bool{}
+	add := func(n models.Necesidad) {
+		if (n.Categoria != "" || n.Item != "") && !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+
+	switch tags := p.NecesidadesTags.(type) {
+	case []any:
+		for _, tag := range stringValues(tags) {
+			add(freeTag(tag))
+		}
+	case map[string]any:
+		for _, cat := range listasPorCategoria {
+			items, ok := tags[cat].([]any)
+			if !ok {
+				continue
+			}
+			for _, item := range stringValues(items) {
+				add(categoryItem(cat, item))
+			}
+		}
+		if med, ok := tags["medicamentos"].(string); ok && strings.TrimSpace(med) != "" {
+			add(models.Necesidad{Categoria: "medicamentos"})
+		}
+		if olla, ok := tags["olla_comun"].(bool); ok && olla {
+			add(models.Necesidad{Categoria: "olla_comun"})
+		}
+		for _, key := range listasLibres {
+			if items, ok := tags[key].([]any); ok {
+				for _, tag := range stringValues(items) {
+					add(freeTag(tag))
+				}
+			}
+		}
+	}
+
+	for _, tag := range p.CategoriasAyuda {
+		add(freeTag(tag))
+	}
+
+	return out
+}
+
+func freeTag(tag string) models.Necesidad {
+	n := Normalize(tag)
+	if cat, ok := categoriaAliases[n]; ok {
+		return models.Necesidad{Categoria: cat}
+	}
+	return models.Necesidad{Item: n}
+}
+
+func categoryItem(cat, item string) models.Necesidad {
+	n := Normalize(item)
+	if categoriaAliases[n] == cat {
+		return models.Necesidad{Categoria: cat}
+	}
+	return models.Necesidad{Categoria: cat, Item: n}
+}
+
+// stringValues toma los strings no vacíos de un array JSON decodificado
+func stringValues(values []any) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Supply es lo que un acopio tiene en stock: los ítems de su inventario con
+// cantidad > 0. Sus propias necesidades_tags no cuentan (son lo que le falta).
+type Supply struct {
+	categorias map[string]bool // categorías con algún ítem en stock
+	items      map[string]bool
+}
+
+func SupplyOf(inventario []models.InventarioItem) Supply {
+	s := Supply{categorias: map[string]bool{}, items: map[string]bool{}}
+	for _, item := range inventario {
+		if item.Cantidad <= 0 {
+			continue
+		}
+		s.items[item.Item] = true
+		if item.Categoria != "" && item.Categoria != "otros" {
+			s.categorias[item.Categoria] = true
+		}
+	}
+	return s
+}
+
+// Covers indica si el acopio cubre la necesidad; exact es false cuando solo
+// coincide la categoría (pide "leche" y el acopio tiene "arroz" en alimentos)
+func (s Supply) Covers(n models.Necesidad) (covered, exact bool) {
+	if n.Item != "" {
+		if s.items[n.Item] {
+			return true, true
+		}
+		return n.Categoria != "" && s.categorias[n.Categoria], false
+	}
+	return s.categorias[n.Categoria], true
+}
+
+// Acopio es un acopio con su stock ya indexado, para reutilizarlo entre zonas
+type Acopio struct {
+	Punto  *models.Punto
+	Supply Supply
+}
+
+func NewAcopio(p *models.Punto, inventario []models.InventarioItem) Acopio {
+	return Acopio{Punto: p, Supply: SupplyOf(inventario)}
+}
+
+// Match sugiere para cada necesidad de p hasta perNeed acopios, del más cercano al
+// más lejano, a no más de maxDistanceM metros
+func Match(p *models.Punto, acopios []Acopio, maxDistanceM float64, perNeed int) models.PuntoMatches {
+	result := models.PuntoMatches{
+		PuntoID:     p.ID,
+		Nombre:      p.Nombre,
+		Categoria:   p.Categoria,
+		Ciudad:      p.Ciudad,
+		NombreZona:  p.NombreZona,
+		Latitud:     p.Latitud,
+		Longitud:    p.Longitud,
+		Necesidades: []models.NecesidadMatch{},
+	}
+
+	type candidate struct {
+		acopio    Acopio
+		distancia float64
+	}
+	var cercanos []candidate
+	for _, a := range acopios {
+		if a.Punto.ID == p.ID {
+			continue
+		}
+		d := geo.DistanceM(p.Latitud, p.Longitud, a.Punto.Latitud, a.Punto.Longitud)
+		if d <= maxDistanceM {
+			cercanos = append(cercanos, candidate{a, d})
+		}
+	}
+	sort.SliceStable(cercanos, func(i, j int) bool { return cercanos[i].distancia < cercanos[j].distancia })
+
+	for _, need := range Needs(p) {
+		m := models.NecesidadMatch{Necesidad: need, Acopios: []models.AcopioSugerido{}}
+		for _, c := range cercanos {
+			if len(m.Acopios) >= perNeed {
+				break
+			}
+			covered, exact := c.acopio.Supply.Covers(need)
+			if !covered {
+				continue
+			}
+			a := c.acopio.Punto
+			m.Acopios = append(m.Acopios, models.AcopioSugerido{
+				ID:                a.ID,
+				Nombre:            a.Nombre,
+				Direccion:         a.Direccion,
+				Ciudad:            a.Ciudad,
+				Latitud:           a.Latitud,
+				Longitud:          a.Longitud,
+				ContactoPrincipal: a.ContactoPrincipal,
+				Horario:           a.Horario,
+				DistanciaM:        math.Round(c.distancia),
+				Exacto:            exact,
+			})
+		}
+		result.Total++
+		if len(m.Acopios) > 0 {
+			result.Cubiertas++
+		}
+		result.Necesidades = append(result.Necesidades, m)
+	}
+
+	return result
+}
+
+// NeedFor busca la necesidad del punto que cubre un compromiso de item: un ítem de su
+// inventario bajo el objetivo o una de Needs. Si el punto pide una categoría completa
+// ("alimentos"), cualquier ítem de esa categoría sirve. Devuelve la necesidad con el
+// ítem del compromiso normalizado.
+func NeedFor(needs []models.Necesidad, inventario []models.InventarioItem, item, categoria string) (models.Necesidad, bool) {
+	n := Normalize(item)
+	if cat, ok := categoriaAliases[n]; ok {
+		n, categoria = "", cat
+	}
+
+	if n != "" {
+		for _, it := range inventario {
+			if it.Faltante != nil && it.Item == n {
+				return models.Necesidad{Categoria: it.Categoria, Item: n}, true
+			}
+		}
+	}
+	for _, need := range needs {
+		if n != "" && need.Item == n {
+			return need, true
+		}
+		if need.Item == "" && need.Categoria != "" && need.Categoria == categoria {
+			return models.Necesidad{Categoria: categoria, Item: n}, true
+		}
+	}
+	return models.Necesidad{}, false
+}
+
+// Pledged resume por necesidad y unidad lo comprometido frente a lo que falta según
+// el inventario. Las filas siguen el orden de los faltantes del inventario, luego
+// Needs, y al final los ítems comprometidos que ya no figuran como necesidad.
+// compromisos son solo los vigentes.
+func Pledged(needs []models.Necesidad, inventario []models.InventarioItem, compromisos []models.Compromiso) []models.NecesidadCompromisos {
+	rows := []models.NecesidadCompromisos{}
+	find := func(n models.Necesidad, unidad string) int {
+		for i, r := range rows {
+			if sameNeed(r.Necesidad, n) && strings.EqualFold(r.Unidad, unidad) {
+				return i
+			}
+		}
+		return -1
+	}
+	listed := func(n models.Necesidad) bool {
+		for _, r := range rows {
+			if sameNeed(r.Necesidad, n) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, it := range inventario {
+		if it.Faltante != nil {
+			rows = append(rows, models.NecesidadCompromisos{
+				Necesidad: models.Necesidad{Categoria: it.Categoria, Item: it.Item},
+				Unidad:    it.Unidad,
+				Faltante:  it.Faltante,
+			})
+		}
+	}
+	for _, n := range needs {
+		if !listed(n) {
+			rows = append(rows, models.NecesidadCompromisos{Necesidad: n})
+		}
+	}
+
+	for _, c := range compromisos {
+		n := models.Necesidad{Categoria: c.Categoria, Item: c.Item}
+		i := find(n, c.Unidad)
+		if i < 0 {
+			// Una necesidad sin unidad toma la del primer compromiso
+			if i = find(n, ""); i >= 0 {
+				rows[i].Unidad = c.Unidad
+			}
+		}
+		if i < 0 {
+			rows = append(rows, models.NecesidadCompromisos{Necesidad: n, Unidad: c.Unidad})
+			i = len(rows) - 1
+		}
+		rows[i].Comprometido += c.Cantidad
+		rows[i].Compromisos++
+	}
+
+	for i, r := range rows {
+		if r.Faltante != nil {
+			porCubrir := math.Max(0, *r.Faltante-r.Comprometido)
+			rows[i].PorCubrir = &porCubrir
+		}
+	}
+	return rows
+}
+
+// sameNeed compara por ítem, o por categoría si ninguna de las dos detalla ítem
+func sameNeed(a, b models.Necesidad) bool {
+	if a.Item != "" || b.Item != "" {
+		return a.Item == b.Item
+	}
+	return a.Categoria == b.Categoria
+}
@@ -0,0 +1,140 @@
+package matching
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+func punto(id string, lat, lng float64, tags string, ayuda ...string) *models.Punto {
+	p := &models.Punto{ID: id, Latitud: lat, Longitud: lng, CategoriasAyuda: ayuda}
+	json.Unmarshal([]byte(tags), &p.NecesidadesTags)
+	return p
+}
+
+// Los tres formatos de necesidades_tags que hay en la base se normalizan igual
+func TestNeedsFormats(t *testing.T) {
+	cases := []struct {
+		tags string
+		want []models.Necesidad
+	}{
+		{`["Alimentos", "Pañales"]`, []models.Necesidad{{Categoria: "alimentos"}, {Item: "panales"}}},
+		{`{"alimentos": ["Leche en polvo"], "olla_comun": true, "medicamentos": "insulina"}`, []models.Necesidad{
+			{Categoria: "alimentos", Item: "leche_en_polvo"}, {Categoria: "medicamentos"}, {Categoria: "olla_comun"},
+		}},
+		{`{"urgentes": ["agua"], "deseables": ["Agua", "ropa"]}`, []models.Necesidad{{Item: "agua"}, {Item: "ropa"}}},
+	}
+	for _, c := range cases {
+		got := Needs(punto("p", 0, 0, c.tags))
+		if len(got) != len(c.want) {
+			t.Errorf("%s: got %v, want %v", c.tags, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: got %v, want %v", c.tags, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+// stock arma un inventario con tríos categoría, ítem, cantidad
+func stock(values ...any) []models.InventarioItem {
+	var items []models.InventarioItem
+	for i := 0; i+2 < len(values); i += 3 {
+		items = append(items, models.InventarioItem{
+			Categoria: values[i].(string), Item: values[i+1].(string), Cantidad: float64(values[i+2].(int)),
+		})
+	}
+	return items
+}
+
+// Un acopio que pide algo (sus necesidades_tags) no es proveedor de eso; solo
+// cuenta lo que tiene en stock
+func TestSupplyFromStockNotNeeds(t *testing.T) {
+	zona := punto("sos", -33.0, -71.5, `["agua"]`)
+	acopios := []Acopio{
+		NewAcopio(punto("pide_agua", -33.001, -71.5, `["agua"]`, "agua"), nil),
+		NewAcopio(punto("sin_stock", -33.002, -71.5, `[]`), stock("otros", "agua", 0)),
+		NewAcopio(punto("con_agua", -33.01, -71.5, `["agua"]`), stock("otros", "agua", 200)),
+	}
+
+	m := Match(zona, acopios, 20000, 3)
+	got := m.Necesidades[0].Acopios
+	if len(got) != 1 || got[0].ID != "con_agua" || !got[0].Exacto {
+		t.Errorf("agua: %+v", got)
+	}
+}
+
+func TestMatchClosestCovering(t *testing.T) {
+	zona := punto("sos", -33.0, -71.5, `{"alimentos": ["leche"], "herramientas": ["pala"]}`)
+	acopios := []Acopio{
+		NewAcopio(punto("lejos", -33.05, -71.5, `[]`), stock("alimentos", "leche", 10)),
+		NewAcopio(punto("cerca", -33.01, -71.5, `[]`), stock("alimentos", "arroz", 5)),
+		NewAcopio(punto("fuera", -34.0, -71.5, `[]`), stock("alimentos", "leche", 1, "herramientas", "pala", 1)),
+	}
+
+	m := Match(zona, acopios, 20000, 3)
+	if m.Total != 2 || m.Cubiertas != 1 {
+		t.Fatalf("total=%d cubiertas=%d, want 2 y 1", m.Total, m.Cubiertas)
+	}
+
+	leche := m.Necesidades[0].Acopios
+	if len(leche) != 2 || leche[0].ID != "cerca" || leche[0].Exacto || leche[1].ID != "lejos" || !leche[1].Exacto {
+		t.Errorf("leche: %+v", leche)
+	}
+	if len(m.Necesidades[1].Acopios) != 0 {
+		t.Errorf("pala no debería tener acopios dentro del radio: %+v", m.Necesidades[1].Acopios)
+	}
+}
+
+// Lo comprometido se suma a la necesidad del inventario o de los tags con la misma unidad
+func TestPledged(t *testing.T) {
+	objetivo, faltante := 100.0, 60.0
+	inventario := []models.InventarioItem{
+		{Categoria: "otros", Item: "agua", Unidad: "litros", Cantidad: 40, Objetivo: &objetivo, Faltante: &faltante},
+	}
+	needs := Needs(punto("p", 0, 0, `["agua", "alimentos", "panales"]`))
+
+	if n, ok := NeedFor(needs, inventario, "Agua", ""); !ok || n != (models.Necesidad{Categoria: "otros", Item: "agua"}) {
+		t.Errorf("NeedFor(agua) = %v, %v", n, ok)
+	}
+	if n, ok := NeedFor(needs, inventario, "Arroz", "alimentos"); !ok || n != (models.Necesidad{Categoria: "alimentos", Item: "arroz"}) {
+		t.Errorf("NeedFor(arroz) = %v, %v", n, ok)
+	}
+	if _, ok := NeedFor(needs, inventario, "Leña", ""); ok {
+		t.Error("NeedFor(leña) no es una necesidad del punto")
+	}
+
+	rows := Pledged(needs, inventario, []models.Compromiso{
+		{Categoria: "otros", Item: "agua", Cantidad: 20, Unidad: "litros"},
+		{Categoria: "otros", Item: "agua", Cantidad: 50, Unidad: "litros"},
+		{Item: "panales", Cantidad: 3, Unidad: "paquetes"},
+		{Categoria: "alimentos", Item: "arroz", Cantidad: 10, Unidad: "kg"},
+	})
+
+	want := []struct {
+		item, unidad string
+		comprometido float64
+		porCubrir    float64 // -1 sin faltante
+	}{
+		{"agua", "litros", 70, 0},
+		{"", "", 0, -1}, // alimentos, sin compromisos
+		{"panales", "paquetes", 3, -1},
+		{"arroz", "kg", 10, -1},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d filas: %+v", len(rows), rows)
+	}
+	for i, w := range want {
+		r := rows[i]
+		if r.Item != w.item || r.Unidad != w.unidad || r.Comprometido != w.comprometido {
+			t.Errorf("fila %d: got %+v, want %+v", i, r, w)
+		}
+		if (w.porCubrir < 0) != (r.PorCubrir == nil) || (r.PorCubrir != nil && *r.PorCubrir != w.porCubrir) {
+			t.Errorf("fila %d: por_cubrir %v, want %v", i, r.PorCubrir, w.porCubrir)
+		}
+	}
+}
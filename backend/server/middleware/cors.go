@@ -6,6 +6,9 @@ import (
 	"github.com/go-chi/cors"
 )
 
+// VoluntarioTokenHeader lleva el token con que un voluntario toma y suelta turnos
+const VoluntarioTokenHeader = "X-Voluntario-Token"
+
 func CORS() func(http.Handler) http.Handler {
 	return cors.Handler(cors.Options{
 		AllowedOrigins: []string{
@@ -26,9 +29,9 @@ func CORS() func(http.Handler) http.Handler {
 			"https://donde-ayudo.cl",
 			"https://www.donde-ayudo.cl",
 		},
-		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Requested-With"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Requested-With", "If-None-Match", DeviceIDHeader, PoWChallengeHeader, PoWNonceHeader, VoluntarioTokenHeader},
+		ExposedHeaders:   []string{"Link", "ETag", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
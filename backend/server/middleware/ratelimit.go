@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeviceIDHeader identifica al dispositivo (id aleatorio que genera y guarda el frontend)
+const DeviceIDHeader = "X-Device-ID"
+
+// maxDeviceIDLength evita usar como clave valores arbitrariamente largos
+const maxDeviceIDLength = 64
+
+type windowCounter struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter cuenta peticiones por clave en ventanas fijas
+type rateLimiter struct {
+	mu        sync.Mutex
+	window    time.Duration
+	counters  map[string]*windowCounter
+	lastSweep time.Time
+}
+
+func newRateLimiter(window time.Duration) *rateLimiter {
+	return &rateLimiter{window: window, counters: map[string]*windowCounter{}}
+}
+
+// allow suma una petición a key y devuelve si está dentro del límite; si no, cuánto falta
+// para que se abra la siguiente ventana
+func (l *rateLimiter) allow(key string, limit int, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.window {
+		for k, c := range l.counters {
+			if now.Sub(c.start) >= l.window {
+				delete(l.counters, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.counters[key]
+	if !ok || now.Sub(c.start) >= l.window {
+		c = &windowCounter{start: now}
+		l.counters[key] = c
+	}
+	if c.count >= limit {
+		return false, c.start.Add(l.window).Sub(now)
+	}
+	c.count++
+	return true, 0
+}
+
+type rateKey struct {
+	key   string
+	limit int
+}
+
+// RateLimit limita las peticiones por IP y por X-Device-ID dentro de cada ventana.
+// Un límite 0 desactiva esa clave. Detrás de nginx requiere RealIP con el proxy como confianza.
+func RateLimit(ipLimit, deviceLimit int, window time.Duration) func(http.Handler) http.Handler {
+	limiter := newRateLimiter(window)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			keys := []rateKey{{"ip:" + clientIP(r), ipLimit}}
+			if device := strings.TrimSpace(r.Header.Get(DeviceIDHeader)); device != "" && len(device) <= maxDeviceIDLength {
+				keys = append(keys, rateKey{"dev:" + device, deviceLimit})
+			}
+
+			for _, k := range keys {
+				if k.limit == 0 {
+					continue
+				}
+				if ok, retry := limiter.allow(k.key, k.limit, now); !ok {
+					log.Printf("🚫 Rate limit excedido (%s) en %s", k.key, r.URL.Path)
+					w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+					http.Error(w, `{"error":"Too many requests, try again later"}`, http.StatusTooManyRequests)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP devuelve la IP sin puerto (RemoteAddr ya viene corregido por RealIP)
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIPHeader es la cabecera con la IP del cliente que pone nginx (proxy_set_header X-Real-IP $remote_addr)
+const RealIPHeader = "X-Real-IP"
+
+// ParseTrustedProxies lee una lista separada por comas de IPs o rangos CIDR
+func ParseTrustedProxies(list string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, raw := range strings.Split(list, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if !strings.Contains(raw, "/") {
+			ip := net.ParseIP(raw)
+			if ip == nil {
+				return nil, fmt.Errorf("proxy inválido: %q", raw)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(raw)
+		if err != nil {
+			return nil, fmt.Errorf("proxy inválido: %q", raw)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// RealIP reemplaza RemoteAddr por X-Real-IP solo si la conexión viene de un proxy
+// de confianza. Las demás cabeceras (True-Client-IP, X-Forwarded-For) las controla
+// el cliente y se ignoran: de esta IP depende el rate limit de los envíos públicos.
+func RealIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := forwardedIP(r, trusted); ip != "" {
+				r.RemoteAddr = ip
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP devuelve la IP de X-Real-IP si RemoteAddr es un proxy de confianza; si no, ""
+func forwardedIP(r *http.Request, trusted []*net.IPNet) string {
+	remote := net.ParseIP(clientIP(r))
+	if remote == nil || !containsIP(trusted, remote) {
+		return ""
+	}
+	ip := net.ParseIP(strings.TrimSpace(r.Header.Get(RealIPHeader)))
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
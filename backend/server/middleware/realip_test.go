@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForwardedIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("127.0.0.1, ::1, 10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, remote string
+		headers      map[string]string
+		want         string
+	}{
+		{"desde nginx", "127.0.0.1:5000", map[string]string{"X-Real-IP": "200.1.2.3"}, "200.1.2.3"},
+		{"desde rango de confianza", "10.1.2.3:5000", map[string]string{"X-Real-IP": "200.1.2.3"}, "200.1.2.3"},
+		{"cliente directo ignora cabeceras", "190.5.6.7:5000", map[string]string{
+			"X-Real-IP": "1.1.1.1", "True-Client-IP": "2.2.2.2", "X-Forwarded-For": "3.3.3.3",
+		}, ""},
+		{"proxy no usa True-Client-IP", "127.0.0.1:5000", map[string]string{"True-Client-IP": "2.2.2.2"}, ""},
+		{"X-Real-IP inválida", "127.0.0.1:5000", map[string]string{"X-Real-IP": "nginx"}, ""},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/api/solicitudes", nil)
+		r.RemoteAddr = tt.remote
+		for k, v := range tt.headers {
+			r.Header.Set(k, v)
+		}
+		if got := forwardedIP(r, trusted); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := ParseTrustedProxies("localhost"); err == nil {
+		t.Error("ParseTrustedProxies(localhost) debería fallar")
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/pow"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// Middleware para los envíos públicos. Orden sugerido en main.go:
+// RateLimit -> Honeypot -> RequireProofOfWork -> DedupSubmissions
+
+// HoneypotField es el campo oculto de los formularios: una persona no lo ve ni lo llena
+const HoneypotField = "website"
+
+// Cabeceras de la prueba de trabajo (ver GET /api/challenge)
+const (
+	PoWChallengeHeader = "X-PoW-Challenge"
+	PoWNonceHeader     = "X-PoW-Nonce"
+)
+
+// maxSubmissionBody es lo máximo que se lee del cuerpo para inspeccionarlo
+const maxSubmissionBody = 64 << 10
+
+// readJSONBody lee el cuerpo, lo deja disponible de nuevo para el handler
+// y lo devuelve decodificado como objeto
+func readJSONBody(w http.ResponseWriter, r *http.Request) (map[string]any, error) {
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSubmissionBody))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+
+	fields := map[string]any{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Honeypot descarta en silencio los envíos que llenan el campo trampa: responde
+// como si se hubieran aceptado para que el bot no aprenda a evitarlo
+func Honeypot(field string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fields, err := readJSONBody(w, r)
+			if err != nil {
+				http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+				return
+			}
+
+			if value, ok := fields[field]; ok && value != "" && value != nil {
+				log.Printf("🍯 Envío descartado por honeypot desde %s", clientIP(r))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusAccepted)
+				fmt.Fprintf(w, `{"id":"sol_%d","estado":"pendiente","message":"Submission received, it will be reviewed before being published"}`, time.Now().UnixNano())
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireProofOfWork exige un desafío de GET /api/challenge resuelto en las cabeceras
+// X-PoW-Challenge y X-PoW-Nonce. Si la dificultad es 0 no hace nada.
+func RequireProofOfWork(issuer *pow.Issuer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !issuer.Enabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := issuer.Verify(r.Header.Get(PoWChallengeHeader), r.Header.Get(PoWNonceHeader))
+			switch {
+			case err == nil:
+				next.ServeHTTP(w, r)
+			case errors.Is(err, pow.ErrMissing):
+				http.Error(w, `{"error":"Proof-of-work required, see GET /api/challenge"}`, http.StatusForbidden)
+			default:
+				http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusForbidden)
+			}
+		})
+	}
+}
+
+// DedupSubmissions rechaza con 409 un envío idéntico a otro aceptado dentro de la ventana.
+// El hash se calcula sobre el JSON normalizado (claves ordenadas) y la ruta.
+func DedupSubmissions(window time.Duration) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	seen := map[string]time.Time{}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fields, err := readJSONBody(w, r)
+			if err != nil {
+				http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+				return
+			}
+			normalized, _ := json.Marshal(fields)
+			sum := sha256.Sum256(append([]byte(r.URL.Path+"\n"), normalized...))
+			hash := hex.EncodeToString(sum[:])
+
+			now := time.Now()
+			mu.Lock()
+			for h, at := range seen {
+				if now.Sub(at) >= window {
+					delete(seen, h)
+				}
+			}
+			_, duplicate := seen[hash]
+			if !duplicate {
+				// Se reserva antes de procesar para que dos envíos simultáneos no pasen ambos
+				seen[hash] = now
+			}
+			mu.Unlock()
+
+			if duplicate {
+				http.Error(w, `{"error":"Duplicate submission"}`, http.StatusConflict)
+				return
+			}
+
+			ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			// Si el envío falló, se puede reintentar
+			if ww.Status() >= 300 {
+				mu.Lock()
+				delete(seen, hash)
+				mu.Unlock()
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHoneypot(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantNext bool
+	}{
+		{"sin campo trampa", `{"nombre":"Acopio"}`, true},
+		{"campo trampa vacío", `{"nombre":"Acopio","website":""}`, true},
+		{"campo trampa null", `{"nombre":"Acopio","website":null}`, true},
+		{"campo trampa lleno", `{"nombre":"Acopio","website":"http://spam.example"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			h := Honeypot(HoneypotField)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusAccepted)
+			}))
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/solicitudes", strings.NewReader(tt.body)))
+			if called != tt.wantNext {
+				t.Errorf("handler llamado = %v, want %v", called, tt.wantNext)
+			}
+			// El bot recibe la misma respuesta que un envío aceptado
+			if rec.Code != http.StatusAccepted {
+				t.Errorf("status = %d, want 202", rec.Code)
+			}
+		})
+	}
+}
+
+func TestDedupSubmissions(t *testing.T) {
+	status := http.StatusAccepted
+	h := DedupSubmissions(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	post := func(path, body string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec.Code
+	}
+
+	tests := []struct {
+		name       string
+		path, body string
+		handler    int // status que responde el handler
+		want       int
+	}{
+		{"primer envío", "/api/solicitudes", `{"a":1,"b":2}`, http.StatusAccepted, http.StatusAccepted},
+		{"mismo envío con otro orden de claves", "/api/solicitudes", `{"b":2,"a":1}`, http.StatusAccepted, http.StatusConflict},
+		{"otro cuerpo", "/api/solicitudes", `{"a":1,"b":3}`, http.StatusAccepted, http.StatusAccepted},
+		{"mismo cuerpo en otra ruta", "/api/puntos", `{"a":1,"b":2}`, http.StatusAccepted, http.StatusAccepted},
+		{"envío que falla", "/api/voluntarios", `{"c":1}`, http.StatusInternalServerError, http.StatusInternalServerError},
+		{"reintento tras el fallo", "/api/voluntarios", `{"c":1}`, http.StatusAccepted, http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		status = tt.handler
+		if got := post(tt.path, tt.body); got != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
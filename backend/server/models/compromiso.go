@@ -0,0 +1,90 @@
+package models
+
+// Estados de un compromiso de donación
+const (
+	CompromisoComprometido = "comprometido" // El donante dijo que lo traerá
+	CompromisoEntregado    = "entregado"    // El punto lo recibió
+	CompromisoCancelado    = "cancelado"    // El donante o el punto lo anuló
+)
+
+// MaxDiasCompromiso es cuántos días hacia adelante se puede comprometer una entrega
+const MaxDiasCompromiso = 60
+
+// Compromiso es la promesa de un donante de llevar algo a un punto ("20 litros de
+// agua el martes"). Categoria e Item usan el formato de Necesidad.
+type Compromiso struct {
+	ID                string   `json:"id"`
+	PuntoID           string   `json:"punto_id"`
+	Categoria         string   `json:"categoria,omitempty"`
+	Item              string   `json:"item,omitempty"`
+	Nombre            string   `json:"nombre"` // Como lo escribió el donante
+	Cantidad          float64  `json:"cantidad"`
+	Unidad            string   `json:"unidad"`
+	FechaEntrega      string   `json:"fecha_entrega"` // YYYY-MM-DD
+	Estado            string   `json:"estado"`
+	CantidadEntregada *float64 `json:"cantidad_entregada,omitempty"`
+	DonanteNombre     string   `json:"donante_nombre,omitempty"`
+	DonanteContacto   string   `json:"donante_contacto,omitempty"`
+	Nota              string   `json:"nota,omitempty"`
+	Created           string   `json:"created,omitempty"`
+	Updated           string   `json:"updated,omitempty"`
+}
+
+type CompromisoCreateRequest struct {
+	Item            string  `json:"item" validate:"required,min=1,max=200" doc:"Lo que se lleva (agua, pañales...) o una categoría (alimentos)"`
+	Categoria       string  `json:"categoria" validate:"enum=alimentos|herramientas|techo_abrigo|animales|medicamentos|olla_comun|otros" doc:"Necesario cuando el punto pide una categoría completa"`
+	Cantidad        float64 `json:"cantidad" validate:"required,min=0"`
+	Unidad          string  `json:"unidad" validate:"required,min=1,max=30" doc:"kg, litros, unidades, cajas..."`
+	FechaEntrega    string  `json:"fecha_entrega" validate:"required" doc:"YYYY-MM-DD, desde hoy y hasta 60 días más"`
+	DonanteNombre   string  `json:"donante_nombre" validate:"max=200"`
+	DonanteContacto string  `json:"donante_contacto" validate:"required,min=1,max=200" doc:"Teléfono o email; solo lo ve el punto"`
+	Nota            string  `json:"nota" validate:"max=500"`
+}
+
+// CompromisoAcceptedResponse es lo que recibe el donante; con el token puede cancelar
+type CompromisoAcceptedResponse struct {
+	ID      string `json:"id"`
+	Token   string `json:"token" doc:"Para cancelar el compromiso; solo se muestra una vez"`
+	Message string `json:"message"`
+}
+
+type CompromisoCancelarRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// CompromisoUpdateRequest cierra un compromiso desde el punto
+type CompromisoUpdateRequest struct {
+	Estado            string   `json:"estado" validate:"required,enum=entregado|cancelado"`
+	CantidadEntregada *float64 `json:"cantidad_entregada,omitempty" validate:"min=0" doc:"Si llegó otra cantidad; por defecto la comprometida"`
+	Nota              *string  `json:"nota,omitempty" validate:"max=500"`
+}
+
+// CompromisoFilter filtra el listado de compromisos de un punto
+type CompromisoFilter struct {
+	PuntoID string
+	Estado  string
+	Vigente bool // Solo comprometidos con fecha de entrega desde hoy
+	Limit   int
+}
+
+// NecesidadCompromisos compara lo comprometido para una necesidad con lo que falta
+type NecesidadCompromisos struct {
+	Necesidad
+	Unidad       string   `json:"unidad,omitempty"`
+	Faltante     *float64 `json:"faltante,omitempty"` // Según el objetivo del inventario
+	Comprometido float64  `json:"comprometido"`
+	Compromisos  int      `json:"compromisos"`
+	PorCubrir    *float64 `json:"por_cubrir,omitempty"` // faltante - comprometido, mínimo 0
+}
+
+// PuntoCompromisosResponse es lo comprometido vs. lo necesitado de un punto
+type PuntoCompromisosResponse struct {
+	PuntoID string                 `json:"punto_id"`
+	Nombre  string                 `json:"nombre"`
+	Data    []NecesidadCompromisos `json:"data"`
+}
+
+type CompromisosResponse struct {
+	Data  []Compromiso `json:"data"`
+	Limit int          `json:"limit"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// PuntoCursor es la posición (created, id) del último punto de una página
+type PuntoCursor struct {
+	Created string `json:"c"`
+	ID      string `json:"i"`
+}
+
+// Encode devuelve el cursor como token opaco para el cliente
+func (c PuntoCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodePuntoCursor interpreta un token generado por PuntoCursor.Encode
+func DecodePuntoCursor(token string) (*PuntoCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var c PuntoCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Created == "" || c.ID == "" {
+		return nil, errors.New("invalid cursor")
+	}
+	return &c, nil
+}
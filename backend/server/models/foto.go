@@ -0,0 +1,27 @@
+package models
+
+// Tipos de foto que se pueden subir a un punto
+const (
+	FotoEvidencia = "evidencia" // Se agrega a evidencia_fotos
+	FotoAsbesto   = "asbesto"   // Reemplaza foto_asbesto
+)
+
+// MaxEvidenciaFotos es el máximo de fotos en evidencia_fotos (igual que la validación de PuntoCreateRequest)
+const MaxEvidenciaFotos = 20
+
+// FotoUploadForm documenta el formulario multipart de subida de fotos
+type FotoUploadForm struct {
+	Foto string `json:"foto" validate:"required" format:"binary" doc:"JPEG o PNG; se reconoce por el contenido"`
+	Tipo string `json:"tipo" validate:"enum=evidencia|asbesto" doc:"evidencia (default) o asbesto"`
+}
+
+// FotoUploadResponse trae las URLs de la foto subida y el punto ya actualizado
+type FotoUploadResponse struct {
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Tipo         string `json:"tipo"`
+	ContentType  string `json:"content_type"`
+	Ancho        int    `json:"ancho"`
+	Alto         int    `json:"alto"`
+	Punto        any    `json:"punto"`
+}
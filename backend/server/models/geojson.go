@@ -0,0 +1,91 @@
+package models
+
+import "encoding/json"
+
+// GeoJSONContentType es el media type registrado para GeoJSON (RFC 7946)
+const GeoJSONContentType = "application/geo+json"
+
+type GeoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+type GeoJSONFeature struct {
+	Type       string          `json:"type"`
+	ID         string          `json:"id,omitempty"`
+	Geometry   GeoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+// GeoJSONFeatureCollection incluye la paginación como miembros extra (permitidos por RFC 7946)
+type GeoJSONFeatureCollection struct {
+	Type       string           `json:"type"`
+	Features   []GeoJSONFeature `json:"features"`
+	Total      *int             `json:"total,omitempty"`
+	Page       int              `json:"page,omitempty"`
+	Limit      int              `json:"limit"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// RutasGeoJSON son las rutas de acceso de un punto leídas de su KML; bbox es
+// [minLng, minLat, maxLng, maxLat] para encuadrar el mapa
+type RutasGeoJSON struct {
+	Type     string           `json:"type"`
+	BBox     []float64        `json:"bbox,omitempty"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// RutasUploadResponse resume el KML/KMZ subido y trae el punto ya actualizado
+type RutasUploadResponse struct {
+	URL        string    `json:"url"`
+	Geometrias int       `json:"geometrias"`
+	Vertices   int       `json:"vertices"`
+	BBox       []float64 `json:"bbox"`
+	Punto      any       `json:"punto"`
+}
+
+// RutasUploadForm documenta el formulario multipart de subida de rutas
+type RutasUploadForm struct {
+	Archivo string `json:"archivo" validate:"required" format:"binary" doc:"KML o KMZ; se reconoce por el contenido"`
+}
+
+// geoJSONOmittedProperties son campos que ya van en geometry/id
+var geoJSONOmittedProperties = []string{"id", "latitud", "longitud"}
+
+// ToGeoJSONFeature convierte el punto en un Feature con geometría Point [lng, lat]
+// y los campos de la vista pública en properties
+func (p *Punto) ToGeoJSONFeature() GeoJSONFeature {
+	properties := map[string]any{}
+	raw, _ := json.Marshal(p.ToPublic())
+	json.Unmarshal(raw, &properties)
+	for _, key := range geoJSONOmittedProperties {
+		delete(properties, key)
+	}
+
+	return GeoJSONFeature{
+		Type: "Feature",
+		ID:   p.ID,
+		Geometry: GeoJSONGeometry{
+			Type:        "Point",
+			Coordinates: []float64{p.Longitud, p.Latitud},
+		},
+		Properties: properties,
+	}
+}
+
+// ToGeoJSON convierte un listado de puntos en FeatureCollection
+func (r *PuntosListResponse) ToGeoJSON() GeoJSONFeatureCollection {
+	features := make([]GeoJSONFeature, len(r.Data))
+	for i := range r.Data {
+		features[i] = r.Data[i].ToGeoJSONFeature()
+	}
+
+	return GeoJSONFeatureCollection{
+		Type:       "FeatureCollection",
+		Features:   features,
+		Total:      r.Total,
+		Page:       r.Page,
+		Limit:      r.Limit,
+		NextCursor: r.NextCursor,
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+// Tipos de movimiento de stock
+const (
+	MovimientoRecibido   = "recibido"   // Entra stock (donaciones, compras)
+	MovimientoDespachado = "despachado" // Sale stock (entregas a zonas, albergues)
+	MovimientoAjuste     = "ajuste"     // Conteo físico: cantidad es el nuevo stock
+)
+
+// InventarioItem es un ítem del inventario de un punto. Categoria es una de las
+// claves de NecesidadesTags (o "otros"); Item es el nombre normalizado, el mismo
+// formato que usa el cruce de necesidades.
+type InventarioItem struct {
+	ID        string   `json:"id"`
+	PuntoID   string   `json:"punto_id"`
+	Item      string   `json:"item"`
+	Nombre    string   `json:"nombre"`
+	Categoria string   `json:"categoria"`
+	Unidad    string   `json:"unidad"`
+	Cantidad  float64  `json:"cantidad"`
+	Objetivo  *float64 `json:"objetivo,omitempty"` // Stock que el punto quiere mantener
+	Faltante  *float64 `json:"faltante,omitempty"` // objetivo - cantidad, si falta
+	Created   string   `json:"created,omitempty"`
+	Updated   string   `json:"updated,omitempty"`
+
+	// Datos del punto, solo en la consulta entre puntos (GET /api/inventario)
+	PuntoNombre string `json:"punto_nombre,omitempty"`
+	Ciudad      string `json:"ciudad,omitempty"`
+}
+
+// Faltante es cuánto le falta a cantidad para llegar al objetivo, o nil si no hay
+// objetivo o ya se cumple
+func Faltante(cantidad float64, objetivo *float64) *float64 {
+	if objetivo == nil || cantidad >= *objetivo {
+		return nil
+	}
+	faltante := *objetivo - cantidad
+	return &faltante
+}
+
+type InventarioItemCreateRequest struct {
+	Nombre    string   `json:"nombre" validate:"required,min=1,max=200"`
+	Categoria string   `json:"categoria" validate:"required,enum=alimentos|herramientas|techo_abrigo|animales|medicamentos|olla_comun|otros"`
+	Unidad    string   `json:"unidad" validate:"required,min=1,max=30" doc:"kg, litros, unidades, cajas..."`
+	Cantidad  float64  `json:"cantidad" validate:"min=0" doc:"Stock inicial; queda registrado como ajuste"`
+	Objetivo  *float64 `json:"objetivo,omitempty" validate:"min=0"`
+}
+
+type InventarioItemUpdateRequest struct {
+	Nombre    *string  `json:"nombre,omitempty" validate:"min=1,max=200"`
+	Categoria *string  `json:"categoria,omitempty" validate:"enum=alimentos|herramientas|techo_abrigo|animales|medicamentos|olla_comun|otros"`
+	Unidad    *string  `json:"unidad,omitempty" validate:"min=1,max=30"`
+	Objetivo  *float64 `json:"objetivo,omitempty" validate:"min=0" doc:"0 quita el objetivo"`
+}
+
+type MovimientoRequest struct {
+	Tipo     string  `json:"tipo" validate:"required,enum=recibido|despachado|ajuste"`
+	Cantidad float64 `json:"cantidad" validate:"required,min=0" doc:"Cantidad movida; en ajuste, el stock contado"`
+	Nota     string  `json:"nota" validate:"max=500"`
+}
+
+// Movimiento es una entrada del historial de stock de un ítem
+type Movimiento struct {
+	ID            string  `json:"id"`
+	ItemID        string  `json:"item_id"`
+	PuntoID       string  `json:"punto_id"`
+	Tipo          string  `json:"tipo"`
+	Cantidad      float64 `json:"cantidad"`
+	Saldo         float64 `json:"saldo"` // Stock después del movimiento
+	Nota          string  `json:"nota,omitempty"`
+	RegistradoPor string  `json:"registrado_por,omitempty"`
+	Created       string  `json:"created"`
+}
+
+// InventarioFilter filtra la consulta de stock entre puntos publicados
+type InventarioFilter struct {
+	Categoria     string
+	Item          string // Nombre normalizado
+	Ciudad        string
+	SoloFaltantes bool
+	Limit         int
+}
+
+// DuplicateInventarioResponse es el 409 al crear un ítem que el punto ya tiene
+type DuplicateInventarioResponse struct {
+	Error  string `json:"error"`
+	ItemID string `json:"item_id"`
+}
+
+type InventarioResponse struct {
+	Data []InventarioItem `json:"data"`
+}
+
+type MovimientosResponse struct {
+	Data  []Movimiento `json:"data"`
+	Limit int          `json:"limit"`
+}
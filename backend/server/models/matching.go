@@ -0,0 +1,49 @@
+package models
+
+// Necesidad es una necesidad de una zona (o un recurso de un acopio) normalizada
+// para el matching. Item vacío significa la categoría completa ("alimentos").
+type Necesidad struct {
+	Categoria string `json:"categoria,omitempty"`
+	Item      string `json:"item,omitempty"`
+}
+
+// AcopioSugerido es un acopio cercano que puede cubrir una necesidad
+type AcopioSugerido struct {
+	ID                string  `json:"id"`
+	Nombre            string  `json:"nombre"`
+	Direccion         string  `json:"direccion"`
+	Ciudad            string  `json:"ciudad"`
+	Latitud           float64 `json:"latitud"`
+	Longitud          float64 `json:"longitud"`
+	ContactoPrincipal string  `json:"contacto_principal"`
+	Horario           string  `json:"horario"`
+	DistanciaM        float64 `json:"distancia_m"`
+	// Exacto es false cuando el acopio tiene stock de la categoría pero no del ítem pedido
+	Exacto bool `json:"exacto"`
+}
+
+// NecesidadMatch lista los acopios más cercanos que cubren una necesidad
+type NecesidadMatch struct {
+	Necesidad
+	Acopios []AcopioSugerido `json:"acopios"`
+}
+
+// PuntoMatches son las sugerencias de acopios para las necesidades de un punto
+type PuntoMatches struct {
+	PuntoID     string           `json:"punto_id"`
+	Nombre      string           `json:"nombre"`
+	Categoria   string           `json:"categoria"`
+	Ciudad      string           `json:"ciudad"`
+	NombreZona  string           `json:"nombre_zona,omitempty"`
+	Latitud     float64          `json:"latitud"`
+	Longitud    float64          `json:"longitud"`
+	Necesidades []NecesidadMatch `json:"necesidades"`
+	Total       int              `json:"total"`     // Necesidades del punto
+	Cubiertas   int              `json:"cubiertas"` // Necesidades con al menos un acopio
+}
+
+// MatchesResponse es la respuesta de GET /api/matches
+type MatchesResponse struct {
+	MaxDistanceM float64        `json:"max_distance_m"`
+	Data         []PuntoMatches `json:"data"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// OcupacionRequest es un reporte de ocupación de un albergue. Si trae
+// capacidad_total, también corrige las camas declaradas.
+type OcupacionRequest struct {
+	Ocupacion      int    `json:"ocupacion" validate:"required,min=0,max=100000" doc:"Personas alojadas en este momento"`
+	CapacidadTotal *int   `json:"capacidad_total,omitempty" validate:"min=0,max=100000"`
+	Nota           string `json:"nota" validate:"max=500"`
+}
+
+// OcupacionRegistro es una entrada del historial de ocupación de un albergue
+type OcupacionRegistro struct {
+	ID             string `json:"id"`
+	PuntoID        string `json:"punto_id"`
+	Ocupacion      int    `json:"ocupacion"`
+	CapacidadTotal int    `json:"capacidad_total"`
+	Nota           string `json:"nota,omitempty"`
+	ReportadoPor   string `json:"reportado_por,omitempty"`
+	Created        string `json:"created"`
+}
+
+// OcupacionHistorialResponse trae los reportes de un albergue, del más reciente al más antiguo
+type OcupacionHistorialResponse struct {
+	Data  []OcupacionRegistro `json:"data"`
+	Limit int                 `json:"limit"`
+}
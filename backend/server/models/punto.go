@@ -1,5 +1,10 @@
 package models
 
+import (
+	"strings"
+	"time"
+)
+
 // NecesidadesTags estructura compleja para necesidades detalladas
 type NecesidadesTags struct {
 	Medicamentos string   `json:"medicamentos,omitempty"`
@@ -24,11 +29,16 @@ type Punto struct {
 	ContactoPrincipal    string   `json:"contacto_principal"`
 	ContactoNombre       string   `json:"contacto_nombre"`
 	Horario              string   `json:"horario"`
+	HorarioOSM           string   `json:"horario_osm,omitempty"` // opening_hours de OSM ("Mo-Fr 09:00-18:00")
 	Estado               string   `json:"estado"`
 	EntidadVerificadora  string   `json:"entidad_verificadora"`
 	FechaVerificacion    string   `json:"fecha_verificacion,omitempty"`
 	NotasInternas        string   `json:"notas_internas,omitempty"`
 	CapacidadEstado      string   `json:"capacidad_estado,omitempty"`
+	CapacidadTotal       int      `json:"capacidad_total,omitempty"`       // Camas declaradas (albergues)
+	OcupacionActual      *int     `json:"ocupacion_actual,omitempty"`      // nil = sin reporte de ocupación
+	OcupacionActualizada string   `json:"ocupacion_actualizada,omitempty"` // Fecha del último reporte
+	CamasDisponibles     *int     `json:"camas_disponibles,omitempty"`     // Calculado, ver CamasDisponibles
 	NecesidadesRaw       string   `json:"necesidades_raw,omitempty"`
 	NecesidadesTags      any      `json:"necesidades_tags,omitempty"` // Puede ser []string o NecesidadesTags
 	NombreZona           string   `json:"nombre_zona,omitempty"`
@@ -43,6 +53,8 @@ type Punto struct {
 	LogisticaLlegada     string   `json:"logistica_llegada,omitempty"`
 	TiposAcceso          []string `json:"tipos_acceso,omitempty"`
 	RequiereVoluntarios  bool     `json:"requiere_voluntarios"`
+	CupoVoluntarios      int      `json:"cupo_voluntarios,omitempty"`      // Cuántos voluntarios pide el punto
+	VoluntariosAsignados int      `json:"voluntarios_asignados,omitempty"` // Calculado desde voluntario_asignaciones
 	TieneBanos           bool     `json:"tiene_banos"`
 	TieneElectricidad    bool     `json:"tiene_electricidad"`
 	TieneSenal           bool     `json:"tiene_senal"`
@@ -52,86 +64,170 @@ type Punto struct {
 	Created              string   `json:"created,omitempty"`
 	Updated              string   `json:"updated,omitempty"`
 	CreatedBy            string   `json:"created_by,omitempty"`
+
+	// Si alguna vez estuvo publicado (publicado_en); decide si el público recibe su baja
+	FuePublicado bool `json:"-"`
+
+	// Distancia en metros al punto de referencia (solo cuando se consulta con near)
+	DistanciaM *float64 `json:"distancia_m,omitempty"`
+}
+
+// CamasDisponibles es capacidad - ocupacion (nunca negativo), o nil si el albergue
+// no declara capacidad o nadie ha reportado su ocupación
+func CamasDisponibles(capacidad int, ocupacion *int) *int {
+	if capacidad <= 0 || ocupacion == nil {
+		return nil
+	}
+	camas := max(capacidad-*ocupacion, 0)
+	return &camas
 }
 
 type PuntoCreateRequest struct {
-	Nombre               string   `json:"nombre"`
-	Latitud              float64  `json:"latitud"`
-	Longitud             float64  `json:"longitud"`
-	Direccion            string   `json:"direccion"`
-	Ciudad               string   `json:"ciudad"`
-	Categoria            string   `json:"categoria"`
-	Subtipo              string   `json:"subtipo"`
-	CategoriasAyuda      []string `json:"categorias_ayuda"`
-	NivelUrgencia        string   `json:"nivel_urgencia"`
-	ContactoPrincipal    string   `json:"contacto_principal"`
-	ContactoNombre       string   `json:"contacto_nombre"`
-	Horario              string   `json:"horario"`
+	Nombre               string   `json:"nombre" validate:"required,min=1,max=300"`
+	Latitud              float64  `json:"latitud" validate:"required,min=-90,max=90"`
+	Longitud             float64  `json:"longitud" validate:"required,min=-180,max=180"`
+	Direccion            string   `json:"direccion" validate:"max=300"`
+	Ciudad               string   `json:"ciudad" validate:"max=100"`
+	Categoria            string   `json:"categoria" validate:"required,enum=acopio|albergue|hidratacion|sos"`
+	Subtipo              string   `json:"subtipo" validate:"max=100"`
+	CategoriasAyuda      []string `json:"categorias_ayuda" validate:"max=30"`
+	NivelUrgencia        string   `json:"nivel_urgencia" validate:"enum=critico|alto|medio|bajo"`
+	ContactoPrincipal    string   `json:"contacto_principal" validate:"max=200"`
+	ContactoNombre       string   `json:"contacto_nombre" validate:"max=200"`
+	Horario              string   `json:"horario" validate:"max=300"`
+	HorarioOSM           string   `json:"horario_osm" validate:"max=500" doc:"Horario en formato opening_hours de OSM; si se omite se deriva de horario"`
 	Estado               string   `json:"estado"`
-	EntidadVerificadora  string   `json:"entidad_verificadora"`
-	CapacidadEstado      string   `json:"capacidad_estado"`
-	NecesidadesRaw       string   `json:"necesidades_raw"`
+	EntidadVerificadora  string   `json:"entidad_verificadora" validate:"max=200"`
+	CapacidadEstado      string   `json:"capacidad_estado" validate:"max=100"`
+	CapacidadTotal       int      `json:"capacidad_total" validate:"min=0,max=100000" doc:"Camas declaradas del albergue; la ocupación se reporta en /ocupacion"`
+	NecesidadesRaw       string   `json:"necesidades_raw" validate:"max=5000"`
 	NecesidadesTags      any      `json:"necesidades_tags"`
-	NombreZona           string   `json:"nombre_zona"`
+	NombreZona           string   `json:"nombre_zona" validate:"max=300"`
 	HabitadoActualmente  bool     `json:"habitado_actualmente"`
-	CantidadNinos        int      `json:"cantidad_ninos"`
-	CantidadAdolescentes int      `json:"cantidad_adolescentes"`
-	CantidadAdultos      int      `json:"cantidad_adultos"`
-	CantidadAncianos     int      `json:"cantidad_ancianos"`
-	AnimalesDetalle      string   `json:"animales_detalle"`
-	RiesgoAsbesto        string   `json:"riesgo_asbesto"`
-	FotoAsbesto          string   `json:"foto_asbesto"`
-	LogisticaLlegada     string   `json:"logistica_llegada"`
-	TiposAcceso          []string `json:"tipos_acceso"`
+	CantidadNinos        int      `json:"cantidad_ninos" validate:"min=0"`
+	CantidadAdolescentes int      `json:"cantidad_adolescentes" validate:"min=0"`
+	CantidadAdultos      int      `json:"cantidad_adultos" validate:"min=0"`
+	CantidadAncianos     int      `json:"cantidad_ancianos" validate:"min=0"`
+	AnimalesDetalle      string   `json:"animales_detalle" validate:"max=3000"`
+	RiesgoAsbesto        string   `json:"riesgo_asbesto" validate:"enum=si|no|no_se"`
+	FotoAsbesto          string   `json:"foto_asbesto" validate:"max=500"`
+	LogisticaLlegada     string   `json:"logistica_llegada" validate:"max=3000"`
+	TiposAcceso          []string `json:"tipos_acceso" validate:"max=20"`
 	RequiereVoluntarios  bool     `json:"requiere_voluntarios"`
+	CupoVoluntarios      int      `json:"cupo_voluntarios" validate:"min=0,max=10000" doc:"Cuántos voluntarios necesita el punto; se asignan en /voluntarios"`
 	TieneBanos           bool     `json:"tiene_banos"`
 	TieneElectricidad    bool     `json:"tiene_electricidad"`
 	TieneSenal           bool     `json:"tiene_senal"`
 	FallecidosReportados bool     `json:"fallecidos_reportados"`
-	EvidenciaFotos       []string `json:"evidencia_fotos"`
-	ArchivoKML           string   `json:"archivo_kml"`
+	EvidenciaFotos       []string `json:"evidencia_fotos" validate:"max=20"`
+	ArchivoKML           string   `json:"archivo_kml" validate:"max=500"`
 }
 
 type PuntoUpdateRequest struct {
-	Nombre               *string   `json:"nombre,omitempty"`
-	Latitud              *float64  `json:"latitud,omitempty"`
-	Longitud             *float64  `json:"longitud,omitempty"`
-	Direccion            *string   `json:"direccion,omitempty"`
-	Ciudad               *string   `json:"ciudad,omitempty"`
-	Categoria            *string   `json:"categoria,omitempty"`
-	Subtipo              *string   `json:"subtipo,omitempty"`
-	CategoriasAyuda      *[]string `json:"categorias_ayuda,omitempty"`
-	NivelUrgencia        *string   `json:"nivel_urgencia,omitempty"`
-	ContactoPrincipal    *string   `json:"contacto_principal,omitempty"`
-	ContactoNombre       *string   `json:"contacto_nombre,omitempty"`
-	Horario              *string   `json:"horario,omitempty"`
+	Nombre               *string   `json:"nombre,omitempty" validate:"min=1,max=300"`
+	Latitud              *float64  `json:"latitud,omitempty" validate:"min=-90,max=90"`
+	Longitud             *float64  `json:"longitud,omitempty" validate:"min=-180,max=180"`
+	Direccion            *string   `json:"direccion,omitempty" validate:"max=300"`
+	Ciudad               *string   `json:"ciudad,omitempty" validate:"max=100"`
+	Categoria            *string   `json:"categoria,omitempty" validate:"enum=acopio|albergue|hidratacion|sos"`
+	Subtipo              *string   `json:"subtipo,omitempty" validate:"max=100"`
+	CategoriasAyuda      *[]string `json:"categorias_ayuda,omitempty" validate:"max=30"`
+	NivelUrgencia        *string   `json:"nivel_urgencia,omitempty" validate:"enum=critico|alto|medio|bajo"`
+	ContactoPrincipal    *string   `json:"contacto_principal,omitempty" validate:"max=200"`
+	ContactoNombre       *string   `json:"contacto_nombre,omitempty" validate:"max=200"`
+	Horario              *string   `json:"horario,omitempty" validate:"max=300"`
+	HorarioOSM           *string   `json:"horario_osm,omitempty" validate:"max=500" doc:"Horario en formato opening_hours de OSM; vacío lo vuelve a derivar de horario"`
 	Estado               *string   `json:"estado,omitempty"`
-	EntidadVerificadora  *string   `json:"entidad_verificadora,omitempty"`
-	NotasInternas        *string   `json:"notas_internas,omitempty"`
-	CapacidadEstado      *string   `json:"capacidad_estado,omitempty"`
-	NecesidadesRaw       *string   `json:"necesidades_raw,omitempty"`
+	EntidadVerificadora  *string   `json:"entidad_verificadora,omitempty" validate:"max=200"`
+	NotasInternas        *string   `json:"notas_internas,omitempty" validate:"max=5000"`
+	CapacidadEstado      *string   `json:"capacidad_estado,omitempty" validate:"max=100"`
+	CapacidadTotal       *int      `json:"capacidad_total,omitempty" validate:"min=0,max=100000"`
+	NecesidadesRaw       *string   `json:"necesidades_raw,omitempty" validate:"max=5000"`
 	NecesidadesTags      any       `json:"necesidades_tags,omitempty"`
-	AnimalesDetalle      *string   `json:"animales_detalle,omitempty"`
-	RiesgoAsbesto        *string   `json:"riesgo_asbesto,omitempty"`
-	FotoAsbesto          *string   `json:"foto_asbesto,omitempty"`
-	LogisticaLlegada     *string   `json:"logistica_llegada,omitempty"`
-	TiposAcceso          *[]string `json:"tipos_acceso,omitempty"`
+	AnimalesDetalle      *string   `json:"animales_detalle,omitempty" validate:"max=3000"`
+	RiesgoAsbesto        *string   `json:"riesgo_asbesto,omitempty" validate:"enum=si|no|no_se"`
+	FotoAsbesto          *string   `json:"foto_asbesto,omitempty" validate:"max=500"`
+	LogisticaLlegada     *string   `json:"logistica_llegada,omitempty" validate:"max=3000"`
+	TiposAcceso          *[]string `json:"tipos_acceso,omitempty" validate:"max=20"`
+	CupoVoluntarios      *int      `json:"cupo_voluntarios,omitempty" validate:"min=0,max=10000"`
 	TieneBanos           *bool     `json:"tiene_banos,omitempty"`
 	TieneElectricidad    *bool     `json:"tiene_electricidad,omitempty"`
 	TieneSenal           *bool     `json:"tiene_senal,omitempty"`
 	FallecidosReportados *bool     `json:"fallecidos_reportados,omitempty"`
-	ArchivoKML           *string   `json:"archivo_kml,omitempty"`
+	ArchivoKML           *string   `json:"archivo_kml,omitempty" validate:"max=500"`
 }
 
 type EstadoUpdateRequest struct {
-	Estado string `json:"estado"`
+	Estado string `json:"estado" validate:"required,enum=activo|pendiente|inactivo|cerrado"`
+}
+
+// BBox es un rectángulo geográfico en grados (minLng,minLat,maxLng,maxLat)
+type BBox struct {
+	MinLng float64
+	MinLat float64
+	MaxLng float64
+	MaxLat float64
+}
+
+// GeoPoint es una coordenada de referencia para búsquedas por radio
+type GeoPoint struct {
+	Lat float64
+	Lng float64
 }
 
+// PuntoFilter agrupa los filtros de los listados de puntos
+type PuntoFilter struct {
+	Categoria string
+	Subtipo   string
+	Ciudad    string
+	Estado    string
+
+	// Filtros geográficos
+	BBox    *BBox
+	Near    *GeoPoint
+	RadiusM float64
+
+	// Búsqueda de texto (acentos y plurales indistintos)
+	Q string
+
+	// Filtros sobre arrays JSONB y valores múltiples
+	CategoriasAyuda      []string
+	CategoriasAyudaMatch string // "any" (default), "all" u "only"
+	TiposAcceso          []string
+	TiposAccesoMatch     string // "any" (default), "all" u "only"
+	NivelesUrgencia      []string
+	RiesgoAsbesto        []string
+
+	// Solo albergues con al menos esa cantidad de camas disponibles (0 = sin filtrar)
+	MinCamas int
+
+	// Solo puntos con horario estructurado abiertos en ese instante (hora de Santiago)
+	OpenAt *time.Time
+
+	// Flags operacionales (nil = sin filtrar)
+	RequiereVoluntarios *bool
+	HabitadoActualmente *bool
+	TieneBanos          *bool
+	TieneElectricidad   *bool
+	TieneSenal          *bool
+
+	// Orden: "created" (default), "distance" (requiere Near) o "relevance" (requiere Q)
+	Sort string
+
+	// Paginación: keyset si UseCursor, o page/limit (modo legacy)
+	UseCursor bool
+	Cursor    *PuntoCursor
+	Page      int
+	Limit     int
+}
+
+// PuntosListResponse trae total/page en modo page/limit y next_cursor en modo cursor
 type PuntosListResponse struct {
-	Data  []Punto `json:"data"`
-	Total int     `json:"total"`
-	Page  int     `json:"page"`
-	Limit int     `json:"limit"`
+	Data       []Punto `json:"data"`
+	Total      *int    `json:"total,omitempty"`
+	Page       int     `json:"page,omitempty"`
+	Limit      int     `json:"limit"`
+	NextCursor string  `json:"next_cursor,omitempty"`
 }
 
 // CSVImportRequest para importación de CSV
@@ -144,3 +240,70 @@ type CSVImportResponse struct {
 	Skipped  int      `json:"skipped"`
 	Errors   []string `json:"errors,omitempty"`
 }
+
+// nivelesUrgencia en orden creciente de gravedad
+var nivelesUrgencia = []string{"bajo", "medio", "alto", "critico"}
+
+// nivelesUrgenciaLegacy son los valores en femenino que usaba el panel admin
+var nivelesUrgenciaLegacy = map[string]string{
+	"critica": "critico",
+	"alta":    "alto",
+	"media":   "medio",
+	"baja":    "bajo",
+}
+
+// NormalizeNivelUrgencia lleva un nivel a critico, alto, medio o bajo (acepta
+// mayúsculas y los valores antiguos en femenino). ok es false si no se reconoce.
+func NormalizeNivelUrgencia(nivel string) (string, bool) {
+	nivel = strings.ToLower(strings.TrimSpace(nivel))
+	if legacy, ok := nivelesUrgenciaLegacy[nivel]; ok {
+		nivel = legacy
+	}
+	if nivel != "" && NivelUrgenciaRank(nivel) == 0 {
+		return "", false
+	}
+	return nivel, true
+}
+
+// NivelUrgenciaRank devuelve la gravedad de un nivel (0 si no se reconoce)
+func NivelUrgenciaRank(nivel string) int {
+	for i, n := range nivelesUrgencia {
+		if n == nivel {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// PuntoCluster agrupa puntos cercanos para niveles de zoom bajos
+type PuntoCluster struct {
+	Latitud          float64        `json:"latitud"`
+	Longitud         float64        `json:"longitud"`
+	Count            int            `json:"count"`
+	Categorias       map[string]int `json:"categorias"`
+	MaxNivelUrgencia string         `json:"max_nivel_urgencia,omitempty"`
+	BBox             [4]float64     `json:"bbox"`               // minLng,minLat,maxLng,maxLat de los puntos del cluster
+	PuntoID          string         `json:"punto_id,omitempty"` // Solo si el cluster tiene un único punto
+}
+
+// ClustersResult trae clusters o, si hay pocos puntos en el bbox, los puntos
+// individuales completos; se responde con ToPublic
+type ClustersResult struct {
+	Zoom      int
+	Total     int
+	Clustered bool
+	Clusters  []PuntoCluster
+	Puntos    []Punto
+}
+
+// PuntoResumen tiene los campos mínimos de un punto para dibujarlo en tiles
+type PuntoResumen struct {
+	ID            string
+	Nombre        string
+	Latitud       float64
+	Longitud      float64
+	Categoria     string
+	Subtipo       string
+	NivelUrgencia string
+	Estado        string
+}
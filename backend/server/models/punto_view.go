@@ -0,0 +1,196 @@
+package models
+
+// Vistas de un punto según quién lo consulta:
+//
+//	anónimo, encargado    -> PuntoPublic
+//	verificador           -> PuntoVerificador (+ notas_internas, created_by)
+//	admin, superadmin     -> Punto completo (+ fallecidos_reportados)
+//
+// PuntoPublic es una lista explícita de campos: un campo nuevo en Punto no se
+// publica hasta agregarlo aquí (punto_view_test.go obliga a clasificarlo).
+
+// PuntoPublic es la vista de un punto para el público anónimo
+type PuntoPublic struct {
+	ID                   string   `json:"id"`
+	Nombre               string   `json:"nombre"`
+	Latitud              float64  `json:"latitud"`
+	Longitud             float64  `json:"longitud"`
+	Direccion            string   `json:"direccion"`
+	Ciudad               string   `json:"ciudad"`
+	Categoria            string   `json:"categoria"`
+	Subtipo              string   `json:"subtipo"`
+	CategoriasAyuda      []string `json:"categorias_ayuda,omitempty"`
+	NivelUrgencia        string   `json:"nivel_urgencia,omitempty"`
+	ContactoPrincipal    string   `json:"contacto_principal"`
+	ContactoNombre       string   `json:"contacto_nombre"`
+	Horario              string   `json:"horario"`
+	HorarioOSM           string   `json:"horario_osm,omitempty"`
+	Estado               string   `json:"estado"`
+	EntidadVerificadora  string   `json:"entidad_verificadora"`
+	FechaVerificacion    string   `json:"fecha_verificacion,omitempty"`
+	CapacidadEstado      string   `json:"capacidad_estado,omitempty"`
+	CapacidadTotal       int      `json:"capacidad_total,omitempty"`
+	OcupacionActual      *int     `json:"ocupacion_actual,omitempty"`
+	OcupacionActualizada string   `json:"ocupacion_actualizada,omitempty"`
+	CamasDisponibles     *int     `json:"camas_disponibles,omitempty"`
+	NecesidadesRaw       string   `json:"necesidades_raw,omitempty"`
+	NecesidadesTags      any      `json:"necesidades_tags,omitempty"`
+	NombreZona           string   `json:"nombre_zona,omitempty"`
+	HabitadoActualmente  bool     `json:"habitado_actualmente"`
+	CantidadNinos        int      `json:"cantidad_ninos"`
+	CantidadAdolescentes int      `json:"cantidad_adolescentes"`
+	CantidadAdultos      int      `json:"cantidad_adultos"`
+	CantidadAncianos     int      `json:"cantidad_ancianos"`
+	AnimalesDetalle      string   `json:"animales_detalle,omitempty"`
+	RiesgoAsbesto        string   `json:"riesgo_asbesto,omitempty"`
+	FotoAsbesto          string   `json:"foto_asbesto,omitempty"`
+	LogisticaLlegada     string   `json:"logistica_llegada,omitempty"`
+	TiposAcceso          []string `json:"tipos_acceso,omitempty"`
+	RequiereVoluntarios  bool     `json:"requiere_voluntarios"`
+	CupoVoluntarios      int      `json:"cupo_voluntarios,omitempty"`
+	VoluntariosAsignados int      `json:"voluntarios_asignados,omitempty"`
+	TieneBanos           bool     `json:"tiene_banos"`
+	TieneElectricidad    bool     `json:"tiene_electricidad"`
+	TieneSenal           bool     `json:"tiene_senal"`
+	EvidenciaFotos       []string `json:"evidencia_fotos,omitempty"`
+	ArchivoKML           string   `json:"archivo_kml,omitempty"`
+	Created              string   `json:"created,omitempty"`
+	Updated              string   `json:"updated,omitempty"`
+	DistanciaM           *float64 `json:"distancia_m,omitempty"`
+}
+
+// PuntoVerificador agrega a la vista pública los campos de trabajo interno
+type PuntoVerificador struct {
+	PuntoPublic
+	NotasInternas string `json:"notas_internas,omitempty"`
+	CreatedBy     string `json:"created_by,omitempty"`
+}
+
+func (p *Punto) ToPublic() PuntoPublic {
+	return PuntoPublic{
+		ID:                   p.ID,
+		Nombre:               p.Nombre,
+		Latitud:              p.Latitud,
+		Longitud:             p.Longitud,
+		Direccion:            p.Direccion,
+		Ciudad:               p.Ciudad,
+		Categoria:            p.Categoria,
+		Subtipo:              p.Subtipo,
+		CategoriasAyuda:      p.CategoriasAyuda,
+		NivelUrgencia:        p.NivelUrgencia,
+		ContactoPrincipal:    p.ContactoPrincipal,
+		ContactoNombre:       p.ContactoNombre,
+		Horario:              p.Horario,
+		HorarioOSM:           p.HorarioOSM,
+		Estado:               p.Estado,
+		EntidadVerificadora:  p.EntidadVerificadora,
+		FechaVerificacion:    p.FechaVerificacion,
+		CapacidadEstado:      p.CapacidadEstado,
+		CapacidadTotal:       p.CapacidadTotal,
+		OcupacionActual:      p.OcupacionActual,
+		OcupacionActualizada: p.OcupacionActualizada,
+		CamasDisponibles:     p.CamasDisponibles,
+		NecesidadesRaw:       p.NecesidadesRaw,
+		NecesidadesTags:      p.NecesidadesTags,
+		NombreZona:           p.NombreZona,
+		HabitadoActualmente:  p.HabitadoActualmente,
+		CantidadNinos:        p.CantidadNinos,
+		CantidadAdolescentes: p.CantidadAdolescentes,
+		CantidadAdultos:      p.CantidadAdultos,
+		CantidadAncianos:     p.CantidadAncianos,
+		AnimalesDetalle:      p.AnimalesDetalle,
+		RiesgoAsbesto:        p.RiesgoAsbesto,
+		FotoAsbesto:          p.FotoAsbesto,
+		LogisticaLlegada:     p.LogisticaLlegada,
+		TiposAcceso:          p.TiposAcceso,
+		RequiereVoluntarios:  p.RequiereVoluntarios,
+		CupoVoluntarios:      p.CupoVoluntarios,
+		VoluntariosAsignados: p.VoluntariosAsignados,
+		TieneBanos:           p.TieneBanos,
+		TieneElectricidad:    p.TieneElectricidad,
+		TieneSenal:           p.TieneSenal,
+		EvidenciaFotos:       p.EvidenciaFotos,
+		ArchivoKML:           p.ArchivoKML,
+		Created:              p.Created,
+		Updated:              p.Updated,
+		DistanciaM:           p.DistanciaM,
+	}
+}
+
+func (p *Punto) ToVerificador() PuntoVerificador {
+	return PuntoVerificador{
+		PuntoPublic:   p.ToPublic(),
+		NotasInternas: p.NotasInternas,
+		CreatedBy:     p.CreatedBy,
+	}
+}
+
+// ForRole devuelve la vista del punto que corresponde al rol ("" para anónimo).
+// Un rol desconocido recibe la vista pública.
+func (p *Punto) ForRole(rol string) any {
+	switch rol {
+	case "admin", "superadmin":
+		return p
+	case "verificador":
+		return p.ToVerificador()
+	default:
+		return p.ToPublic()
+	}
+}
+
+// PuntosListView es PuntosListResponse con cada punto proyectado según el rol
+type PuntosListView struct {
+	Data       []any  `json:"data"`
+	Total      *int   `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func (r *PuntosListResponse) ForRole(rol string) PuntosListView {
+	data := make([]any, len(r.Data))
+	for i := range r.Data {
+		data[i] = r.Data[i].ForRole(rol)
+	}
+
+	return PuntosListView{
+		Data:       data,
+		Total:      r.Total,
+		Page:       r.Page,
+		Limit:      r.Limit,
+		NextCursor: r.NextCursor,
+	}
+}
+
+// ClustersResponse es ClustersResult con los puntos individuales en la vista pública
+type ClustersResponse struct {
+	Zoom      int            `json:"zoom"`
+	Total     int            `json:"total"`
+	Clustered bool           `json:"clustered"`
+	Clusters  []PuntoCluster `json:"clusters,omitempty"`
+	Puntos    []PuntoPublic  `json:"puntos,omitempty"`
+}
+
+func (r *ClustersResult) ToPublic() ClustersResponse {
+	var puntos []PuntoPublic
+	for i := range r.Puntos {
+		puntos = append(puntos, r.Puntos[i].ToPublic())
+	}
+
+	return ClustersResponse{
+		Zoom:      r.Zoom,
+		Total:     r.Total,
+		Clustered: r.Clustered,
+		Clusters:  r.Clusters,
+		Puntos:    puntos,
+	}
+}
+
+// PuntoChangesResponse es la respuesta de sincronización incremental (vista pública)
+type PuntoChangesResponse struct {
+	Created   []PuntoPublic `json:"created"`
+	Updated   []PuntoPublic `json:"updated"`
+	Removed   []string      `json:"removed"`
+	NextToken string        `json:"next_token"`
+	HasMore   bool          `json:"has_more"`
+}
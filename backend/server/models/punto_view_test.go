@@ -0,0 +1,150 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// privateFields son los campos de Punto que no deben salir en la vista pública
+var privateFields = []string{"notas_internas", "fallecidos_reportados", "created_by"}
+
+func fullPunto() *Punto {
+	distancia := 120.0
+	return &Punto{
+		ID:                   "pnt_1",
+		Nombre:               "Albergue Escuela Básica",
+		Latitud:              -36.82,
+		Longitud:             -73.05,
+		Categoria:            "albergue",
+		Estado:               "publicado",
+		ContactoPrincipal:    "+56911111111",
+		NotasInternas:        "Encargado pidió no publicar su segundo teléfono",
+		FallecidosReportados: true,
+		CreatedBy:            "usr_1",
+		NecesidadesTags:      NecesidadesTags{Alimentos: []string{"agua"}},
+		DistanciaM:           &distancia,
+	}
+}
+
+func jsonKeys(t *testing.T, v any) map[string]bool {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	keys := map[string]any{}
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	set := map[string]bool{}
+	for k := range keys {
+		set[k] = true
+	}
+	return set
+}
+
+func TestPuntoForRole(t *testing.T) {
+	tests := []struct {
+		rol     string
+		visible []string
+		hidden  []string
+	}{
+		{rol: "", hidden: []string{"notas_internas", "fallecidos_reportados", "created_by"}},
+		{rol: "desconocido", hidden: []string{"notas_internas", "fallecidos_reportados", "created_by"}},
+		{rol: "verificador", visible: []string{"notas_internas", "created_by"}, hidden: []string{"fallecidos_reportados"}},
+		{rol: "admin", visible: []string{"notas_internas", "created_by", "fallecidos_reportados"}},
+		{rol: "superadmin", visible: []string{"notas_internas", "created_by", "fallecidos_reportados"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("rol="+tt.rol, func(t *testing.T) {
+			keys := jsonKeys(t, fullPunto().ForRole(tt.rol))
+
+			for _, k := range []string{"id", "nombre", "latitud", "contacto_principal", "distancia_m"} {
+				if !keys[k] {
+					t.Errorf("campo público %q ausente", k)
+				}
+			}
+			for _, k := range tt.visible {
+				if !keys[k] {
+					t.Errorf("campo %q debería ser visible", k)
+				}
+			}
+			for _, k := range tt.hidden {
+				if keys[k] {
+					t.Errorf("campo %q no debería ser visible", k)
+				}
+			}
+		})
+	}
+}
+
+// Todo campo de Punto debe estar en PuntoPublic o en privateFields, para que un
+// campo nuevo no quede fuera (o expuesto) sin una decisión explícita.
+func TestPuntoPublicCoversPunto(t *testing.T) {
+	public := map[string]bool{}
+	publicType := reflect.TypeOf(PuntoPublic{})
+	for i := 0; i < publicType.NumField(); i++ {
+		public[jsonName(publicType.Field(i))] = true
+	}
+
+	private := map[string]bool{}
+	for _, name := range privateFields {
+		private[name] = true
+		if public[name] {
+			t.Errorf("campo privado %q está en PuntoPublic", name)
+		}
+	}
+
+	puntoType := reflect.TypeOf(Punto{})
+	for i := 0; i < puntoType.NumField(); i++ {
+		name := jsonName(puntoType.Field(i))
+		if name == "-" {
+			continue // No sale en ninguna respuesta
+		}
+		if !public[name] && !private[name] {
+			t.Errorf("campo %q de Punto no está clasificado como público ni privado", name)
+		}
+	}
+}
+
+func TestPuntosListResponseForRole(t *testing.T) {
+	total := 1
+	list := &PuntosListResponse{Data: []Punto{*fullPunto()}, Total: &total, Page: 1, Limit: 50}
+
+	raw, _ := json.Marshal(list.ForRole(""))
+	for _, name := range privateFields {
+		if strings.Contains(string(raw), `"`+name+`"`) {
+			t.Errorf("listado público contiene %q", name)
+		}
+	}
+}
+
+func TestGeoJSONFeatureIsPublic(t *testing.T) {
+	feature := fullPunto().ToGeoJSONFeature()
+	for _, name := range privateFields {
+		if _, ok := feature.Properties[name]; ok {
+			t.Errorf("properties contiene %q", name)
+		}
+	}
+}
+
+func TestClustersResponseIsPublic(t *testing.T) {
+	result := &ClustersResult{Zoom: 14, Total: 1, Puntos: []Punto{*fullPunto()}}
+
+	raw, _ := json.Marshal(result.ToPublic())
+	if !strings.Contains(string(raw), `"pnt_1"`) {
+		t.Fatalf("faltan los puntos: %s", raw)
+	}
+	for _, name := range privateFields {
+		if strings.Contains(string(raw), `"`+name+`"`) {
+			t.Errorf("clusters sin agrupar contiene %q", name)
+		}
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	return strings.Split(f.Tag.Get("json"), ",")[0]
+}
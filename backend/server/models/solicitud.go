@@ -0,0 +1,202 @@
+package models
+
+import "strings"
+
+// Estados de moderación de una solicitud pública
+const (
+	SolicitudPendiente = "pendiente"
+	SolicitudAprobada  = "aprobada"
+	SolicitudRechazada = "rechazada"
+)
+
+// CategoriasPublicas son las categorías que el público puede proponer
+var CategoriasPublicas = []string{"sos", "acopio", "albergue", "hidratacion"}
+
+// PuntoPublicoRequest son los campos de un punto que cualquier persona puede proponer.
+// Estado, verificación, notas internas y datos sensibles los completa un verificador.
+type PuntoPublicoRequest struct {
+	Nombre               string   `json:"nombre" validate:"required,min=1,max=300"`
+	Latitud              float64  `json:"latitud" validate:"required,min=-90,max=90"`
+	Longitud             float64  `json:"longitud" validate:"required,min=-180,max=180"`
+	Direccion            string   `json:"direccion" validate:"max=300"`
+	Ciudad               string   `json:"ciudad" validate:"max=100"`
+	Categoria            string   `json:"categoria" validate:"required,enum=acopio|albergue|hidratacion|sos"`
+	Subtipo              string   `json:"subtipo" validate:"max=100"`
+	CategoriasAyuda      []string `json:"categorias_ayuda" validate:"max=30"`
+	NivelUrgencia        string   `json:"nivel_urgencia" validate:"enum=critico|alto|medio|bajo"`
+	Horario              string   `json:"horario" validate:"max=300"`
+	NecesidadesRaw       string   `json:"necesidades_raw" validate:"max=5000"`
+	NecesidadesTags      any      `json:"necesidades_tags"`
+	NombreZona           string   `json:"nombre_zona" validate:"max=300"`
+	HabitadoActualmente  bool     `json:"habitado_actualmente"`
+	CantidadNinos        int      `json:"cantidad_ninos" validate:"min=0"`
+	CantidadAdolescentes int      `json:"cantidad_adolescentes" validate:"min=0"`
+	CantidadAdultos      int      `json:"cantidad_adultos" validate:"min=0"`
+	CantidadAncianos     int      `json:"cantidad_ancianos" validate:"min=0"`
+	AnimalesDetalle      string   `json:"animales_detalle" validate:"max=3000"`
+	RiesgoAsbesto        string   `json:"riesgo_asbesto" validate:"enum=si|no|no_se"`
+	LogisticaLlegada     string   `json:"logistica_llegada" validate:"max=3000"`
+	TiposAcceso          []string `json:"tipos_acceso" validate:"max=20"`
+	RequiereVoluntarios  bool     `json:"requiere_voluntarios"`
+	TieneBanos           bool     `json:"tiene_banos"`
+	TieneElectricidad    bool     `json:"tiene_electricidad"`
+	TieneSenal           bool     `json:"tiene_senal"`
+}
+
+// Validate revisa los campos de un punto propuesto por el público
+func (p *PuntoPublicoRequest) Validate() string {
+	if strings.TrimSpace(p.Nombre) == "" {
+		return "nombre is required"
+	}
+	if p.Latitud == 0 || p.Longitud == 0 || p.Latitud < -90 || p.Latitud > 90 || p.Longitud < -180 || p.Longitud > 180 {
+		return "latitud and longitud are required and must be valid coordinates"
+	}
+	if !contains(CategoriasPublicas, p.Categoria) {
+		return "categoria must be one of: " + strings.Join(CategoriasPublicas, ", ")
+	}
+	if p.NivelUrgencia != "" && NivelUrgenciaRank(p.NivelUrgencia) == 0 {
+		return "nivel_urgencia must be one of: " + strings.Join(nivelesUrgencia, ", ")
+	}
+	if p.CantidadNinos < 0 || p.CantidadAdolescentes < 0 || p.CantidadAdultos < 0 || p.CantidadAncianos < 0 {
+		return "cantidades must not be negative"
+	}
+	for _, text := range []string{p.Nombre, p.Direccion, p.Ciudad, p.Subtipo, p.Horario, p.NombreZona} {
+		if len(text) > 300 {
+			return "text fields must be at most 300 characters"
+		}
+	}
+	for _, text := range []string{p.NecesidadesRaw, p.AnimalesDetalle, p.LogisticaLlegada} {
+		if len(text) > 3000 {
+			return "descriptions must be at most 3000 characters"
+		}
+	}
+	return ""
+}
+
+// ToCreateRequest arma el punto a insertar, siempre en estado pendiente
+func (p *PuntoPublicoRequest) ToCreateRequest() PuntoCreateRequest {
+	return PuntoCreateRequest{
+		Nombre:               strings.TrimSpace(p.Nombre),
+		Latitud:              p.Latitud,
+		Longitud:             p.Longitud,
+		Direccion:            p.Direccion,
+		Ciudad:               p.Ciudad,
+		Categoria:            p.Categoria,
+		Subtipo:              p.Subtipo,
+		CategoriasAyuda:      p.CategoriasAyuda,
+		NivelUrgencia:        p.NivelUrgencia,
+		Horario:              p.Horario,
+		Estado:               "pendiente",
+		NecesidadesRaw:       p.NecesidadesRaw,
+		NecesidadesTags:      p.NecesidadesTags,
+		NombreZona:           p.NombreZona,
+		HabitadoActualmente:  p.HabitadoActualmente,
+		CantidadNinos:        p.CantidadNinos,
+		CantidadAdolescentes: p.CantidadAdolescentes,
+		CantidadAdultos:      p.CantidadAdultos,
+		CantidadAncianos:     p.CantidadAncianos,
+		AnimalesDetalle:      p.AnimalesDetalle,
+		RiesgoAsbesto:        p.RiesgoAsbesto,
+		LogisticaLlegada:     p.LogisticaLlegada,
+		TiposAcceso:          p.TiposAcceso,
+		RequiereVoluntarios:  p.RequiereVoluntarios,
+		TieneBanos:           p.TieneBanos,
+		TieneElectricidad:    p.TieneElectricidad,
+		TieneSenal:           p.TieneSenal,
+	}
+}
+
+// SolicitudCreateRequest es un envío público: un punto propuesto, un mensaje libre, o ambos.
+// Los datos del remitente son privados (solo los ve quien modera).
+type SolicitudCreateRequest struct {
+	RemitenteNombre   string               `json:"remitente_nombre" validate:"max=200"`
+	RemitenteContacto string               `json:"remitente_contacto" validate:"max=200"`
+	Mensaje           string               `json:"mensaje" validate:"max=5000"`
+	Origen            string               `json:"origen" validate:"max=50"`
+	Punto             *PuntoPublicoRequest `json:"punto,omitempty"`
+}
+
+func (s *SolicitudCreateRequest) Validate() string {
+	if s.Punto == nil && strings.TrimSpace(s.Mensaje) == "" {
+		return "punto or mensaje is required"
+	}
+	if len(s.Mensaje) > 5000 {
+		return "mensaje must be at most 5000 characters"
+	}
+	if len(s.RemitenteNombre) > 200 || len(s.RemitenteContacto) > 200 || len(s.Origen) > 50 {
+		return "remitente fields must be at most 200 characters"
+	}
+	if s.Punto != nil {
+		return s.Punto.Validate()
+	}
+	return ""
+}
+
+// PuntoSubmissionRequest es el cuerpo de POST /api/puntos: el punto propuesto
+// con los datos del remitente al mismo nivel
+type PuntoSubmissionRequest struct {
+	PuntoPublicoRequest
+	RemitenteNombre   string `json:"remitente_nombre" validate:"max=200"`
+	RemitenteContacto string `json:"remitente_contacto" validate:"max=200"`
+	Origen            string `json:"origen" validate:"max=50"`
+}
+
+func (p *PuntoSubmissionRequest) ToSolicitud() SolicitudCreateRequest {
+	punto := p.PuntoPublicoRequest
+	return SolicitudCreateRequest{
+		RemitenteNombre:   p.RemitenteNombre,
+		RemitenteContacto: p.RemitenteContacto,
+		Origen:            p.Origen,
+		Punto:             &punto,
+	}
+}
+
+// Solicitud es un elemento de la cola de moderación
+type Solicitud struct {
+	ID                string `json:"id"`
+	PuntoID           string `json:"punto_id,omitempty"`
+	DuplicadoDe       string `json:"duplicado_de,omitempty"` // Punto cercano de la misma categoría ya reportado
+	RemitenteNombre   string `json:"remitente_nombre,omitempty"`
+	RemitenteContacto string `json:"remitente_contacto,omitempty"`
+	Mensaje           string `json:"mensaje,omitempty"`
+	Origen            string `json:"origen,omitempty"`
+	Estado            string `json:"estado"`
+	MotivoRechazo     string `json:"motivo_rechazo,omitempty"`
+	ModeradoPor       string `json:"moderado_por,omitempty"`
+	FechaModeracion   string `json:"fecha_moderacion,omitempty"`
+	Created           string `json:"created"`
+	Punto             any    `json:"punto,omitempty"` // Vista del punto según el rol de quien modera
+}
+
+// SolicitudAcceptedResponse es lo que recibe el público al enviar
+type SolicitudAcceptedResponse struct {
+	ID      string `json:"id"`
+	Estado  string `json:"estado"`
+	Message string `json:"message"`
+}
+
+// SolicitudAprobarRequest permite corregir el punto al aprobarlo
+type SolicitudAprobarRequest struct {
+	Punto   *PuntoUpdateRequest `json:"punto,omitempty"`
+	PuntoID string              `json:"punto_id,omitempty"` // Para mensajes sin punto: punto creado a partir del mensaje
+}
+
+type SolicitudRechazarRequest struct {
+	Motivo string `json:"motivo" validate:"required,min=1,max=1000"`
+}
+
+type SolicitudesListResponse struct {
+	Data  []Solicitud `json:"data"`
+	Total int         `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
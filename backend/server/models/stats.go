@@ -0,0 +1,48 @@
+package models
+
+// StatsSinDato agrupa los puntos sin ciudad, categoría o nivel de urgencia
+const StatsSinDato = "sin_dato"
+
+// Afectados suma la población afectada reportada en los puntos
+type Afectados struct {
+	Ninos        int `json:"ninos"`
+	Adolescentes int `json:"adolescentes"`
+	Adultos      int `json:"adultos"`
+	Ancianos     int `json:"ancianos"`
+	Total        int `json:"total"`
+}
+
+func (a *Afectados) Add(b Afectados) {
+	a.Ninos += b.Ninos
+	a.Adolescentes += b.Adolescentes
+	a.Adultos += b.Adultos
+	a.Ancianos += b.Ancianos
+	a.Total += b.Total
+}
+
+// ZonaStats resume una zona (nombre_zona) dentro de una comuna
+type ZonaStats struct {
+	NombreZona string    `json:"nombre_zona"`
+	Puntos     int       `json:"puntos"`
+	Afectados  Afectados `json:"afectados"`
+}
+
+// CiudadStats resume los puntos de una comuna
+type CiudadStats struct {
+	Ciudad           string         `json:"ciudad"`
+	Total            int            `json:"total"`
+	PorCategoria     map[string]int `json:"por_categoria"`
+	PorNivelUrgencia map[string]int `json:"por_nivel_urgencia"`
+	Afectados        Afectados      `json:"afectados"`
+	Zonas            []ZonaStats    `json:"zonas,omitempty"`
+}
+
+// PuntoStats son los conteos agregados de GET /api/stats
+type PuntoStats struct {
+	Total            int            `json:"total"`
+	PorCategoria     map[string]int `json:"por_categoria"`
+	PorEstado        map[string]int `json:"por_estado"`
+	PorNivelUrgencia map[string]int `json:"por_nivel_urgencia"`
+	Afectados        Afectados      `json:"afectados"`
+	PorCiudad        []CiudadStats  `json:"por_ciudad"`
+}
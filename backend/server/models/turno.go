@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// MaxDuracionTurno es el largo máximo de un turno
+const MaxDuracionTurno = 24 * time.Hour
+
+// Turno es un bloque de trabajo en un punto con cupos que los voluntarios toman
+type Turno struct {
+	ID       string    `json:"id"`
+	PuntoID  string    `json:"punto_id"`
+	Inicio   time.Time `json:"inicio"`
+	Fin      time.Time `json:"fin"`
+	Rol      string    `json:"rol"` // cocina, bodega, recepción...
+	Cupos    int       `json:"cupos"`
+	Ocupados int       `json:"ocupados"`
+	Libres   int       `json:"libres"`
+	Nota     string    `json:"nota,omitempty"`
+	Created  string    `json:"created,omitempty"`
+	Updated  string    `json:"updated,omitempty"`
+
+	// Datos del punto, en los listados entre puntos y en los turnos de un voluntario
+	PuntoNombre string  `json:"punto_nombre,omitempty"`
+	Ciudad      string  `json:"ciudad,omitempty"`
+	Direccion   string  `json:"direccion,omitempty"`
+	Latitud     float64 `json:"latitud,omitempty"`
+	Longitud    float64 `json:"longitud,omitempty"`
+
+	// Quiénes lo tomaron, solo en la planilla del punto (admin)
+	Inscritos []TurnoInscrito `json:"inscritos,omitempty"`
+}
+
+// TurnoInscrito es un voluntario que tomó el turno
+type TurnoInscrito struct {
+	VoluntarioID string `json:"voluntario_id"`
+	Nombre       string `json:"nombre"`
+	Telefono     string `json:"telefono,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Created      string `json:"created"`
+}
+
+// TurnoCreateRequest crea uno o varios turnos seguidos del mismo largo
+// (p. ej. 21 turnos de 8 horas cubren una semana 24/7)
+type TurnoCreateRequest struct {
+	Inicio       string `json:"inicio" validate:"required" doc:"RFC3339 o YYYY-MM-DDTHH:MM hora de Chile"`
+	Fin          string `json:"fin" validate:"required" doc:"RFC3339 o YYYY-MM-DDTHH:MM hora de Chile; hasta 24 horas después de inicio"`
+	Rol          string `json:"rol" validate:"required,min=1,max=100"`
+	Cupos        int    `json:"cupos" validate:"required,min=1,max=1000"`
+	Nota         string `json:"nota" validate:"max=1000"`
+	Repeticiones int    `json:"repeticiones" validate:"min=0,max=200" doc:"Turnos a crear, uno a continuación del otro (default 1)"`
+}
+
+// TurnoUpdateRequest cambia un turno. El horario solo se puede mover si nadie lo ha tomado.
+type TurnoUpdateRequest struct {
+	Inicio *string `json:"inicio,omitempty"`
+	Fin    *string `json:"fin,omitempty"`
+	Rol    *string `json:"rol,omitempty" validate:"min=1,max=100"`
+	Cupos  *int    `json:"cupos,omitempty" validate:"min=1,max=1000" doc:"No puede quedar bajo los cupos ya ocupados"`
+	Nota   *string `json:"nota,omitempty" validate:"max=1000"`
+}
+
+// TurnoFilter filtra los listados de turnos
+type TurnoFilter struct {
+	PuntoID      string
+	VoluntarioID string // Solo los turnos que tomó
+	Ciudad       string
+	Rol          string
+	Desde        time.Time // Turnos que terminan después de Desde
+	Hasta        time.Time // y empiezan antes de Hasta (cero = sin límite)
+	SoloLibres   bool
+	Publicados   bool // Solo turnos de puntos publicados
+	Limit        int
+}
+
+// TurnoConflictoResponse es el 409 de un voluntario que ya tiene un turno a esa hora
+type TurnoConflictoResponse struct {
+	Error   string `json:"error"`
+	TurnoID string `json:"turno_id"`
+}
+
+type TurnosResponse struct {
+	Data []Turno `json:"data"`
+}
+
+// VoluntarioTokenResponse entrega el token de un voluntario; solo se muestra una vez
+type VoluntarioTokenResponse struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
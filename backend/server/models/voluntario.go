@@ -0,0 +1,145 @@
+package models
+
+// Vehiculos son los medios con que un voluntario puede llegar a un punto; usan los
+// mismos valores que tipos_acceso ("pie" = sin vehículo)
+var Vehiculos = []string{"pie", "auto", "4x4", "camion"}
+
+func IsVehiculo(v string) bool {
+	return contains(Vehiculos, v)
+}
+
+// Voluntario es una persona inscrita para ayudar. Teléfono, email y notas son
+// privados: solo los ven verificadores, admins y el encargado del punto asignado.
+type Voluntario struct {
+	ID                string   `json:"id"`
+	Nombre            string   `json:"nombre"`
+	Telefono          string   `json:"telefono,omitempty"`
+	Email             string   `json:"email,omitempty"`
+	Habilidades       []string `json:"habilidades"` // Normalizadas ("primeros_auxilios")
+	Ciudad            string   `json:"ciudad"`
+	Vehiculo          string   `json:"vehiculo"`
+	Disponibilidad    string   `json:"disponibilidad,omitempty"`     // Texto libre ("fines de semana")
+	DisponibilidadOSM string   `json:"disponibilidad_osm,omitempty"` // opening_hours de OSM
+	Activo            bool     `json:"activo"`
+	Notas             string   `json:"notas,omitempty"`
+	Created           string   `json:"created"`
+	Updated           string   `json:"updated,omitempty"`
+
+	// Puntos a los que está asignado
+	Puntos []string `json:"puntos"`
+}
+
+// PuedeLlegar indica si con vehiculo se llega a un punto con esos tipos_acceso.
+// Sin tipos_acceso declarados se asume que cualquiera llega; donde se llega a pie
+// también; "auto" admite cualquier vehículo y "4x4" o "camion" solo ese.
+func PuedeLlegar(vehiculo string, tiposAcceso []string) bool {
+	if len(tiposAcceso) == 0 {
+		return true
+	}
+	for _, tipo := range tiposAcceso {
+		switch tipo {
+		case "pie":
+			return true
+		case "auto":
+			if vehiculo != "pie" {
+				return true
+			}
+		default:
+			if vehiculo == tipo {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// VoluntarioCreateRequest es la inscripción pública (POST /api/voluntarios)
+type VoluntarioCreateRequest struct {
+	Nombre            string   `json:"nombre" validate:"required,min=1,max=200"`
+	Telefono          string   `json:"telefono" validate:"max=50" doc:"Teléfono o email; al menos uno"`
+	Email             string   `json:"email" validate:"max=200"`
+	Habilidades       []string `json:"habilidades" validate:"max=20" doc:"primeros auxilios, cocina, carga, conducción..."`
+	Ciudad            string   `json:"ciudad" validate:"required,min=1,max=100"`
+	Vehiculo          string   `json:"vehiculo" validate:"enum=pie|auto|4x4|camion" doc:"Mismos valores que tipos_acceso; por defecto pie"`
+	Disponibilidad    string   `json:"disponibilidad" validate:"max=300"`
+	DisponibilidadOSM string   `json:"disponibilidad_osm" validate:"max=500" doc:"Disponibilidad en formato opening_hours de OSM; si se omite se deriva de disponibilidad"`
+}
+
+// VoluntarioUpdateRequest corrige la ficha de un voluntario (admin)
+type VoluntarioUpdateRequest struct {
+	Nombre            *string   `json:"nombre,omitempty" validate:"min=1,max=200"`
+	Telefono          *string   `json:"telefono,omitempty" validate:"max=50"`
+	Email             *string   `json:"email,omitempty" validate:"max=200"`
+	Habilidades       *[]string `json:"habilidades,omitempty" validate:"max=20"`
+	Ciudad            *string   `json:"ciudad,omitempty" validate:"min=1,max=100"`
+	Vehiculo          *string   `json:"vehiculo,omitempty" validate:"enum=pie|auto|4x4|camion"`
+	Disponibilidad    *string   `json:"disponibilidad,omitempty" validate:"max=300"`
+	DisponibilidadOSM *string   `json:"disponibilidad_osm,omitempty" validate:"max=500" doc:"Vacío la vuelve a derivar de disponibilidad"`
+	Activo            *bool     `json:"activo,omitempty"`
+	Notas             *string   `json:"notas,omitempty" validate:"max=3000"`
+}
+
+// VoluntarioFilter filtra el listado de voluntarios
+type VoluntarioFilter struct {
+	Ciudad    string
+	Habilidad string // Normalizada
+	Vehiculos []string
+	Activo    *bool
+	Libres    bool   // Solo los que no tienen punto asignado
+	Excluir   string // Punto cuyos voluntarios ya asignados se excluyen
+	// Solo los que tienen disponibilidad estructurada en ese minuto de la semana (-1 = sin filtrar)
+	DisponibleMinuto int
+	Limit            int
+}
+
+// VoluntarioAcceptedResponse es lo que recibe quien se inscribe
+type VoluntarioAcceptedResponse struct {
+	ID      string `json:"id"`
+	Token   string `json:"token" doc:"Para tomar turnos y suscribirse a su calendario; solo se muestra una vez"`
+	Message string `json:"message"`
+}
+
+type VoluntariosResponse struct {
+	Data  []Voluntario `json:"data"`
+	Limit int          `json:"limit"`
+}
+
+// PuntoVoluntarios resume cuántos voluntarios pide un punto y cuántos tiene
+type PuntoVoluntarios struct {
+	PuntoID     string   `json:"punto_id"`
+	Nombre      string   `json:"nombre"`
+	Ciudad      string   `json:"ciudad"`
+	Categoria   string   `json:"categoria"`
+	TiposAcceso []string `json:"tipos_acceso,omitempty"`
+	Cupo        int      `json:"cupo"`
+	Asignados   int      `json:"asignados"`
+	Faltan      int      `json:"faltan"`
+}
+
+// NewPuntoVoluntarios arma el resumen a partir del punto
+func NewPuntoVoluntarios(p *Punto) PuntoVoluntarios {
+	faltan := p.CupoVoluntarios - p.VoluntariosAsignados
+	if faltan < 0 {
+		faltan = 0
+	}
+	return PuntoVoluntarios{
+		PuntoID:     p.ID,
+		Nombre:      p.Nombre,
+		Ciudad:      p.Ciudad,
+		Categoria:   p.Categoria,
+		TiposAcceso: p.TiposAcceso,
+		Cupo:        p.CupoVoluntarios,
+		Asignados:   p.VoluntariosAsignados,
+		Faltan:      faltan,
+	}
+}
+
+// PuntoVoluntariosResponse es el detalle de un punto: el resumen y los asignados
+type PuntoVoluntariosResponse struct {
+	PuntoVoluntarios
+	Data []Voluntario `json:"data"`
+}
+
+type PuntosVoluntariosResponse struct {
+	Data []PuntoVoluntarios `json:"data"`
+}
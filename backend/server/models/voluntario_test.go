@@ -0,0 +1,24 @@
+package models
+
+import "testing"
+
+func TestPuedeLlegar(t *testing.T) {
+	cases := []struct {
+		vehiculo    string
+		tiposAcceso []string
+		want        bool
+	}{
+		{"pie", nil, true},
+		{"pie", []string{"auto", "pie"}, true},
+		{"pie", []string{"auto"}, false},
+		{"4x4", []string{"auto"}, true},
+		{"auto", []string{"4x4"}, false},
+		{"4x4", []string{"4x4"}, true},
+		{"camion", []string{"4x4", "camion"}, true},
+	}
+	for _, c := range cases {
+		if got := PuedeLlegar(c.vehiculo, c.tiposAcceso); got != c.want {
+			t.Errorf("PuedeLlegar(%q, %v) = %v, want %v", c.vehiculo, c.tiposAcceso, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package mvt codifica Mapbox Vector Tiles (spec v2.1) con geometrías de punto.
+//
+// El formato es protobuf; como solo necesitamos escribir tiles con puntos,
+// se codifica a mano sin depender de una librería de protobuf.
+package mvt
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultExtent es la resolución estándar de un tile
+const DefaultExtent = 4096
+
+// ContentType es el media type de un tile vectorial
+const ContentType = "application/vnd.mapbox-vector-tile"
+
+// Feature es un punto en coordenadas locales del tile con sus atributos.
+// Los valores soportados son string, bool, int, int64 y float64.
+type Feature struct {
+	X, Y       int
+	Properties map[string]any
+}
+
+type Layer struct {
+	Name     string
+	Extent   uint32
+	Features []Feature
+}
+
+// NewLayer crea una capa con el extent por defecto
+func NewLayer(name string) *Layer {
+	return &Layer{Name: name, Extent: DefaultExtent}
+}
+
+// AddPoint agrega un punto a la capa
+func (l *Layer) AddPoint(x, y int, properties map[string]any) {
+	l.Features = append(l.Features, Feature{X: x, Y: y, Properties: properties})
+}
+
+// Números de campo del esquema vector_tile.proto
+const (
+	tileLayers = 3
+
+	layerName     = 1
+	layerFeatures = 2
+	layerKeys     = 3
+	layerValues   = 4
+	layerExtent   = 5
+	layerVersion  = 15
+
+	featureTags     = 2
+	featureType     = 3
+	featureGeometry = 4
+
+	valueString = 1
+	valueDouble = 3
+	valueInt    = 4
+	valueBool   = 7
+
+	geomTypePoint = 1
+	cmdMoveTo     = 1
+)
+
+// Encode serializa las capas como un tile
+func Encode(layers ...*Layer) []byte {
+	var tile buffer
+	for _, l := range layers {
+		tile.bytesField(tileLayers, l.encode())
+	}
+	return tile
+}
+
+func (l *Layer) encode() []byte {
+	var buf buffer
+	buf.varintField(layerVersion, 2)
+	buf.stringField(layerName, l.Name)
+
+	keys := []string{}
+	keyIndex := map[string]int{}
+	values := [][]byte{}
+	valueIndex := map[string]int{}
+
+	for _, f := range l.Features {
+		// Orden estable de atributos para que el mismo tile produzca los mismos bytes
+		names := make([]string, 0, len(f.Properties))
+		for key := range f.Properties {
+			names = append(names, key)
+		}
+		sort.Strings(names)
+
+		tags := []uint64{}
+		for _, key := range names {
+			encoded, ok := encodeValue(f.Properties[key])
+			if !ok {
+				continue
+			}
+
+			ki, found := keyIndex[key]
+			if !found {
+				ki = len(keys)
+				keys = append(keys, key)
+				keyIndex[key] = ki
+			}
+
+			vi, found := valueIndex[string(encoded)]
+			if !found {
+				vi = len(values)
+				values = append(values, encoded)
+				valueIndex[string(encoded)] = vi
+			}
+
+			tags = append(tags, uint64(ki), uint64(vi))
+		}
+
+		var feature buffer
+		feature.packedField(featureTags, tags)
+		feature.varintField(featureType, geomTypePoint)
+		feature.packedField(featureGeometry, []uint64{
+			command(cmdMoveTo, 1), zigzag(f.X), zigzag(f.Y),
+		})
+		buf.bytesField(layerFeatures, feature)
+	}
+
+	for _, key := range keys {
+		buf.stringField(layerKeys, key)
+	}
+	for _, v := range values {
+		buf.bytesField(layerValues, v)
+	}
+	buf.varintField(layerExtent, uint64(l.Extent))
+
+	return buf
+}
+
+func encodeValue(v any) ([]byte, bool) {
+	var buf buffer
+	switch val := v.(type) {
+	case string:
+		buf.stringField(valueString, val)
+	case bool:
+		b := uint64(0)
+		if val {
+			b = 1
+		}
+		buf.varintField(valueBool, b)
+	case int:
+		buf.varintField(valueInt, uint64(int64(val)))
+	case int64:
+		buf.varintField(valueInt, uint64(val))
+	case float64:
+		buf.fixed64Field(valueDouble, math.Float64bits(val))
+	default:
+		return nil, false
+	}
+	return buf, true
+}
+
+func command(id, count uint32) uint64 {
+	return uint64((id & 0x7) | (count << 3))
+}
+
+func zigzag(n int) uint64 {
+	return uint64((int64(n) << 1) ^ (int64(n) >> 63))
+}
@@ -0,0 +1,53 @@
+package mvt
+
+import "encoding/binary"
+
+// Tipos de cable de protobuf usados por el formato MVT
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// buffer es un escritor mínimo de mensajes protobuf
+type buffer []byte
+
+func (b *buffer) varint(v uint64) {
+	*b = binary.AppendUvarint(*b, v)
+}
+
+func (b *buffer) key(field, wireType int) {
+	b.varint(uint64(field<<3 | wireType))
+}
+
+func (b *buffer) varintField(field int, v uint64) {
+	b.key(field, wireVarint)
+	b.varint(v)
+}
+
+func (b *buffer) fixed64Field(field int, v uint64) {
+	b.key(field, wireFixed64)
+	*b = binary.LittleEndian.AppendUint64(*b, v)
+}
+
+func (b *buffer) bytesField(field int, data []byte) {
+	b.key(field, wireBytes)
+	b.varint(uint64(len(data)))
+	*b = append(*b, data...)
+}
+
+func (b *buffer) stringField(field int, s string) {
+	b.bytesField(field, []byte(s))
+}
+
+// packedField escribe un campo repeated uint32/uint64 empaquetado
+func (b *buffer) packedField(field int, values []uint64) {
+	if len(values) == 0 {
+		return
+	}
+	var packed buffer
+	for _, v := range values {
+		packed.varint(v)
+	}
+	b.bytesField(field, packed)
+}
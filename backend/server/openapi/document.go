@@ -0,0 +1,237 @@
+// Package openapi genera el documento OpenAPI 3 de la API a partir de las rutas
+// registradas en el router chi y de los tipos Go de request/response, y valida
+// los cuerpos de las peticiones contra esos mismos schemas.
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	Tags       []Tag               `json:"tags,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem agrupa las operaciones de una ruta por método en minúsculas (get, post, ...)
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Route describe una ruta para el documento. Las rutas registradas en el router
+// sin Route aparecen igual, solo con método, path y parámetros de path.
+type Route struct {
+	Summary     string
+	Description string
+	Tag         string
+	Auth        bool     // Requiere Authorization: Bearer
+	Roles       []string // Roles permitidos (se documentan en la descripción)
+	Query       []Parameter
+	Request     any    // Valor del tipo del cuerpo JSON
+	RequestType string // Para cuerpos que no son JSON (multipart/form-data)
+	Response    any    // Valor del tipo de la respuesta JSON
+	Status      int    // Código de éxito (200 por defecto)
+	ContentType string // Para respuestas que no son JSON (csv, kml, mvt, ...)
+}
+
+// QueryParam es un atajo para documentar un parámetro de query
+func QueryParam(name, typ, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
+
+var pathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Build recorre las rutas del router y arma el documento. routes se indexa por
+// "MÉTODO /path" con el patrón de chi ("GET /api/puntos/{id}").
+func Build(info Info, router chi.Routes, reg *Registry, routes map[string]Route) (*Document, error) {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]PathItem{},
+	}
+	tags := map[string]bool{}
+
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !strings.HasPrefix(route, "/api") || method == http.MethodHead || method == http.MethodOptions {
+			return nil
+		}
+		route = strings.TrimSuffix(route, "/")
+		path := pathParam.ReplaceAllString(route, "{$1}")
+
+		meta, ok := routes[method+" "+route]
+		if !ok {
+			meta = Route{Tag: defaultTag(path)}
+		}
+		op := reg.operation(method, path, meta)
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(method)] = op
+		if meta.Tag != "" {
+			tags[meta.Tag] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	doc.Components = Components{
+		Schemas: reg.Components(),
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+	}
+	return doc, nil
+}
+
+func (reg *Registry) operation(method, path string, meta Route) *Operation {
+	op := &Operation{
+		Summary:     meta.Summary,
+		Description: meta.Description,
+		OperationID: operationID(method, path),
+		Responses:   map[string]Response{},
+	}
+	if meta.Tag != "" {
+		op.Tags = []string{meta.Tag}
+	}
+	if len(meta.Roles) > 0 {
+		roles := "Roles: " + strings.Join(meta.Roles, ", ")
+		if op.Description != "" {
+			op.Description += "\n\n" + roles
+		} else {
+			op.Description = roles
+		}
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	op.Parameters = append(op.Parameters, meta.Query...)
+
+	if meta.Request != nil {
+		requestType := meta.RequestType
+		if requestType == "" {
+			requestType = "application/json"
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{requestType: {Schema: reg.SchemaOf(meta.Request)}},
+		}
+		op.Responses["400"] = Response{
+			Description: "Cuerpo inválido (errores por campo)",
+			Content:     map[string]MediaType{"application/json": {Schema: reg.SchemaOf(ValidationError{})}},
+		}
+	}
+
+	status := meta.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ok := Response{Description: http.StatusText(status)}
+	switch {
+	case meta.ContentType != "":
+		ok.Content = map[string]MediaType{meta.ContentType: {}}
+	case meta.Response != nil:
+		ok.Content = map[string]MediaType{"application/json": {Schema: reg.SchemaOf(meta.Response)}}
+	}
+	op.Responses[strconv.Itoa(status)] = ok
+
+	if meta.Auth {
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+		op.Responses["401"] = Response{Description: "Falta el token o no es válido"}
+		if len(meta.Roles) > 0 {
+			op.Responses["403"] = Response{Description: "El rol no tiene permiso"}
+		}
+	}
+	return op
+}
+
+// operationID arma un id legible: "GET /api/puntos/{id}" -> "getApiPuntosById"
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '.' || r == '-' }) {
+		if strings.HasPrefix(part, "{") {
+			b.WriteString("By")
+			part = strings.Trim(part, "{}")
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// defaultTag agrupa por el segmento después de /api (o /api/admin)
+func defaultTag(path string) string {
+	parts := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+	if parts[0] == "admin" && len(parts) > 1 {
+		return "admin"
+	}
+	return parts[0]
+}
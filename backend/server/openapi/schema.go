@@ -0,0 +1,210 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Schema es el subconjunto de JSON Schema que usa OpenAPI 3.0 y que entiende Validate
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+// Registry genera los schemas de los tipos Go a partir de sus tags json y validate,
+// y los guarda como components/schemas. Es seguro para uso concurrente.
+//
+// Tags validate soportados (separados por coma):
+//
+//	required         el campo debe venir en el JSON
+//	min=N, max=N     rango (números), largo (strings) o cantidad de elementos (arrays)
+//	enum=a|b|c       valores permitidos (strings; "" se acepta como "sin valor")
+type Registry struct {
+	mu      sync.Mutex
+	schemas map[string]*Schema
+}
+
+func NewRegistry() *Registry {
+	return &Registry{schemas: map[string]*Schema{}}
+}
+
+// Components devuelve una copia de los schemas registrados
+func (reg *Registry) Components() map[string]*Schema {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	out := make(map[string]*Schema, len(reg.schemas))
+	for name, s := range reg.schemas {
+		out[name] = s
+	}
+	return out
+}
+
+// SchemaOf devuelve el schema de v (un valor o puntero del tipo a describir)
+func (reg *Registry) SchemaOf(v any) *Schema {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	return reg.schemaFor(reflect.TypeOf(v))
+}
+
+// resolve sigue un $ref a components/schemas
+func (reg *Registry) resolve(s *Schema) *Schema {
+	if s.Ref == "" {
+		return s
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if target, ok := reg.schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]; ok {
+		return target
+	}
+	return &Schema{}
+}
+
+func (reg *Registry) schemaFor(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		s := *reg.schemaFor(t.Elem())
+		if s.Ref != "" {
+			return &s
+		}
+		s.Nullable = true
+		return &s
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: reg.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return reg.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := reg.schemas[name]; !ok {
+			// Se reserva el nombre antes de recorrer los campos por si el tipo es recursivo
+			reg.schemas[name] = &Schema{Type: "object"}
+			reg.schemas[name] = reg.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// interface{} / any: cualquier valor JSON
+		return &Schema{}
+	}
+}
+
+func (reg *Registry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	reg.addFields(s, t)
+	return s
+}
+
+func (reg *Registry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Los structs embebidos sin nombre json aportan sus campos al mismo nivel
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			reg.addFields(s, f.Type)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := reg.schemaFor(f.Type)
+		if rules := f.Tag.Get("validate"); rules != "" {
+			if applyRules(prop, rules) {
+				s.Required = append(s.Required, name)
+			}
+		}
+		if doc := f.Tag.Get("doc"); doc != "" {
+			prop.Description = doc
+		}
+		if format := f.Tag.Get("format"); format != "" {
+			prop.Format = format
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// applyRules aplica las reglas de un tag validate al schema y devuelve si el campo es requerido
+func applyRules(s *Schema, rules string) bool {
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "required":
+			required = true
+		case "enum":
+			s.Enum = strings.Split(value, "|")
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			setBound(s, key, n)
+		}
+	}
+	return required
+}
+
+func setBound(s *Schema, key string, n float64) {
+	switch s.Type {
+	case "string":
+		v := int(n)
+		if key == "min" {
+			s.MinLength = &v
+		} else {
+			s.MaxLength = &v
+		}
+	case "array":
+		v := int(n)
+		if key == "min" {
+			s.MinItems = &v
+		} else {
+			s.MaxItems = &v
+		}
+	default:
+		if key == "min" {
+			s.Minimum = &n
+		} else {
+			s.Maximum = &n
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError es un error de validación de un campo del cuerpo (field usa notación
+// con puntos e índices: "punto.latitud", "categorias_ayuda[2]")
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError es la respuesta 400 cuando el cuerpo no cumple el schema
+type ValidationError struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
+
+// Validate revisa el JSON raw contra el schema y devuelve los errores por campo
+// (nil si es válido). Los campos desconocidos se ignoran, igual que encoding/json.
+func (reg *Registry) Validate(s *Schema, raw []byte) []FieldError {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return []FieldError{{Field: "", Message: "invalid JSON: " + err.Error()}}
+	}
+
+	var errs []FieldError
+	reg.validate(s, value, "", &errs)
+	return errs
+}
+
+func (reg *Registry) validate(s *Schema, value any, path string, errs *[]FieldError) {
+	s = reg.resolve(s)
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		if s.Type != "" && !s.Nullable {
+			fail("must not be null")
+		}
+		return
+	}
+
+	switch s.Type {
+	case "":
+		// Sin tipo: cualquier valor
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		n := utf8.RuneCountInString(str)
+		if s.MinLength != nil && n < *s.MinLength {
+			if *s.MinLength == 1 {
+				fail("must not be empty")
+			} else {
+				fail("must be at least %d characters", *s.MinLength)
+			}
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("must be at most %d characters", *s.MaxLength)
+		}
+		// "" equivale a no informar el campo (los formularios envían vacíos)
+		if len(s.Enum) > 0 && str != "" && !contains(s.Enum, str) {
+			fail("must be one of: %s", strings.Join(s.Enum, ", "))
+		}
+	case "integer", "number":
+		num, ok := value.(json.Number)
+		if !ok {
+			fail("must be a number")
+			return
+		}
+		if s.Type == "integer" {
+			if _, err := num.Int64(); err != nil {
+				fail("must be an integer")
+				return
+			}
+		}
+		f, err := num.Float64()
+		if err != nil {
+			fail("must be a number")
+			return
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			fail("must be >= %v", *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			fail("must be <= %v", *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be true or false")
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			fail("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				reg.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				*errs = append(*errs, FieldError{Field: join(path, name), Message: "is required"})
+			}
+		}
+
+		// Orden estable de los errores
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := s.Properties[name]; ok {
+				reg.validate(prop, obj[name], join(path, name), errs)
+			}
+		}
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/P1ngu-Dev/donde-ayudo-cl/backend/models"
+)
+
+func TestValidate(t *testing.T) {
+	long := strings.Repeat("x", 201)
+	tests := []struct {
+		name string
+		v    any
+		body string
+		want []FieldError
+	}{
+		{
+			name: "required ausente",
+			v:    models.PuntoCreateRequest{},
+			body: `{"latitud": -33.4, "longitud": -70.6, "categoria": "acopio"}`,
+			want: []FieldError{{"nombre", "is required"}},
+		},
+		{
+			name: "required vacío",
+			v:    models.PuntoCreateRequest{},
+			body: `{"nombre": "", "latitud": -33.4, "longitud": -70.6, "categoria": "acopio"}`,
+			want: []FieldError{{"nombre", "must not be empty"}},
+		},
+		{
+			name: "enum vacío equivale a no informarlo",
+			v:    models.PuntoCreateRequest{},
+			body: `{"nombre": "A", "latitud": -33.4, "longitud": -70.6, "categoria": "acopio", "nivel_urgencia": "", "riesgo_asbesto": ""}`,
+		},
+		{
+			name: "enum fuera de la lista",
+			v:    models.PuntoCreateRequest{},
+			body: `{"nombre": "A", "latitud": -33.4, "longitud": -70.6, "categoria": "comedor"}`,
+			want: []FieldError{{"categoria", "must be one of: acopio, albergue, hidratacion, sos"}},
+		},
+		{
+			name: "min y max en punteros",
+			v:    models.PuntoUpdateRequest{},
+			body: `{"latitud": 91, "longitud": -181, "nombre": "", "capacidad_total": -1, "cupo_voluntarios": 10001}`,
+			want: []FieldError{
+				{"capacidad_total", "must be >= 0"},
+				{"cupo_voluntarios", "must be <= 10000"},
+				{"latitud", "must be <= 90"},
+				{"longitud", "must be >= -180"},
+				{"nombre", "must not be empty"},
+			},
+		},
+		{
+			name: "punteros dentro del rango",
+			v:    models.PuntoUpdateRequest{},
+			body: `{"latitud": -90, "longitud": 180, "nombre": "A"}`,
+		},
+		{
+			name: "struct embebido aplanado",
+			v:    models.PuntoSubmissionRequest{},
+			body: `{"latitud": -33.4, "longitud": -70.6, "categoria": "sos", "nivel_urgencia": "urgente", "remitente_nombre": "` + long + `"}`,
+			want: []FieldError{
+				{"nombre", "is required"},
+				{"nivel_urgencia", "must be one of: critico, alto, medio, bajo"},
+				{"remitente_nombre", "must be at most 200 characters"},
+			},
+		},
+		{
+			name: "null en campos no puntero",
+			v:    models.PuntoCreateRequest{},
+			body: `{"nombre": null, "latitud": -33.4, "longitud": -70.6, "categoria": "acopio", "tipos_acceso": null}`,
+			want: []FieldError{
+				{"nombre", "must not be null"},
+				{"tipos_acceso", "must not be null"},
+			},
+		},
+		{
+			name: "null en punteros y any",
+			v:    models.PuntoUpdateRequest{},
+			body: `{"nombre": null, "latitud": null, "categorias_ayuda": null, "necesidades_tags": null}`,
+		},
+		{
+			name: "tipo incorrecto",
+			v:    models.PuntoUpdateRequest{},
+			body: `{"latitud": "-33.4", "tiene_banos": "si", "tipos_acceso": "4x4"}`,
+			want: []FieldError{
+				{"latitud", "must be a number"},
+				{"tiene_banos", "must be true or false"},
+				{"tipos_acceso", "must be an array"},
+			},
+		},
+	}
+
+	reg := NewRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reg.Validate(reg.SchemaOf(tt.v), []byte(tt.body))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%T) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+// El struct embebido aporta sus campos al mismo nivel, sin propiedad propia
+func TestSchemaFlattensEmbedded(t *testing.T) {
+	reg := NewRegistry()
+	s := reg.resolve(reg.SchemaOf(models.PuntoSubmissionRequest{}))
+
+	if _, ok := s.Properties["PuntoPublicoRequest"]; ok {
+		t.Error("embedded struct exposed as property")
+	}
+	for _, name := range []string{"nombre", "categoria", "remitente_nombre"} {
+		if _, ok := s.Properties[name]; !ok {
+			t.Errorf("missing property %q", name)
+		}
+	}
+	if want := []string{"nombre", "latitud", "longitud", "categoria"}; !reflect.DeepEqual(s.Required, want) {
+		t.Errorf("required = %v, want %v", s.Required, want)
+	}
+}
+
+// adminSOSPayload es el cuerpo que arma handleEditSubmit (src/admin.js) para un punto SOS
+func adminSOSPayload(nivelUrgencia string) []byte {
+	raw, _ := json.Marshal(map[string]any{
+		"nombre":                "Sector Cancha",
+		"ciudad":                "Viña del Mar",
+		"direccion":             "",
+		"latitud":               -33.0247,
+		"longitud":              -71.5518,
+		"categoria":             "sos",
+		"subtipo":               "",
+		"estado":                "publicado",
+		"contacto_nombre":       "",
+		"contacto_principal":    "+56912345678",
+		"horario":               "",
+		"horario_osm":           "",
+		"capacidad_estado":      "",
+		"capacidad_total":       0,
+		"necesidades_raw":       "agua, pañales",
+		"necesidades_tags":      []string{"agua"},
+		"notas_internas":        "",
+		"entidad_verificadora":  "",
+		"nombre_zona":           "Sector Cancha",
+		"nivel_urgencia":        nivelUrgencia,
+		"habitado_actualmente":  true,
+		"cantidad_ninos":        2,
+		"cantidad_adolescentes": 0,
+		"cantidad_adultos":      5,
+		"cantidad_ancianos":     1,
+		"animales_detalle":      "",
+		"riesgo_asbesto":        "no_se",
+		"foto_asbesto":          "",
+		"tiene_banos":           false,
+		"tiene_electricidad":    false,
+		"tiene_senal":           true,
+		"requiere_voluntarios":  false,
+		"cupo_voluntarios":      0,
+		"logistica_llegada":     "",
+		"categorias_ayuda":      []string{},
+		"tipos_acceso":          []string{"4x4"},
+		"archivo_kml":           "",
+	})
+	return raw
+}
+
+func TestValidateAdminPuntoPayload(t *testing.T) {
+	reg := NewRegistry()
+	for _, v := range []any{models.PuntoCreateRequest{}, models.PuntoUpdateRequest{}} {
+		s := reg.SchemaOf(v)
+		for _, nivel := range []string{"", "critico", "alto", "medio", "bajo"} {
+			if errs := reg.Validate(s, adminSOSPayload(nivel)); len(errs) > 0 {
+				t.Errorf("%T nivel_urgencia=%q: %v", v, nivel, errs)
+			}
+		}
+
+		errs := reg.Validate(s, adminSOSPayload("critica"))
+		if len(errs) != 1 || errs[0].Field != "nivel_urgencia" {
+			t.Errorf("%T nivel_urgencia=critica: %v", v, errs)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package pow implementa un desafío de prueba de trabajo (proof-of-work) para
+// los envíos públicos. El desafío va firmado con HMAC, así que el servidor no
+// guarda nada al emitirlo; el cliente debe encontrar un nonce tal que
+// SHA-256(desafío + nonce) empiece con Difficulty bits en cero.
+package pow
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMissing      = errors.New("proof-of-work required")
+	ErrInvalid      = errors.New("invalid challenge")
+	ErrExpired      = errors.New("challenge expired")
+	ErrReused       = errors.New("challenge already used")
+	ErrInsufficient = errors.New("nonce does not satisfy difficulty")
+)
+
+// Challenge es lo que recibe el cliente en GET /api/challenge
+type Challenge struct {
+	Challenge  string    `json:"challenge,omitempty"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at,omitempty"`
+}
+
+type Issuer struct {
+	secret     []byte
+	difficulty int
+	ttl        time.Duration
+
+	mu   sync.Mutex
+	used map[string]time.Time // desafíos ya canjeados, hasta que expiran
+}
+
+// NewIssuer crea un emisor de desafíos. difficulty = 0 desactiva la prueba de trabajo.
+func NewIssuer(secret string, difficulty int, ttl time.Duration) *Issuer {
+	return &Issuer{
+		secret:     []byte(secret),
+		difficulty: difficulty,
+		ttl:        ttl,
+		used:       map[string]time.Time{},
+	}
+}
+
+func (i *Issuer) Enabled() bool {
+	return i != nil && i.difficulty > 0
+}
+
+// Issue emite un desafío "expira:dificultad:aleatorio.firma"
+func (i *Issuer) Issue() Challenge {
+	if !i.Enabled() {
+		return Challenge{}
+	}
+
+	random := make([]byte, 12)
+	rand.Read(random)
+
+	expires := time.Now().Add(i.ttl).Truncate(time.Second)
+	payload := fmt.Sprintf("%d:%d:%s", expires.Unix(), i.difficulty, hex.EncodeToString(random))
+
+	return Challenge{
+		Challenge:  payload + "." + i.sign(payload),
+		Difficulty: i.difficulty,
+		ExpiresAt:  expires,
+	}
+}
+
+// Verify comprueba la firma, la vigencia y el nonce, y marca el desafío como usado
+func (i *Issuer) Verify(challenge, nonce string) error {
+	if challenge == "" || nonce == "" {
+		return ErrMissing
+	}
+
+	payload, sig, ok := strings.Cut(challenge, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(i.sign(payload))) {
+		return ErrInvalid
+	}
+
+	parts := strings.Split(payload, ":")
+	if len(parts) != 3 {
+		return ErrInvalid
+	}
+	expiresUnix, err1 := strconv.ParseInt(parts[0], 10, 64)
+	difficulty, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return ErrInvalid
+	}
+
+	now := time.Now()
+	expires := time.Unix(expiresUnix, 0)
+	if now.After(expires) {
+		return ErrExpired
+	}
+
+	sum := sha256.Sum256([]byte(challenge + nonce))
+	if LeadingZeroBits(sum[:]) < difficulty {
+		return ErrInsufficient
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, used := i.used[challenge]; used {
+		return ErrReused
+	}
+	for c, exp := range i.used {
+		if now.After(exp) {
+			delete(i.used, c)
+		}
+	}
+	i.used[challenge] = expires
+
+	return nil
+}
+
+func (i *Issuer) sign(payload string) string {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write([]byte("pow:" + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// LeadingZeroBits cuenta los bits en cero al comienzo de b
+func LeadingZeroBits(b []byte) int {
+	n := 0
+	for _, c := range b {
+		if c != 0 {
+			return n + bits.LeadingZeros8(c)
+		}
+		n += 8
+	}
+	return n
+}
@@ -0,0 +1,96 @@
+package pow
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// solve busca un nonce que cumpla (want = true) o que no cumpla la dificultad
+func solve(t *testing.T, challenge string, difficulty int, want bool) string {
+	t.Helper()
+	for n := 0; n < 1<<22; n++ {
+		nonce := strconv.Itoa(n)
+		sum := sha256.Sum256([]byte(challenge + nonce))
+		if (LeadingZeroBits(sum[:]) >= difficulty) == want {
+			return nonce
+		}
+	}
+	t.Fatal("no se encontró nonce")
+	return ""
+}
+
+func TestVerify(t *testing.T) {
+	const difficulty = 8
+	issuer := NewIssuer("secreto", difficulty, time.Minute)
+	expired := NewIssuer("secreto", difficulty, -time.Minute)
+	otherKey := NewIssuer("otro-secreto", difficulty, time.Minute)
+
+	tests := []struct {
+		name string
+		// build devuelve el desafío y el nonce a verificar con issuer
+		build func() (string, string)
+		want  error
+	}{
+		{"nonce válido", func() (string, string) {
+			c := issuer.Issue().Challenge
+			return c, solve(t, c, difficulty, true)
+		}, nil},
+		{"nonce incorrecto", func() (string, string) {
+			c := issuer.Issue().Challenge
+			return c, solve(t, c, difficulty, false)
+		}, ErrInsufficient},
+		{"desafío expirado", func() (string, string) {
+			c := expired.Issue().Challenge
+			return c, solve(t, c, difficulty, true)
+		}, ErrExpired},
+		{"firma alterada", func() (string, string) {
+			c := issuer.Issue().Challenge
+			last := "0"
+			if strings.HasSuffix(c, "0") {
+				last = "1"
+			}
+			c = c[:len(c)-1] + last
+			return c, solve(t, c, difficulty, true)
+		}, ErrInvalid},
+		{"dificultad rebajada con la firma original", func() (string, string) {
+			payload, sig, _ := strings.Cut(issuer.Issue().Challenge, ".")
+			parts := strings.Split(payload, ":")
+			c := parts[0] + ":0:" + parts[2] + "." + sig
+			return c, "0"
+		}, ErrInvalid},
+		{"firmado con otra clave", func() (string, string) {
+			c := otherKey.Issue().Challenge
+			return c, solve(t, c, difficulty, true)
+		}, ErrInvalid},
+		{"sin nonce", func() (string, string) {
+			return issuer.Issue().Challenge, ""
+		}, ErrMissing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge, nonce := tt.build()
+			if err := issuer.Verify(challenge, nonce); !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// Un desafío resuelto se canjea una sola vez
+func TestVerifyReused(t *testing.T) {
+	issuer := NewIssuer("secreto", 8, time.Minute)
+	c := issuer.Issue().Challenge
+	nonce := solve(t, c, 8, true)
+
+	if err := issuer.Verify(c, nonce); err != nil {
+		t.Fatal(err)
+	}
+	if err := issuer.Verify(c, nonce); !errors.Is(err, ErrReused) {
+		t.Errorf("segundo Verify = %v, want %v", err, ErrReused)
+	}
+}
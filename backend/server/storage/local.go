@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local guarda los archivos bajo un directorio que el servidor sirve en PublicURL
+type Local struct {
+	dir       string
+	publicURL string
+}
+
+func NewLocal(dir, publicURL string) *Local {
+	return &Local{dir: dir, publicURL: strings.TrimSuffix(publicURL, "/")}
+}
+
+func (l *Local) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("error creando directorio: %w", err)
+	}
+
+	// Se escribe a un temporal y se renombra: nunca se sirve un archivo a medias
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("error guardando archivo: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("error guardando archivo: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("error guardando archivo: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", fmt.Errorf("error guardando archivo: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("error guardando archivo: %w", err)
+	}
+
+	return l.publicURL + "/" + key, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error borrando archivo: %w", err)
+	}
+	return nil
+}
+
+// path resuelve key dentro del directorio; rechaza keys que intenten salir de él
+func (l *Local) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", fmt.Errorf("key inválida: %q", key)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(clean)), nil
+}
+
+// Handler sirve los archivos del directorio, sin listar directorios. Lo que no
+// es imagen sale como descarga y con CSP sandbox: se sirve desde el mismo
+// origen que el panel admin.
+func (l *Local) Handler() http.Handler {
+	files := http.FileServer(http.Dir(l.dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "" || strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if !isImage(mime.TypeByExtension(path.Ext(r.URL.Path))) {
+			w.Header().Set("Content-Security-Policy", "sandbox")
+			w.Header().Set("Content-Disposition", "attachment")
+		}
+		files.ServeHTTP(w, r)
+	})
+}
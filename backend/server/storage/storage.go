@@ -0,0 +1,56 @@
+// Package storage guarda los archivos subidos (fotos, rutas KML) y devuelve la URL
+// pública con que se sirven. Por defecto en el disco local; opcionalmente en un
+// bucket compatible con S3 (AWS, MinIO, Cloudflare R2...).
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// LocalPath es la ruta en que main.go sirve los archivos locales
+const LocalPath = "/uploads"
+
+// Storage guarda y borra archivos por key ("puntos/pnt_1/foto.jpg")
+type Storage interface {
+	// Put guarda data en key y devuelve la URL pública
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// isImage dice si un archivo se puede mostrar en el navegador; el resto (KML)
+// se sirve como descarga para que nunca se interprete en el origen de la app
+func isImage(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/")
+}
+
+// Config elige y configura el backend (ver config.Load)
+type Config struct {
+	Backend   string // "local" (default) o "s3"
+	LocalDir  string // Directorio de los archivos locales
+	PublicURL string // URL base con que se sirven los archivos (local: "/uploads"; s3: endpoint/bucket)
+
+	S3Endpoint  string // https://s3.us-east-1.amazonaws.com, https://<cuenta>.r2.cloudflarestorage.com...
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", "local":
+		publicURL := cfg.PublicURL
+		if publicURL == "" {
+			publicURL = LocalPath
+		}
+		return NewLocal(cfg.LocalDir, publicURL), nil
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+			return nil, fmt.Errorf("storage s3 requiere endpoint, bucket y credenciales")
+		}
+		return NewS3(cfg), nil
+	}
+	return nil, fmt.Errorf("storage desconocido: %q", cfg.Backend)
+}
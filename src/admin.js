@@ -450,12 +450,35 @@ function setupFilters() {
     puntosPage++;
     loadPuntos();
   });
+  
+  // Exportar con los filtros actuales
+  document.getElementById('export-format')?.addEventListener('change', async (e) => {
+    const format = e.target.value;
+    if (!format) return;
+    e.target.disabled = true;
+    try {
+      await adminService.exportPuntos(format, {
+        estado: puntosFilters.estado,
+        categoria: puntosFilters.categoria,
+        nivel_urgencia: puntosFilters.urgencia,
+        q: puntosFilters.search
+      });
+    } catch (error) {
+      showToast('Error al exportar: ' + error.message, 'error');
+    } finally {
+      e.target.value = '';
+      e.target.disabled = false;
+    }
+  });
 }
 
 // ==================== SOLICITUDES ====================
+let solicitudesCache = [];
+
 async function loadSolicitudes() {
   try {
     const result = await adminService.getSolicitudes(1, 50, true);
+    solicitudesCache = result.items;
     renderSolicitudesTable(result.items);
   } catch (error) {
     console.error('Error loading solicitudes:', error);
@@ -482,13 +505,16 @@ function renderSolicitudesTable(solicitudes) {
   }
   
   tbody.innerHTML = solicitudes.map(s => {
-    const datos = typeof s.datos_brutos === 'string' ? JSON.parse(s.datos_brutos) : s.datos_brutos;
-    const preview = JSON.stringify(datos).substring(0, 100) + '...';
+    const texto = s.punto ? `${s.punto.categoria}: ${s.punto.nombre}` : (s.mensaje || '');
+    const preview = texto.length > 100 ? texto.substring(0, 100) + '...' : texto;
     
     return `
       <tr>
         <td>${escapeHtml(s.origen || 'Desconocido')}</td>
-        <td class="truncate" style="max-width: 300px;">${escapeHtml(preview)}</td>
+        <td class="truncate" style="max-width: 300px;">
+          ${escapeHtml(preview)}
+          ${s.duplicado_de ? `<div><a href="#" class="text-xs text-orange-600" onclick="event.preventDefault(); window.adminActions.view('${escapeHtml(s.duplicado_de)}')">⚠️ Posible duplicado: ver punto existente</a></div>` : ''}
+        </td>
         <td>${formatDate(s.created)}</td>
         <td class="actions-cell">
           <button class="btn-action btn-view" title="Ver detalle" onclick="window.adminActions.viewSolicitud('${s.id}')">
@@ -554,6 +580,7 @@ function renderUsuariosTable(usuarios) {
       <td>
         <select class="filter-select" onchange="window.adminActions.changeRole('${u.id}', this.value)" style="padding: 0.25rem 0.5rem;">
           <option value="" ${!u.rol ? 'selected' : ''}>Sin rol</option>
+          <option value="encargado" ${u.rol === 'encargado' ? 'selected' : ''}>Encargado</option>
           <option value="verificador" ${u.rol === 'verificador' ? 'selected' : ''}>Verificador</option>
           <option value="admin" ${u.rol === 'admin' ? 'selected' : ''}>Admin</option>
           <option value="superadmin" ${u.rol === 'superadmin' ? 'selected' : ''}>Super Admin</option>
@@ -830,12 +857,14 @@ async function openEditModal(id) {
       
       const capacidadSelect = document.getElementById('edit-capacidad-estado');
       if (capacidadSelect) capacidadSelect.value = punto.capacidad_estado || '';
+      document.getElementById('edit-capacidad-total').value = punto.capacidad_total || '';
       
       const contactoNombre = document.getElementById('edit-contacto-nombre');
       if (contactoNombre) contactoNombre.value = punto.contacto_nombre || '';
       
       document.getElementById('edit-contacto').value = punto.contacto_principal || '';
       document.getElementById('edit-horario').value = punto.horario || '';
+      document.getElementById('edit-horario-osm').value = punto.horario_osm || '';
       document.getElementById('edit-necesidades').value = punto.necesidades_raw || '';
       document.getElementById('edit-notas').value = punto.notas_internas || '';
       
@@ -849,7 +878,7 @@ async function openEditModal(id) {
       if (nombreZona) nombreZona.value = punto.nombre_zona || '';
       
       const urgencia = document.getElementById('edit-urgencia');
-      if (urgencia) urgencia.value = punto.urgencia || '';
+      if (urgencia) urgencia.value = punto.nivel_urgencia || '';
       
       const habitado = document.getElementById('edit-habitado');
       if (habitado) habitado.checked = punto.habitado_actualmente || false;
@@ -899,6 +928,8 @@ async function openEditModal(id) {
       
       const requiereVoluntarios = document.getElementById('edit-requiere-voluntarios');
       if (requiereVoluntarios) requiereVoluntarios.checked = punto.requiere_voluntarios || false;
+      const cupoVoluntarios = document.getElementById('edit-cupo-voluntarios');
+      if (cupoVoluntarios) cupoVoluntarios.value = punto.cupo_voluntarios || '';
       
       const logistica = document.getElementById('edit-logistica');
       if (logistica) logistica.value = punto.logistica_llegada || '';
@@ -967,7 +998,7 @@ async function openViewModal(id) {
           <h4 style="margin: 0 0 0.5rem; color: #DC2626;">Información SOS</h4>
           <div style="display: grid; grid-template-columns: 1fr 1fr; gap: 0.5rem;">
             <div><strong>Zona:</strong> ${escapeHtml(punto.nombre_zona || '-')}</div>
-            <div><strong>Urgencia:</strong> <span class="urgencia-${punto.urgencia}">${punto.urgencia || '-'}</span></div>
+            <div><strong>Urgencia:</strong> <span class="urgencia-${punto.nivel_urgencia}">${punto.nivel_urgencia || '-'}</span></div>
             <div><strong>Habitado:</strong> ${punto.habitado_actualmente ? 'Sí' : 'No'}</div>
             <div><strong>Niños:</strong> ${punto.cantidad_ninos || 0}</div>
             <div><strong>Adultos:</strong> ${punto.cantidad_adultos || 0}</div>
@@ -976,7 +1007,7 @@ async function openViewModal(id) {
           ${punto.animales_detalle ? `<div><strong>Animales:</strong> ${escapeHtml(punto.animales_detalle)}</div>` : ''}
           <div style="display: flex; gap: 1rem; margin-top: 0.5rem;">
             ${punto.riesgo_asbesto ? '<span class="estado-tag pendiente">⚠️ Riesgo Asbesto</span>' : ''}
-            ${punto.requiere_voluntarios ? '<span class="estado-tag activo">👷 Requiere Voluntarios</span>' : ''}
+            ${punto.requiere_voluntarios ? `<span class="estado-tag activo">👷 Requiere Voluntarios${punto.cupo_voluntarios ? ` (${punto.voluntarios_asignados || 0} de ${punto.cupo_voluntarios})` : ''}</span>` : ''}
           </div>
           ${punto.logistica_llegada ? `<div style="margin-top: 0.5rem;"><strong>Logística:</strong> ${escapeHtml(punto.logistica_llegada)}</div>` : ''}
         </div>
@@ -1068,6 +1099,7 @@ async function openViewModal(id) {
     `;
     
     document.getElementById('modal-view-edit').dataset.id = id;
+    document.getElementById('modal-view-edit').style.display = '';
     modal.classList.add('show');
   } catch (error) {
     showToast('Error cargando punto', 'error');
@@ -1115,7 +1147,9 @@ async function handleEditSubmit(e) {
     contacto_nombre: document.getElementById('edit-contacto-nombre')?.value || '',
     contacto_principal: document.getElementById('edit-contacto').value,
     horario: document.getElementById('edit-horario').value,
+    horario_osm: document.getElementById('edit-horario-osm').value.trim(),
     capacidad_estado: document.getElementById('edit-capacidad-estado')?.value || '',
+    capacidad_total: parseInt(document.getElementById('edit-capacidad-total').value, 10) || 0,
     necesidades_raw: document.getElementById('edit-necesidades').value,
     necesidades_tags: necesidadesTags,
     notas_internas: document.getElementById('edit-notas').value,
@@ -1138,6 +1172,7 @@ async function handleEditSubmit(e) {
     data.tiene_electricidad = document.getElementById('edit-tiene-electricidad')?.checked || false;
     data.tiene_senal = document.getElementById('edit-tiene-senal')?.checked || false;
     data.requiere_voluntarios = document.getElementById('edit-requiere-voluntarios')?.checked || false;
+    data.cupo_voluntarios = parseInt(document.getElementById('edit-cupo-voluntarios')?.value, 10) || 0;
     data.logistica_llegada = document.getElementById('edit-logistica')?.value || '';
     
     // Categorías de ayuda y tipos de acceso (convertir de texto separado por comas a array)
@@ -1225,24 +1260,32 @@ window.adminActions = {
   },
   
   async viewSolicitud(id) {
-    try {
-      // Funcionalidad de solicitudes pendiente en el backend
-      showToast('Esta funcionalidad estará disponible próximamente', 'warning');
-      return;
-      
-      // TODO: Implementar cuando el backend tenga endpoint de solicitudes
-      // const solicitud = await adminService.getSolicitud(id);
-      // const datos = typeof solicitud.datos_brutos === 'string' 
-      //   ? JSON.parse(solicitud.datos_brutos) 
-      //   : solicitud.datos_brutos;
-      // 
-      // const content = document.getElementById('modal-view-content');
-      // content.innerHTML = ...
-      // document.getElementById('modal-view-edit').style.display = 'none';
-      // document.getElementById('modal-view').classList.add('show');
-    } catch (error) {
+    const solicitud = solicitudesCache.find(s => s.id === id);
+    if (!solicitud) {
       showToast('Error cargando solicitud', 'error');
+      return;
     }
+
+    // Si trae un punto propuesto, se revisa en el modal del punto
+    if (solicitud.punto_id) {
+      openViewModal(solicitud.punto_id);
+      return;
+    }
+
+    const content = document.getElementById('modal-view-content');
+    content.innerHTML = `
+      <div class="view-section">
+        <div><strong>Remitente:</strong> ${escapeHtml(solicitud.remitente_nombre || '-')}</div>
+        <div><strong>Contacto:</strong> ${escapeHtml(solicitud.remitente_contacto || '-')}</div>
+        <div><strong>Origen:</strong> ${escapeHtml(solicitud.origen || '-')}</div>
+        <div><strong>Recibida:</strong> ${formatDate(solicitud.created)}</div>
+      </div>
+      <div class="view-section">
+        <p style="white-space: pre-wrap;">${escapeHtml(solicitud.mensaje || '')}</p>
+      </div>
+    `;
+    document.getElementById('modal-view-edit').style.display = 'none';
+    document.getElementById('modal-view').classList.add('show');
   },
   
   async convertToPoint(id) {
@@ -1252,11 +1295,15 @@ window.adminActions = {
       async () => {
         try {
           const punto = await adminService.convertirAPunto(id);
-          showToast('Punto creado. ID: ' + punto.id, 'success');
           loadSolicitudes();
-          
-          // Open edit modal for the new punto
-          setTimeout(() => openEditModal(punto.id), 500);
+
+          if (punto.id) {
+            showToast('Punto publicado. ID: ' + punto.id, 'success');
+            // Open edit modal for the published punto
+            setTimeout(() => openEditModal(punto.id), 500);
+          } else {
+            showToast('Solicitud aprobada', 'success');
+          }
         } catch (error) {
           showToast('Error convirtiendo solicitud', 'error');
         }
@@ -1564,14 +1611,16 @@ function transformCSVRowToAPI(row, rowNum) {
   // Parse nivel_urgencia
   const urgenciaRaw = getVal(['nivel_urgencia', 'Nivel de urgencia', 'urgencia']);
   let nivelUrgencia = '';
-  if (urgenciaRaw.includes('🔴') || urgenciaRaw.toLowerCase().includes('crit')) {
-    nivelUrgencia = 'critica';
-  } else if (urgenciaRaw.includes('🟠') || urgenciaRaw.toLowerCase().includes('alta')) {
-    nivelUrgencia = 'alta';
-  } else if (urgenciaRaw.includes('🟡') || urgenciaRaw.toLowerCase().includes('media')) {
-    nivelUrgencia = 'media';
-  } else if (urgenciaRaw.includes('🟢') || urgenciaRaw.toLowerCase().includes('baja')) {
-    nivelUrgencia = 'baja';
+  // Acepta "crítico"/"crítica", "alto"/"alta", etc.; el backend usa critico, alto, medio y bajo
+  const urgenciaLower = urgenciaRaw.toLowerCase();
+  if (urgenciaRaw.includes('🔴') || urgenciaLower.includes('crit') || urgenciaLower.includes('crít')) {
+    nivelUrgencia = 'critico';
+  } else if (urgenciaRaw.includes('🟠') || urgenciaLower.includes('alt')) {
+    nivelUrgencia = 'alto';
+  } else if (urgenciaRaw.includes('🟡') || urgenciaLower.includes('medi')) {
+    nivelUrgencia = 'medio';
+  } else if (urgenciaRaw.includes('🟢') || urgenciaLower.includes('baj')) {
+    nivelUrgencia = 'bajo';
   }
   
   // Parse boolean fields
@@ -1787,7 +1836,7 @@ function downloadCSVTemplate() {
     '', '',
     'Sector Centro', 'si', '5', '10', '3', '2', '2 perros',
     'no', 'si', 'Llegar por Av. Libertad hasta el nº 1234',
-    'alta', 'alimentos,ropa,agua', '4x4,camioneta',
+    'alto', 'alimentos,ropa,agua', '4x4,camioneta',
     'si', 'si', 'no',
     '', 'Notas internas aquí'
   ];
@@ -472,7 +472,8 @@ function setupModals() {
         type: formData.get('type'),
         descripcion: formData.get('descripcion'),
         contacto: formData.get('contacto'),
-        nombre: 'SOS Ciudadano'
+        nombre: 'SOS Ciudadano',
+        website: formData.get('website')
       };
 
       console.log('Submitting SOS using repo:', repository);
@@ -484,7 +485,7 @@ function setupModals() {
       const result = await repository.submitSOS(data);
       
       if (result.success) {
-        alert('¡Alerta enviada! Aparecerá en el mapa en breve.');
+        alert('¡Alerta enviada! Aparecerá en el mapa una vez verificada.');
         closeAllModals();
         formSOS.reset();
         
@@ -520,7 +521,8 @@ function setupModals() {
       const data = {
         nombre: formData.get('origen'),
         contacto: formData.get('contacto'),
-        mensaje: formData.get('datos')
+        mensaje: formData.get('datos'),
+        website: formData.get('website')
       };
 
       const result = await repository.submitExternalRequest(data, 'web-collaborate');
@@ -273,16 +273,16 @@ export function createDetailContent(point) {
   let urgenciaHtml = '';
   if (point.nivel_urgencia) {
     const urgenciaIcons = {
-      critica: '🔴',
-      alta: '🟠',
-      media: '🟡',
-      baja: '🟢'
+      critico: '🔴',
+      alto: '🟠',
+      medio: '🟡',
+      bajo: '🟢'
     };
     const urgenciaLabels = {
-      critica: 'Crítica',
-      alta: 'Alta',
-      media: 'Media',
-      baja: 'Baja'
+      critico: 'Crítico',
+      alto: 'Alto',
+      medio: 'Medio',
+      bajo: 'Bajo'
     };
     urgenciaHtml = `
       <div class="detail-section">
@@ -342,6 +342,37 @@ export function createDetailContent(point) {
     `;
   }
 
+  // Camas disponibles en albergues (último reporte de ocupación)
+  let camasHtml = '';
+  if (point.camas_disponibles != null) {
+    const reportado = point.ocupacion_actualizada
+      ? ` · reportado ${new Date(point.ocupacion_actualizada).toLocaleString('es-CL', { dateStyle: 'short', timeStyle: 'short' })}`
+      : '';
+    camasHtml = `
+      <div class="detail-section">
+        <h4>Camas disponibles</h4>
+        <p style="color: #374151; font-size: 0.95rem; font-weight: 600;">
+          ${point.camas_disponibles > 0 ? '🛏️' : '🔴'} ${point.camas_disponibles} de ${point.capacidad_total}
+        </p>
+        <p style="color: #6B7280; font-size: 0.8rem;">${point.ocupacion_actual} personas alojadas${reportado}</p>
+      </div>
+    `;
+  }
+
+  // Voluntarios pedidos vs asignados
+  let voluntariosHtml = '';
+  if (point.cupo_voluntarios > 0) {
+    const faltan = Math.max(point.cupo_voluntarios - point.voluntarios_asignados, 0);
+    voluntariosHtml = `
+      <div class="detail-section">
+        <h4>Voluntarios</h4>
+        <p style="color: #374151; font-size: 0.95rem; font-weight: 600;">
+          🙋 ${point.voluntarios_asignados} de ${point.cupo_voluntarios}${faltan > 0 ? ` · faltan ${faltan}` : ''}
+        </p>
+      </div>
+    `;
+  }
+
   // Tiempo desde última actualización
   const updatedDate = new Date(point.updated_at);
   const now = new Date();
@@ -378,6 +409,10 @@ export function createDetailContent(point) {
     
     ${urgenciaHtml}
     
+    ${camasHtml}
+
+    ${voluntariosHtml}
+    
     ${point.address ? `
       <div class="detail-section">
         <h4>Dirección</h4>
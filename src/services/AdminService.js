@@ -159,21 +159,73 @@ class AdminService {
   // ==================== SOLICITUDES EXTERNAS ====================
 
   /**
-   * Funcionalidad de solicitudes pendiente de implementar en backend Go
+   * Cola de moderación de envíos ciudadanos (verificador, admin, superadmin)
    */
   async getSolicitudes(page = 1, perPage = 20, onlyPending = true) {
-    console.warn('⚠️ getSolicitudes: Funcionalidad pendiente de implementar');
-    return { items: [], page: 1, perPage, totalItems: 0, totalPages: 0 };
+    const params = new URLSearchParams({
+      page: page.toString(),
+      limit: perPage.toString()
+    });
+    if (onlyPending) {
+      params.append('estado', 'pendiente');
+    }
+
+    const response = await fetch(`${API_URL}/api/admin/solicitudes?${params}`, {
+      headers: authService.getAuthHeaders()
+    });
+
+    if (!response.ok) {
+      throw new Error('Error obteniendo solicitudes');
+    }
+
+    const result = await response.json();
+
+    return {
+      items: result.data || [],
+      page: result.page || 1,
+      perPage: result.limit || perPage,
+      totalItems: result.total || 0,
+      totalPages: Math.ceil((result.total || 0) / perPage)
+    };
   }
 
-  async marcarProcesada(id) {
-    console.warn('⚠️ marcarProcesada: Funcionalidad pendiente de implementar');
-    return null;
+  /**
+   * Rechaza una solicitud (oculta su punto si tenía)
+   */
+  async marcarProcesada(id, motivo = 'Descartada desde el panel') {
+    const response = await fetch(`${API_URL}/api/admin/solicitudes/${id}/rechazar`, {
+      method: 'POST',
+      headers: authService.getAuthHeaders(),
+      body: JSON.stringify({ motivo })
+    });
+
+    if (!response.ok) {
+      const errorData = await response.json().catch(() => ({}));
+      throw new Error(errorData.error || 'Error descartando solicitud');
+    }
+
+    return await response.json();
   }
 
+  /**
+   * Aprueba una solicitud y publica su punto, aplicando antes las correcciones
+   */
   async convertirAPunto(solicitudId, datosAdicionales = {}) {
-    console.warn('⚠️ convertirAPunto: Funcionalidad pendiente de implementar');
-    return null;
+    const body = Object.keys(datosAdicionales).length > 0 ? { punto: datosAdicionales } : {};
+
+    const response = await fetch(`${API_URL}/api/admin/solicitudes/${solicitudId}/aprobar`, {
+      method: 'POST',
+      headers: authService.getAuthHeaders(),
+      body: JSON.stringify(body)
+    });
+
+    if (!response.ok) {
+      const errorData = await response.json().catch(() => ({}));
+      throw new Error(errorData.error || 'Error aprobando solicitud');
+    }
+
+    const solicitud = await response.json();
+    return { id: solicitud.punto_id, solicitud };
   }
 
   // ==================== ESTADÍSTICAS ====================
@@ -194,7 +246,7 @@ class AdminService {
       pendientes: puntos.filter(p => p.estado === 'pendiente').length,
       inactivos: puntos.filter(p => p.estado === 'inactivo').length,
       cerrados: puntos.filter(p => p.estado === 'cerrado').length,
-      solicitudesPendientes: 0, // TODO: Implementar cuando exista endpoint
+      solicitudesPendientes: (await this.getSolicitudes(1, 1)).totalItems,
       
       // Por categoría con nuevos valores
       porCategoria: {
@@ -392,6 +444,36 @@ class AdminService {
     return await response.json();
   }
 
+  // ==================== EXPORTACIÓN ====================
+
+  /**
+   * Descarga los puntos filtrados como csv, kml o gpx
+   */
+  async exportPuntos(format, filters = {}) {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(filters)) {
+      if (value) params.append(key, value);
+    }
+
+    const response = await fetch(`${API_URL}/api/admin/export/puntos.${format}?${params}`, {
+      headers: authService.getAuthHeaders()
+    });
+
+    if (!response.ok) {
+      throw new Error('Error exportando puntos');
+    }
+
+    const disposition = response.headers.get('Content-Disposition') || '';
+    const filename = disposition.match(/filename="([^"]+)"/)?.[1] || `puntos.${format}`;
+
+    const url = URL.createObjectURL(await response.blob());
+    const link = document.createElement('a');
+    link.href = url;
+    link.download = filename;
+    link.click();
+    URL.revokeObjectURL(url);
+  }
+
   // ==================== IMPORTACIÓN CSV ====================
 
   /**
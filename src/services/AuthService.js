@@ -25,8 +25,13 @@ export const ROLES = {
   },
   verificador: {
     name: 'Verificador',
-    permissions: ['puntos:view', 'puntos:verify', 'solicitudes:view', 'solicitudes:process'],
+    permissions: ['puntos:view', 'puntos:verify', 'puntos:ocupacion', 'solicitudes:view', 'solicitudes:process'],
     color: 'green'
+  },
+  encargado: {
+    name: 'Encargado',
+    permissions: ['puntos:ocupacion'],
+    color: 'orange'
   }
 };
 
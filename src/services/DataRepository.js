@@ -2,12 +2,48 @@
 // Fuente de verdad: Go API -> LocalStorage (cache) -> Memoria
 
 const STORAGE_KEY = 'donde-ayudo-data';
+const DEVICE_ID_KEY = 'donde-ayudo-device-id';
 
 // URL del backend Go
 // En desarrollo usa rutas relativas (proxy de Vite)
 // En producción usa la URL base del sitio
 const API_URL = import.meta.env.VITE_API_URL || '';
 
+/**
+ * Id aleatorio del dispositivo, usado por el backend para limitar envíos por dispositivo
+ */
+function getDeviceId() {
+  let id = localStorage.getItem(DEVICE_ID_KEY);
+  if (!id) {
+    id = crypto.randomUUID();
+    localStorage.setItem(DEVICE_ID_KEY, id);
+  }
+  return id;
+}
+
+function leadingZeroBits(bytes) {
+  let n = 0;
+  for (const b of bytes) {
+    if (b !== 0) return n + Math.clz32(b) - 24;
+    n += 8;
+  }
+  return n;
+}
+
+/**
+ * Resuelve el desafío de GET /api/challenge: busca un nonce tal que
+ * SHA-256(challenge + nonce) empiece con `difficulty` bits en cero
+ */
+async function solveChallenge({ challenge, difficulty }) {
+  const encoder = new TextEncoder();
+  for (let nonce = 0; ; nonce++) {
+    const digest = await crypto.subtle.digest('SHA-256', encoder.encode(challenge + nonce));
+    if (leadingZeroBits(new Uint8Array(digest)) >= difficulty) {
+      return String(nonce);
+    }
+  }
+}
+
 /**
  * Transforma un registro de la API Go al formato que espera el frontend
  * Esto permite mantener compatibilidad con el código existente del mapa
@@ -42,6 +78,10 @@ function transformAPItoFrontend(record) {
     nivel_urgencia: record.nivel_urgencia,
     estado: record.estado,
     capacidad_estado: record.capacidad_estado,
+    capacidad_total: record.capacidad_total,
+    ocupacion_actual: record.ocupacion_actual,
+    ocupacion_actualizada: record.ocupacion_actualizada,
+    camas_disponibles: record.camas_disponibles,
     contacto_nombre: record.contacto_nombre,
     entidad_verificadora: record.entidad_verificadora,
     fecha_verificacion: record.fecha_verificacion,
@@ -61,6 +101,8 @@ function transformAPItoFrontend(record) {
     logistica_llegada: record.logistica_llegada,
     tipos_acceso: record.tipos_acceso || [],
     requiere_voluntarios: record.requiere_voluntarios,
+    cupo_voluntarios: record.cupo_voluntarios || 0,
+    voluntarios_asignados: record.voluntarios_asignados || 0,
     
     // Infraestructura
     tiene_banos: record.tiene_banos,
@@ -82,6 +124,7 @@ export class DataRepository {
   constructor() {
     this.points = [];
     this.lastUpdated = null;
+    this.syncToken = null; // next_token de /api/puntos/changes
     this.cache = new Map(); // Cache en memoria para filtros
   }
 
@@ -93,15 +136,9 @@ export class DataRepository {
     // 1. Cargar caché local inmediato (para velocidad)
     this.loadFromStorage();
     
-    // 2. Intentar actualizar desde la API
+    // 2. Intentar actualizar desde la API (solo lo que cambió desde el último sync)
     try {
-      const freshData = await this.fetchFromAPI();
-      // Solo actualizar si los datos son diferentes
-      if (freshData.length > 0) {
-        this.saveToStorage(freshData);
-        this.points = freshData;
-        this.cache.clear();
-      }
+      await this.syncChanges();
     } catch (error) {
       console.warn('⚠️ No se pudo conectar a la API, usando caché local:', error.message);
       // Si no hay datos en caché, intentar cargar el JSON estático como fallback
@@ -124,6 +161,7 @@ export class DataRepository {
         const parsed = JSON.parse(raw);
         this.points = parsed.data || [];
         this.lastUpdated = parsed.timestamp;
+        this.syncToken = parsed.syncToken || null;
         console.log(`📦 Cargados ${this.points.length} puntos desde caché local`);
       } catch (e) {
         localStorage.removeItem(STORAGE_KEY);
@@ -157,6 +195,48 @@ export class DataRepository {
     return records.map(transformAPItoFrontend);
   }
 
+  /**
+   * Sincronización incremental con /api/puntos/changes
+   * Sin token (primer uso o caché antiguo) descarga todo, paginado por el servidor
+   */
+  async syncChanges() {
+    const byId = new Map(this.points.map(p => [p.id, p]));
+    let token = this.syncToken;
+    let hasMore = true;
+    let changed = 0;
+
+    while (hasMore) {
+      const url = token
+        ? `${API_URL}/api/puntos/changes?since=${encodeURIComponent(token)}`
+        : `${API_URL}/api/puntos/changes`;
+      const response = await fetch(url);
+
+      if (!response.ok) {
+        throw new Error(`API error: ${response.status}`);
+      }
+
+      const result = await response.json();
+      [...result.created, ...result.updated].forEach(record => {
+        byId.set(record.id, transformAPItoFrontend(record));
+      });
+      result.removed.forEach(id => byId.delete(id));
+
+      changed += result.created.length + result.updated.length + result.removed.length;
+      token = result.next_token;
+      hasMore = result.has_more;
+    }
+
+    this.syncToken = token;
+    if (changed > 0) {
+      this.points = Array.from(byId.values());
+      this.cache.clear();
+    }
+    this.saveToStorage(this.points);
+
+    console.log(`🔄 Sincronizados ${changed} cambios desde API (${this.points.length} puntos)`);
+    return this.points;
+  }
+
   /**
    * Fallback: cargar desde JSON estático si PocketBase no está disponible
    */
@@ -172,6 +252,7 @@ export class DataRepository {
   saveToStorage(data) {
     const payload = {
       timestamp: new Date().toISOString(),
+      syncToken: this.syncToken,
       data: data
     };
     try {
@@ -205,14 +286,17 @@ export class DataRepository {
   }
 
   /**
-   * Envía una solicitud externa (SOS o datos de usuario)
-   * Nota: La API actual no tiene este endpoint, se puede implementar más tarde
+   * Envía una solicitud externa (datos de usuario) a la cola de moderación
    */
   async submitExternalRequest(data, origen = 'web') {
     try {
-      console.warn('⚠️ submitExternalRequest: Funcionalidad pendiente de implementar en backend Go');
-      // TODO: Implementar endpoint /api/solicitudes en backend Go
-      return { success: false, error: 'Funcionalidad no disponible temporalmente' };
+      return await this.postSubmission('/api/solicitudes', {
+        remitente_nombre: data.nombre,
+        remitente_contacto: data.contacto,
+        mensaje: data.mensaje,
+        origen,
+        website: data.website
+      });
     } catch (error) {
       console.error('❌ Error enviando solicitud:', error);
       return { success: false, error: error.message };
@@ -220,30 +304,60 @@ export class DataRepository {
   }
 
   /**
-   * Envía una alerta SOS directa al mapa (estado: revision)
-   * Requiere autenticación en el futuro
+   * Envía una alerta SOS. Queda pendiente hasta que un verificador la apruebe.
    */
   async submitSOS(data) {
     try {
-      console.warn('⚠️ submitSOS: Funcionalidad pendiente - requiere autenticación en backend Go');
-      // TODO: Implementar endpoint POST /api/puntos público o con auth opcional
-      // Por ahora los SOS se crean manualmente por admins
-      return { success: false, error: 'Los reportes SOS deben ser creados por administradores por el momento' };
+      return await this.postSubmission('/api/puntos', {
+        nombre: data.nombre,
+        latitud: data.lat,
+        longitud: data.lng,
+        categoria: 'sos',
+        subtipo: data.type,
+        necesidades_raw: data.descripcion,
+        remitente_contacto: data.contacto,
+        origen: 'web-sos',
+        website: data.website
+      });
     } catch (error) {
        console.error('❌ Error enviando SOS:', error);
        return { success: false, error: error.message };
     }
   }
 
+  async postSubmission(path, body) {
+    const headers = {
+      'Content-Type': 'application/json',
+      'X-Device-ID': getDeviceId()
+    };
+
+    // Prueba de trabajo (solo si el backend la tiene activada)
+    const challengeResponse = await fetch(`${API_URL}/api/challenge`);
+    if (challengeResponse.ok) {
+      const challenge = await challengeResponse.json();
+      if (challenge.difficulty > 0) {
+        headers['X-PoW-Challenge'] = challenge.challenge;
+        headers['X-PoW-Nonce'] = await solveChallenge(challenge);
+      }
+    }
+
+    const response = await fetch(`${API_URL}${path}`, {
+      method: 'POST',
+      headers,
+      body: JSON.stringify(body)
+    });
+    const result = await response.json().catch(() => ({}));
+    if (!response.ok) {
+      return { success: false, error: result.error || `HTTP ${response.status}` };
+    }
+    return { success: true, id: result.id };
+  }
+
   /**
    * Fuerza recarga desde la API
    */
   async refresh() {
-    const freshData = await this.fetchFromAPI();
-    this.saveToStorage(freshData);
-    this.points = freshData;
-    this.cache.clear();
-    return this.points;
+    return this.syncChanges();
   }
 }
 